@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"errors"
+	"io"
 	"net/http"
+	"strings"
 
 	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/opml"
 	"youtube-curator-v2/internal/rss"
 	"youtube-curator-v2/internal/store"
 
@@ -110,67 +116,218 @@ func (h *ChannelHandlers) ImportChannels(c echo.Context) error {
 	var imported []types.ChannelResponse
 	var failed []types.ImportFailure
 
-	ctx := context.Background()
-
 	for _, channelImport := range req.Channels {
-		if channelImport.URL == "" {
-			failed = append(failed, types.ImportFailure{
-				Channel: channelImport,
-				Error:   "URL is required",
-			})
+		channel, err := h.importChannel(c.Request().Context(), channelImport)
+		if err != nil {
+			failed = append(failed, types.ImportFailure{Channel: channelImport, Error: err.Error()})
 			continue
 		}
+		imported = append(imported, types.TransformChannel(*channel))
+	}
 
-		// Extract channel ID from URL, with yt-dlp fallback for @username, /c/, /user/ URLs
-		channelID, err := extractChannelIDWithYtdlpFallback(c.Request().Context(), h.ytdlpEnricher, channelImport.URL)
+	return importResponse(c, imported, nil, failed)
+}
+
+// ImportChannelsOPML handles POST /api/channels/import/opml
+// Accepts an OPML subscription list, as the multipart form field "file" or as the request body,
+// and adds every YouTube channel feed it lists. Channels that are already configured, or listed
+// twice, are skipped.
+func (h *ChannelHandlers) ImportChannelsOPML(c echo.Context) error {
+	data, err := readImportFile(c)
+	if err != nil {
+		return err
+	}
+
+	doc, err := opml.Parse(bytes.NewReader(data))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid OPML: "+err.Error())
+	}
+	feeds := doc.Feeds()
+	if len(feeds) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "OPML lists no feeds")
+	}
+
+	existing, err := h.configuredChannels()
+	if err != nil {
+		return err
+	}
+
+	var imported, skipped []types.ChannelResponse
+	var failed []types.ImportFailure
+
+	for _, outline := range feeds {
+		channelImport := types.ChannelImport{URL: strings.TrimSpace(outline.XMLURL), Title: outline.Name()}
+		channelID, err := rss.ExtractChannelID(channelImport.URL)
+		if err != nil {
+			failed = append(failed, types.ImportFailure{Channel: channelImport, Error: err.Error()})
+			continue
+		}
+		if channel, ok := existing[channelID]; ok {
+			skipped = append(skipped, types.TransformChannel(channel))
+			continue
+		}
+
+		channel, err := h.addChannel(c.Request().Context(), channelID, channelImport.Title)
 		if err != nil {
-			failed = append(failed, types.ImportFailure{
-				Channel: channelImport,
-				Error:   err.Error(),
-			})
+			failed = append(failed, types.ImportFailure{Channel: channelImport, Error: err.Error()})
 			continue
 		}
+		existing[channel.ID] = *channel
+		imported = append(imported, types.TransformChannel(*channel))
+	}
 
-		title := channelImport.Title
-		if title == "" {
-			// Fetch title from RSS feed
-			feed, err := h.feedProvider.FetchFeed(ctx, channelID)
-			if err != nil {
-				failed = append(failed, types.ImportFailure{
-					Channel: channelImport,
-					Error:   "Could not fetch channel title from RSS feed: " + err.Error(),
-				})
-				continue
-			}
-			title = feed.Title
-			if title == "" {
-				failed = append(failed, types.ImportFailure{
-					Channel: channelImport,
-					Error:   "Channel title could not be determined from RSS feed",
-				})
-				continue
-			}
+	return importResponse(c, imported, skipped, failed)
+}
+
+// ImportChannelsTakeout handles POST /api/channels/import/takeout
+// Accepts the subscriptions.csv of a Google Takeout export, as the multipart form field "file" or
+// as the request body. Rows hold the channel ID, URL and title; channels that are already
+// configured, or listed twice, are skipped.
+func (h *ChannelHandlers) ImportChannelsTakeout(c echo.Context) error {
+	data, err := readImportFile(c)
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\uFEFF"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid CSV: "+err.Error())
+	}
+	// The header row's names depend on the account's language, so it is recognised by not
+	// looking like a channel ID at all. A first row with a malformed ID is reported as a failure.
+	if len(records) > 0 && !strings.HasPrefix(strings.TrimSpace(records[0][0]), "UC") {
+		records = records[1:]
+	}
+	if len(records) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "CSV lists no subscriptions")
+	}
+
+	existing, err := h.configuredChannels()
+	if err != nil {
+		return err
+	}
+
+	var imported, skipped []types.ChannelResponse
+	var failed []types.ImportFailure
+
+	for _, record := range records {
+		channelID := strings.TrimSpace(record[0])
+		channelImport := types.ChannelImport{URL: channelID}
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			channelImport.URL = strings.TrimSpace(record[1])
+		}
+		if len(record) > 2 {
+			channelImport.Title = strings.TrimSpace(record[2])
 		}
 
-		channel := store.Channel{ID: channelID, Title: title}
-		if err := h.store.AddChannel(channel); err != nil {
-			failed = append(failed, types.ImportFailure{
-				Channel: channelImport,
-				Error:   "Failed to add channel to database: " + err.Error(),
-			})
+		if err := rss.ValidateChannelID(channelID); err != nil {
+			failed = append(failed, types.ImportFailure{Channel: channelImport, Error: err.Error()})
+			continue
+		}
+		if channel, ok := existing[channelID]; ok {
+			skipped = append(skipped, types.TransformChannel(channel))
 			continue
 		}
 
-		storeChannel := store.Channel{ID: channelID, Title: title}
-		imported = append(imported, types.TransformChannel(storeChannel))
+		channel, err := h.addChannel(c.Request().Context(), channelID, channelImport.Title)
+		if err != nil {
+			failed = append(failed, types.ImportFailure{Channel: channelImport, Error: err.Error()})
+			continue
+		}
+		existing[channel.ID] = *channel
+		imported = append(imported, types.TransformChannel(*channel))
 	}
 
+	return importResponse(c, imported, skipped, failed)
+}
+
+// ExportChannelsOPML handles GET /api/channels/export/opml
+// Returns the configured channels as an OPML subscription list of their feeds
+func (h *ChannelHandlers) ExportChannelsOPML(c echo.Context) error {
+	channels, err := h.store.GetChannels()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve channels")
+	}
+
+	outlines := make([]opml.Outline, 0, len(channels))
+	for _, channel := range channels {
+		outlines = append(outlines, opml.Outline{
+			Text:    channel.Title,
+			Title:   channel.Title,
+			Type:    "rss",
+			XMLURL:  rss.ChannelFeedURL(channel.ID),
+			HTMLURL: "https://www.youtube.com/channel/" + channel.ID,
+		})
+	}
+
+	body, err := opml.New("YouTube Curator subscriptions", outlines).Marshal()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to render OPML")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="subscriptions.opml"`)
+	return c.Blob(http.StatusOK, opml.ContentType, body)
+}
+
+// importChannel adds a channel given by URL or ID, resolving @username, /c/ and /user/ URLs with
+// yt-dlp and fetching the title from the channel's feed when none is given
+func (h *ChannelHandlers) importChannel(ctx context.Context, channelImport types.ChannelImport) (*store.Channel, error) {
+	if channelImport.URL == "" {
+		return nil, errors.New("URL is required")
+	}
+
+	channelID, err := extractChannelIDWithYtdlpFallback(ctx, h.ytdlpEnricher, channelImport.URL)
+	if err != nil {
+		return nil, err
+	}
+	return h.addChannel(ctx, channelID, channelImport.Title)
+}
+
+// addChannel adds a channel by ID, fetching the title from the channel's feed when none is given
+func (h *ChannelHandlers) addChannel(ctx context.Context, channelID string, title string) (*store.Channel, error) {
+	if title == "" {
+		feed, err := h.feedProvider.FetchFeed(ctx, channelID)
+		if err != nil {
+			return nil, errors.New("Could not fetch channel title from RSS feed: " + err.Error())
+		}
+		title = feed.Title
+		if title == "" {
+			return nil, errors.New("Channel title could not be determined from RSS feed")
+		}
+	}
+
+	channel := store.Channel{ID: channelID, Title: title}
+	if err := h.store.AddChannel(channel); err != nil {
+		return nil, errors.New("Failed to add channel to database: " + err.Error())
+	}
+	return &channel, nil
+}
+
+// configuredChannels returns the configured channels by ID, so imports can skip them
+func (h *ChannelHandlers) configuredChannels() (map[string]store.Channel, error) {
+	channels, err := h.store.GetChannels()
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve channels")
+	}
+	existing := make(map[string]store.Channel, len(channels))
+	for _, channel := range channels {
+		existing[channel.ID] = channel
+	}
+	return existing, nil
+}
+
+// importResponse reports an import, with 207 Multi-Status if there were any failures and 201 if
+// all succeeded. Skipped channels were already configured; they are listed as imported, with
+// their stored details, since they are in place after the import all the same.
+func importResponse(c echo.Context, imported, skipped []types.ChannelResponse, failed []types.ImportFailure) error {
 	response := types.ImportChannelsResponse{
-		Imported: imported,
+		Imported: append(imported, skipped...),
 		Failed:   failed,
 	}
 
-	// Return 207 Multi-Status if there were any failures, 201 if all succeeded
 	statusCode := http.StatusCreated
 	if len(failed) > 0 {
 		statusCode = http.StatusMultiStatus
@@ -178,3 +335,36 @@ func (h *ChannelHandlers) ImportChannels(c echo.Context) error {
 
 	return c.JSON(statusCode, response)
 }
+
+// maxImportFileSize bounds uploaded subscription files
+const maxImportFileSize = 5 << 20
+
+// readImportFile reads an uploaded subscription file, sent either as the multipart form field
+// "file" or as the raw request body
+func readImportFile(c echo.Context) ([]byte, error) {
+	var r io.Reader = c.Request().Body
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "A file is required in the file field")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to read uploaded file")
+		}
+		defer file.Close()
+		r = file
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxImportFileSize+1))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to read uploaded file")
+	}
+	if len(data) > maxImportFileSize {
+		return nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge, "File must be at most 5 MB")
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "File is empty")
+	}
+	return data, nil
+}
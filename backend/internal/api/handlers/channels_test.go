@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/opml"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+)
+
+// stubFeedProvider returns feeds titled after the channel ID, failing for unknown channels
+type stubFeedProvider struct {
+	titles map[string]string
+}
+
+func (p *stubFeedProvider) FetchFeed(ctx context.Context, channelID string) (*rss.Feed, error) {
+	title, ok := p.titles[channelID]
+	if !ok {
+		return nil, errors.New("feed not found")
+	}
+	return &rss.Feed{Title: title}, nil
+}
+
+const testOPML = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="YouTube">
+      <outline text="Titled" type="rss" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UCAYF6ZY9gWBR1GW3R7PX7yw"/>
+      <outline text="" type="rss" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UCrAhw9Z8NI6GzO2WnvhYzCg"/>
+      <outline text="Old Name" type="rss" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UCxhfCYSc5IE1Uy5GKfOcfyw"/>
+      <outline text="Titled Again" type="rss" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UCAYF6ZY9gWBR1GW3R7PX7yw"/>
+    </outline>
+    <outline text="Blog" type="rss" xmlUrl="https://blog.example.com/feed.xml"/>
+    <outline text="Broken" type="rss" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=invalid"/>
+  </body>
+</opml>`
+
+func TestImportChannelsOPML(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCxhfCYSc5IE1Uy5GKfOcfyw", Title: "Stored Title"}}, nil)
+	var added []store.Channel
+	mockStore.EXPECT().AddChannel(gomock.Any()).DoAndReturn(func(channel store.Channel) error {
+		added = append(added, channel)
+		return nil
+	}).Times(2)
+	feedProvider := &stubFeedProvider{titles: map[string]string{"UCrAhw9Z8NI6GzO2WnvhYzCg": "Fetched Title"}}
+	handler := NewChannelHandlers(&BaseHandlers{store: mockStore, feedProvider: feedProvider})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/channels/import/opml", strings.NewReader(testOPML))
+	req.Header.Set(echo.HeaderContentType, "text/x-opml")
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler.ImportChannelsOPML(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusMultiStatus, rec.Code)
+
+	var response types.ImportChannelsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Imported, 4)
+	assert.Equal(t, "UCAYF6ZY9gWBR1GW3R7PX7yw", response.Imported[0].ID)
+	assert.Equal(t, "Titled", response.Imported[0].Title)
+	assert.Equal(t, "Fetched Title", response.Imported[1].Title, "Untitled outlines take the feed's title")
+	// Configured channels and repeated feeds are skipped, and reported with their stored title
+	assert.Equal(t, "Stored Title", response.Imported[2].Title)
+	assert.Equal(t, "Titled", response.Imported[3].Title)
+	assert.Equal(t, []store.Channel{{ID: "UCAYF6ZY9gWBR1GW3R7PX7yw", Title: "Titled"}, {ID: "UCrAhw9Z8NI6GzO2WnvhYzCg", Title: "Fetched Title"}}, added)
+
+	require.Len(t, response.Failed, 2)
+	assert.Equal(t, "https://blog.example.com/feed.xml", response.Failed[0].Channel.URL)
+	assert.Equal(t, "Blog", response.Failed[0].Channel.Title)
+	assert.Equal(t, "Broken", response.Failed[1].Channel.Title)
+	assert.NotEmpty(t, response.Failed[1].Error)
+}
+
+func TestImportChannelsOPML_MultipartUpload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return(nil, nil)
+	mockStore.EXPECT().AddChannel(store.Channel{ID: "UCAYF6ZY9gWBR1GW3R7PX7yw", Title: "Go Time"}).Return(nil)
+	handler := NewChannelHandlers(&BaseHandlers{store: mockStore})
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "subscriptions.opml")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(`<opml version="2.0"><body><outline text="Go Time" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UCAYF6ZY9gWBR1GW3R7PX7yw"/></body></opml>`))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/channels/import/opml", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler.ImportChannelsOPML(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestImportChannelsOPML_Invalid(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "empty body", body: "  ", expectedStatus: http.StatusBadRequest},
+		{name: "not OPML", body: `{"channels":[]}`, expectedStatus: http.StatusBadRequest},
+		{name: "no feeds", body: `<opml version="2.0"><body><outline text="Folder"/></body></opml>`, expectedStatus: http.StatusBadRequest},
+		{name: "too large", body: strings.Repeat(" ", maxImportFileSize+1), expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewChannelHandlers(&BaseHandlers{})
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/channels/import/opml", strings.NewReader(tt.body))
+			err := handler.ImportChannelsOPML(e.NewContext(req, httptest.NewRecorder()))
+
+			require.Error(t, err)
+			httpErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedStatus, httpErr.Code)
+		})
+	}
+}
+
+func TestExportChannelsOPML(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{
+		{ID: "UCAYF6ZY9gWBR1GW3R7PX7yw", Title: "Go Time"},
+		{ID: "UCrAhw9Z8NI6GzO2WnvhYzCg", Title: "Rust & Friends"},
+	}, nil)
+	handler := NewChannelHandlers(&BaseHandlers{store: mockStore})
+
+	c, rec := newSubscriberContext(http.MethodGet, "/api/channels/export/opml", "")
+	require.NoError(t, handler.ExportChannelsOPML(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, opml.ContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "subscriptions.opml")
+
+	doc, err := opml.Parse(rec.Body)
+	require.NoError(t, err)
+	feeds := doc.Feeds()
+	require.Len(t, feeds, 2)
+	assert.Equal(t, "Rust & Friends", feeds[1].Name())
+	assert.Equal(t, "https://www.youtube.com/channel/UCrAhw9Z8NI6GzO2WnvhYzCg", feeds[1].HTMLURL)
+
+	// Exported feed URLs import back to the same channels
+	for i, feed := range feeds {
+		channelID, err := rss.ExtractChannelID(feed.XMLURL)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"UCAYF6ZY9gWBR1GW3R7PX7yw", "UCrAhw9Z8NI6GzO2WnvhYzCg"}[i], channelID)
+	}
+}
+
+const testTakeoutCSV = "\uFEFFChannel Id,Channel Url,Channel Title\n" +
+	"UCAYF6ZY9gWBR1GW3R7PX7yw,http://www.youtube.com/channel/UCAYF6ZY9gWBR1GW3R7PX7yw,Go Time\n" +
+	"UCrAhw9Z8NI6GzO2WnvhYzCg,http://www.youtube.com/channel/UCrAhw9Z8NI6GzO2WnvhYzCg,\n" +
+	"UCxhfCYSc5IE1Uy5GKfOcfyw,http://www.youtube.com/channel/UCxhfCYSc5IE1Uy5GKfOcfyw,Already Here\n" +
+	"\n" +
+	"UCAYF6ZY9gWBR1GW3R7PX7yw,http://www.youtube.com/channel/UCAYF6ZY9gWBR1GW3R7PX7yw,Go Time\n" +
+	"invalid,http://www.youtube.com/channel/invalid,\"Broken, Channel\"\n"
+
+func TestImportChannelsTakeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCxhfCYSc5IE1Uy5GKfOcfyw", Title: "Already Here"}}, nil)
+	var added []store.Channel
+	mockStore.EXPECT().AddChannel(gomock.Any()).DoAndReturn(func(channel store.Channel) error {
+		added = append(added, channel)
+		return nil
+	}).Times(2)
+	feedProvider := &stubFeedProvider{titles: map[string]string{"UCrAhw9Z8NI6GzO2WnvhYzCg": "Fetched Title"}}
+	handler := NewChannelHandlers(&BaseHandlers{store: mockStore, feedProvider: feedProvider})
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "subscriptions.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(testTakeoutCSV))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/channels/import/takeout", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler.ImportChannelsTakeout(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusMultiStatus, rec.Code)
+
+	var response types.ImportChannelsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, []store.Channel{{ID: "UCAYF6ZY9gWBR1GW3R7PX7yw", Title: "Go Time"}, {ID: "UCrAhw9Z8NI6GzO2WnvhYzCg", Title: "Fetched Title"}}, added)
+	require.Len(t, response.Imported, 4)
+	assert.Equal(t, "Fetched Title", response.Imported[1].Title, "Untitled rows take the feed's title")
+	// Configured channels and repeated rows are skipped, and reported as they are stored
+	assert.Equal(t, "UCxhfCYSc5IE1Uy5GKfOcfyw", response.Imported[2].ID)
+	assert.Equal(t, "UCAYF6ZY9gWBR1GW3R7PX7yw", response.Imported[3].ID)
+
+	require.Len(t, response.Failed, 1)
+	assert.Equal(t, "http://www.youtube.com/channel/invalid", response.Failed[0].Channel.URL)
+	assert.Equal(t, "Broken, Channel", response.Failed[0].Channel.Title)
+	assert.NotEmpty(t, response.Failed[0].Error)
+}
+
+func TestImportChannelsTakeout_AllPresent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCAYF6ZY9gWBR1GW3R7PX7yw", Title: "Go Time"}}, nil).Times(2)
+	handler := NewChannelHandlers(&BaseHandlers{store: mockStore})
+
+	// Headers are localized, and a header-less file is accepted too
+	for _, body := range []string{
+		"Kanal-ID,Kanal-URL,Kanaltitel\nUCAYF6ZY9gWBR1GW3R7PX7yw,,Go Time\n",
+		"UCAYF6ZY9gWBR1GW3R7PX7yw\n",
+	} {
+		c, rec := newSubscriberContext(http.MethodPost, "/api/channels/import/takeout", body)
+		require.NoError(t, handler.ImportChannelsTakeout(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var response types.ImportChannelsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Imported, 1)
+		assert.Equal(t, "Go Time", response.Imported[0].Title)
+		assert.Empty(t, response.Failed)
+	}
+}
+
+func TestImportChannelsTakeout_MalformedFirstRow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCAYF6ZY9gWBR1GW3R7PX7yw", Title: "Go Time"}}, nil)
+	handler := NewChannelHandlers(&BaseHandlers{store: mockStore})
+
+	// A first row that looks like a channel ID is a subscription, not a header, even if the ID is malformed
+	c, rec := newSubscriberContext(http.MethodPost, "/api/channels/import/takeout", "UCtooshort,,Typo\nUCAYF6ZY9gWBR1GW3R7PX7yw,,Go Time\n")
+	require.NoError(t, handler.ImportChannelsTakeout(c))
+	assert.Equal(t, http.StatusMultiStatus, rec.Code)
+
+	var response types.ImportChannelsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Failed, 1)
+	assert.Equal(t, "Typo", response.Failed[0].Channel.Title)
+	require.Len(t, response.Imported, 1)
+}
+
+func TestImportChannelsTakeout_Invalid(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "empty body", body: "", expectedStatus: http.StatusBadRequest},
+		{name: "header only", body: "Channel Id,Channel Url,Channel Title\n", expectedStatus: http.StatusBadRequest},
+		{name: "malformed CSV", body: "Channel Id,Channel Url,Channel Title\nUCAYF6ZY9gWBR1GW3R7PX7yw,\"unterminated\n", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewChannelHandlers(&BaseHandlers{})
+
+			c, _ := newSubscriberContext(http.MethodPost, "/api/channels/import/takeout", tt.body)
+			err := handler.ImportChannelsTakeout(c)
+
+			require.Error(t, err)
+			httpErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedStatus, httpErr.Code)
+		})
+	}
+}
@@ -1,24 +1,43 @@
 package handlers
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"net/mail"
 	"strings"
 	"time"
 
 	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/customerrors"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/http/retry"
+	"youtube-curator-v2/internal/openai"
+	"youtube-curator-v2/internal/scheduler"
 	"youtube-curator-v2/internal/store"
 
 	"github.com/labstack/echo/v4"
 )
 
+// llmTestTimeout bounds the completion made by POST /api/config/llm/test
+const llmTestTimeout = 30 * time.Second
+
 // ConfigHandlers provides handlers for configuration management endpoints
 type ConfigHandlers struct {
 	*BaseHandlers
+	scheduler *scheduler.Scheduler                        // Rescheduled when the interval or newsletter configuration changes; may be nil
+	newSender func(config *store.SMTPConfig) email.Sender // Builds the sender used by POST /api/config/smtp/test
 }
 
 // NewConfigHandlers creates a new instance of config handlers
-func NewConfigHandlers(base *BaseHandlers) *ConfigHandlers {
-	return &ConfigHandlers{BaseHandlers: base}
+func NewConfigHandlers(base *BaseHandlers, scheduler *scheduler.Scheduler) *ConfigHandlers {
+	return &ConfigHandlers{
+		BaseHandlers: base,
+		scheduler:    scheduler,
+		newSender: func(config *store.SMTPConfig) email.Sender {
+			return email.NewEmailSenderFromConfig(config)
+		},
+	}
 }
 
 // GetCheckInterval handles GET /api/config/interval
@@ -60,6 +79,7 @@ func (h *ConfigHandlers) SetCheckInterval(c echo.Context) error {
 	if err := h.store.SetCheckInterval(duration); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to set check interval")
 	}
+	h.reschedule()
 
 	return c.JSON(http.StatusOK, types.ConfigInterval{Interval: duration.String()})
 }
@@ -79,15 +99,7 @@ func (h *ConfigHandlers) GetSMTPConfig(c echo.Context) error {
 	}
 
 	// Return config without password
-	response := types.SMTPConfigResponse{
-		Server:         config.Server,
-		Port:           config.Port,
-		Username:       config.Username,
-		RecipientEmail: config.RecipientEmail,
-		PasswordSet:    config.Password != "",
-	}
-
-	return c.JSON(http.StatusOK, response)
+	return c.JSON(http.StatusOK, types.TransformSMTPConfig(config))
 }
 
 // SetSMTPConfig handles PUT /api/config/smtp
@@ -97,52 +109,110 @@ func (h *ConfigHandlers) SetSMTPConfig(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
 	}
 
+	smtpConfig, err := smtpConfigFromRequest(req)
+	if err != nil {
+		return err
+	}
+
+	// Save to store
+	if err := h.store.SetSMTPConfig(smtpConfig); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save SMTP configuration")
+	}
+
+	// Return response without password
+	return c.JSON(http.StatusOK, types.TransformSMTPConfig(smtpConfig))
+}
+
+// TestSMTPConfig handles POST /api/config/smtp/test
+// It sends a test email to the recipient using the submitted settings without saving them.
+// A blank password reuses the saved one when the server and username are unchanged.
+func (h *ConfigHandlers) TestSMTPConfig(c echo.Context) error {
+	var req types.SMTPConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.Password == "" && req.Username != "" {
+		saved, err := h.store.GetSMTPConfig()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve SMTP configuration")
+		}
+		if saved != nil && saved.Server == req.Server && saved.Username == req.Username {
+			req.Password = saved.Password
+		}
+	}
+
+	smtpConfig, err := smtpConfigFromRequest(req)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	sendErr := h.newSender(smtpConfig).Send(smtpConfig.RecipientEmail, "YouTube Curator test email", email.FormatTestEmail(start))
+	response := types.SMTPTestResponse{
+		Success:    sendErr == nil,
+		Recipient:  smtpConfig.RecipientEmail,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if sendErr != nil {
+		response.Message = "Failed to send test email"
+		response.Error = sendErr.Error()
+	} else {
+		response.Message = "Test email sent to " + smtpConfig.RecipientEmail
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// smtpConfigFromRequest validates an SMTP configuration request. A username and password are
+// only needed for authenticated servers; unauthenticated relays need an explicit From address.
+func smtpConfigFromRequest(req types.SMTPConfigRequest) (*store.SMTPConfig, error) {
 	// Validate required fields
 	if req.Server == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Server is required")
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Server is required")
 	}
 	if req.Port == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Port is required")
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Port is required")
 	}
-	if req.Username == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Username is required")
+	if req.Username != "" && req.Password == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Password is required")
 	}
-	if req.Password == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Password is required")
+	if req.Username == "" && req.FromAddress == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "From address is required without a username")
 	}
 	if req.RecipientEmail == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Recipient email is required")
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Recipient email is required")
 	}
 
 	// Basic email validation
 	if !strings.Contains(req.RecipientEmail, "@") {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid recipient email format")
-	}
-
-	// Create SMTP config
-	smtpConfig := &store.SMTPConfig{
-		Server:         req.Server,
-		Port:           req.Port,
-		Username:       req.Username,
-		Password:       req.Password,
-		RecipientEmail: req.RecipientEmail,
-	}
-
-	// Save to store
-	if err := h.store.SetSMTPConfig(smtpConfig); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save SMTP configuration")
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid recipient email format")
 	}
-
-	// Return response without password
-	response := types.SMTPConfigResponse{
-		Server:         req.Server,
-		Port:           req.Port,
-		Username:       req.Username,
-		RecipientEmail: req.RecipientEmail,
-		PasswordSet:    true,
+	if req.FromAddress != "" {
+		if _, err := mail.ParseAddress(req.FromAddress); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid from address format")
+		}
 	}
 
-	return c.JSON(http.StatusOK, response)
+	tlsMode, err := email.ParseTLSMode(req.TLSMode)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := email.ValidateAuthTLS(req.Server, tlsMode, req.Username); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return &store.SMTPConfig{
+		Server:             req.Server,
+		Port:               req.Port,
+		Username:           req.Username,
+		Password:           req.Password,
+		RecipientEmail:     req.RecipientEmail,
+		FromAddress:        req.FromAddress,
+		FromName:           strings.TrimSpace(req.FromName),
+		TLSMode:            string(tlsMode),
+		InsecureSkipVerify: req.InsecureSkipVerify,
+	}, nil
 }
 
 // GetLLMConfig handles GET /api/config/llm
@@ -176,22 +246,9 @@ func (h *ConfigHandlers) SetLLMConfig(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
 	}
 
-	// Validate required fields
-	if req.EndpointURL == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Endpoint URL is required")
-	}
-	if req.APIKey == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "API key is required")
-	}
-	if req.Model == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Model is required")
-	}
-
-	// Create LLM config
-	llmConfig := &store.LLMConfig{
-		EndpointURL: req.EndpointURL,
-		APIKey:      req.APIKey,
-		Model:       req.Model,
+	llmConfig, err := llmConfigFromRequest(req)
+	if err != nil {
+		return err
 	}
 
 	// Save to store
@@ -209,6 +266,77 @@ func (h *ConfigHandlers) SetLLMConfig(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// TestLLMConfig handles POST /api/config/llm/test
+// It runs a tiny chat completion with the submitted settings without saving them, reporting
+// the latency and, on failure, a category for the error. A blank API key reuses the saved
+// one when the endpoint is unchanged.
+func (h *ConfigHandlers) TestLLMConfig(c echo.Context) error {
+	var req types.LLMConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.APIKey == "" {
+		saved, err := h.store.GetLLMConfig()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve LLM configuration")
+		}
+		if saved != nil && saved.EndpointURL == req.EndpointURL {
+			req.APIKey = saved.APIKey
+		}
+	}
+
+	llmConfig, err := llmConfigFromRequest(req)
+	if err != nil {
+		return err
+	}
+
+	client := openai.New(llmConfig.EndpointURL, llmConfig.APIKey, llmConfig.Model)
+	client.SetRetryConfig(retry.RetryConfig{MaxRetries: 0})
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), llmTestTimeout)
+	defer cancel()
+
+	results := make(chan customerrors.ErrorString, 1)
+	start := time.Now()
+	client.ChatCompletion(ctx, "You are a connectivity check. Reply with the single word: pong", []string{"ping"}, nil, nil, 0, 5, results)
+	result := <-results
+
+	response := types.LLMTestResponse{
+		Success:   result.Err == nil,
+		Model:     client.GetModelName(),
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if result.Err != nil {
+		response.Error = result.Err.Error()
+		response.ErrorCategory = openai.ErrorCategory(result.Err)
+	} else {
+		response.Reply = strings.TrimSpace(result.Value)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// llmConfigFromRequest validates an LLM configuration request
+func llmConfigFromRequest(req types.LLMConfigRequest) (*store.LLMConfig, error) {
+	// Validate required fields
+	if req.EndpointURL == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Endpoint URL is required")
+	}
+	if req.APIKey == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "API key is required")
+	}
+	if req.Model == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Model is required")
+	}
+
+	return &store.LLMConfig{
+		EndpointURL: req.EndpointURL,
+		APIKey:      req.APIKey,
+		Model:       req.Model,
+	}, nil
+}
+
 // GetNewsletterConfig handles GET /api/config/newsletter
 func (h *ConfigHandlers) GetNewsletterConfig(c echo.Context) error {
 	config, err := h.store.GetNewsletterConfig()
@@ -217,7 +345,10 @@ func (h *ConfigHandlers) GetNewsletterConfig(c echo.Context) error {
 	}
 
 	response := types.NewsletterConfigResponse{
-		Enabled: config.Enabled,
+		Enabled:             config.Enabled,
+		MaxVideosPerChannel: config.MaxVideosPerChannel,
+		IncludeSummaries:    config.IncludeSummaries,
+		CronSchedule:        config.CronSchedule,
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -230,19 +361,47 @@ func (h *ConfigHandlers) SetNewsletterConfig(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
 	}
 
+	if req.MaxVideosPerChannel < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "maxVideosPerChannel must be non-negative")
+	}
+
+	cronSchedule := strings.TrimSpace(req.CronSchedule)
+	if cronSchedule != "" {
+		if err := scheduler.ValidateCronSchedule(cronSchedule); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
 	// Create newsletter config
 	newsletterConfig := &store.NewsletterConfig{
-		Enabled: req.Enabled,
+		Enabled:             req.Enabled,
+		MaxVideosPerChannel: req.MaxVideosPerChannel,
+		IncludeSummaries:    req.IncludeSummaries,
+		CronSchedule:        cronSchedule,
 	}
 
 	// Save to store
 	if err := h.store.SetNewsletterConfig(newsletterConfig); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save newsletter configuration")
 	}
+	h.reschedule()
 
 	response := types.NewsletterConfigResponse{
-		Enabled: req.Enabled,
+		Enabled:             req.Enabled,
+		MaxVideosPerChannel: req.MaxVideosPerChannel,
+		IncludeSummaries:    req.IncludeSummaries,
+		CronSchedule:        cronSchedule,
 	}
 
 	return c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// reschedule applies a changed schedule or enabled flag to the running scheduler
+func (h *ConfigHandlers) reschedule() {
+	if h.scheduler == nil {
+		return
+	}
+	if err := h.scheduler.Reload(); err != nil {
+		log.Printf("Warning: Failed to reschedule newsletter: %v", err)
+	}
+}
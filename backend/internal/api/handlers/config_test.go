@@ -6,12 +6,16 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/scheduler"
 	"youtube-curator-v2/internal/store"
 )
 
@@ -34,6 +38,21 @@ func TestGetNewsletterConfig(t *testing.T) {
 				Enabled: true,
 			},
 		},
+		{
+			name: "Success - Newsletter with per-channel cap and summaries",
+			mockReturn: &store.NewsletterConfig{
+				Enabled:             true,
+				MaxVideosPerChannel: 5,
+				IncludeSummaries:    true,
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+			expectedBody: types.NewsletterConfigResponse{
+				Enabled:             true,
+				MaxVideosPerChannel: 5,
+				IncludeSummaries:    true,
+			},
+		},
 		{
 			name: "Success - Newsletter disabled",
 			mockReturn: &store.NewsletterConfig{
@@ -61,7 +80,7 @@ func TestGetNewsletterConfig(t *testing.T) {
 
 			mockStore := store.NewMockStore(ctrl)
 			baseHandlers := &BaseHandlers{store: mockStore}
-			handler := NewConfigHandlers(baseHandlers)
+			handler := NewConfigHandlers(baseHandlers, nil)
 			e := echo.New()
 
 			// Setup mock
@@ -113,6 +132,45 @@ func TestSetNewsletterConfig(t *testing.T) {
 				Enabled: true,
 			},
 		},
+		{
+			name: "Success - Cap videos per channel",
+			requestBody: types.NewsletterConfigRequest{
+				Enabled:             true,
+				MaxVideosPerChannel: 3,
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+			expectedBody: types.NewsletterConfigResponse{
+				Enabled:             true,
+				MaxVideosPerChannel: 3,
+			},
+		},
+		{
+			name: "Success - Include summaries",
+			requestBody: types.NewsletterConfigRequest{
+				Enabled:          true,
+				IncludeSummaries: true,
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+			expectedBody: types.NewsletterConfigResponse{
+				Enabled:          true,
+				IncludeSummaries: true,
+			},
+		},
+		{
+			name: "Success - Cron schedule",
+			requestBody: types.NewsletterConfigRequest{
+				Enabled:      true,
+				CronSchedule: "30 7 * * 1-5",
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusOK,
+			expectedBody: types.NewsletterConfigResponse{
+				Enabled:      true,
+				CronSchedule: "30 7 * * 1-5",
+			},
+		},
 		{
 			name: "Success - Disable newsletter",
 			requestBody: types.NewsletterConfigRequest{
@@ -142,12 +200,15 @@ func TestSetNewsletterConfig(t *testing.T) {
 
 			mockStore := store.NewMockStore(ctrl)
 			baseHandlers := &BaseHandlers{store: mockStore}
-			handler := NewConfigHandlers(baseHandlers)
+			handler := NewConfigHandlers(baseHandlers, nil)
 			e := echo.New()
 
 			// Setup mock
 			expectedConfig := &store.NewsletterConfig{
-				Enabled: tt.requestBody.Enabled,
+				Enabled:             tt.requestBody.Enabled,
+				MaxVideosPerChannel: tt.requestBody.MaxVideosPerChannel,
+				IncludeSummaries:    tt.requestBody.IncludeSummaries,
+				CronSchedule:        tt.requestBody.CronSchedule,
 			}
 			mockStore.EXPECT().SetNewsletterConfig(expectedConfig).Return(tt.mockError)
 
@@ -187,7 +248,7 @@ func TestSetNewsletterConfig_InvalidJSON(t *testing.T) {
 
 	mockStore := store.NewMockStore(ctrl)
 	baseHandlers := &BaseHandlers{store: mockStore}
-	handler := NewConfigHandlers(baseHandlers)
+	handler := NewConfigHandlers(baseHandlers, nil)
 	e := echo.New()
 
 	// Create request with invalid JSON
@@ -204,4 +265,354 @@ func TestSetNewsletterConfig_InvalidJSON(t *testing.T) {
 	httpErr, ok := err.(*echo.HTTPError)
 	assert.True(t, ok)
 	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
-}
\ No newline at end of file
+}
+func TestSetNewsletterConfig_NegativeCap(t *testing.T) {
+	// Setup
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	baseHandlers := &BaseHandlers{store: mockStore}
+	handler := NewConfigHandlers(baseHandlers, nil)
+	e := echo.New()
+
+	// Create request with a negative per-channel cap
+	req := httptest.NewRequest(http.MethodPut, "/api/config/newsletter", bytes.NewReader([]byte(`{"enabled":true,"maxVideosPerChannel":-1}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	// Execute
+	err := handler.SetNewsletterConfig(c)
+
+	// Assert
+	assert.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestSetNewsletterConfig_InvalidCronSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewConfigHandlers(&BaseHandlers{store: store.NewMockStore(ctrl)}, nil)
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/config/newsletter", bytes.NewReader([]byte(`{"enabled":true,"cronSchedule":"every morning"}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	err := handler.SetNewsletterConfig(c)
+
+	assert.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestGetCheckInterval_Default(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetCheckInterval().Return(time.Hour, nil)
+	handler := NewConfigHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/config/interval", nil), rec)
+
+	assert.NoError(t, handler.GetCheckInterval(c))
+	assert.JSONEq(t, `{"interval":"1h0m0s"}`, rec.Body.String())
+}
+
+func TestSetCheckInterval_Reschedules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	newsletterScheduler := scheduler.New(mockStore, "0 0 * * *", func() {})
+	defer newsletterScheduler.Stop()
+
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil).Times(2)
+	gomock.InOrder(
+		mockStore.EXPECT().GetScheduleInterval().Return(time.Duration(0), nil),
+		mockStore.EXPECT().SetCheckInterval(30*time.Minute).Return(nil),
+		mockStore.EXPECT().GetScheduleInterval().Return(30*time.Minute, nil),
+	)
+	assert.NoError(t, newsletterScheduler.Start())
+	assert.Equal(t, scheduler.SourceEnvironment, newsletterScheduler.Status().Source)
+
+	handler := NewConfigHandlers(&BaseHandlers{store: mockStore}, newsletterScheduler)
+	req := httptest.NewRequest(http.MethodPut, "/api/config/interval", bytes.NewReader([]byte(`{"interval":"30m"}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	assert.NoError(t, handler.SetCheckInterval(c))
+	status := newsletterScheduler.Status()
+	assert.Equal(t, scheduler.SourceInterval, status.Source)
+	assert.Equal(t, "@every 30m0s", status.Schedule)
+	if assert.NotNil(t, status.NextRun) {
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), *status.NextRun, time.Minute)
+	}
+}
+
+func TestSetSMTPConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedSaved  *store.SMTPConfig
+	}{
+		{
+			name:           "authenticated server with sender identity",
+			body:           `{"server":"smtp.example.com","port":"465","username":"user@example.com","password":"secret","recipientEmail":"me@example.com","fromAddress":"curator@example.com","fromName":" YouTube Curator ","tlsMode":"implicit"}`,
+			expectedStatus: http.StatusOK,
+			expectedSaved: &store.SMTPConfig{
+				Server: "smtp.example.com", Port: "465", Username: "user@example.com", Password: "secret", RecipientEmail: "me@example.com",
+				FromAddress: "curator@example.com", FromName: "YouTube Curator", TLSMode: "implicit",
+			},
+		},
+		{
+			name:           "unauthenticated relay",
+			body:           `{"server":"relay.lan","port":"25","recipientEmail":"me@example.com","fromAddress":"curator@example.com","tlsMode":"none","insecureSkipVerify":true}`,
+			expectedStatus: http.StatusOK,
+			expectedSaved: &store.SMTPConfig{
+				Server: "relay.lan", Port: "25", RecipientEmail: "me@example.com",
+				FromAddress: "curator@example.com", TLSMode: "none", InsecureSkipVerify: true,
+			},
+		},
+		{
+			name:           "relay without from address",
+			body:           `{"server":"relay.lan","port":"25","recipientEmail":"me@example.com"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "username without password",
+			body:           `{"server":"smtp.example.com","port":"587","username":"user@example.com","recipientEmail":"me@example.com"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid TLS mode",
+			body:           `{"server":"smtp.example.com","port":"587","username":"user@example.com","password":"secret","recipientEmail":"me@example.com","tlsMode":"ssl"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "username over a plain connection",
+			body:           `{"server":"smtp.example.com","port":"25","username":"user@example.com","password":"secret","recipientEmail":"me@example.com","tlsMode":"none"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid from address",
+			body:           `{"server":"smtp.example.com","port":"587","username":"user@example.com","password":"secret","recipientEmail":"me@example.com","fromAddress":"nope"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := store.NewMockStore(ctrl)
+			if tt.expectedSaved != nil {
+				mockStore.EXPECT().SetSMTPConfig(tt.expectedSaved).Return(nil)
+			}
+			handler := NewConfigHandlers(&BaseHandlers{store: mockStore}, nil)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/api/config/smtp", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.SetSMTPConfig(c)
+
+			if tt.expectedStatus != http.StatusOK {
+				assert.Error(t, err)
+				httpErr, ok := err.(*echo.HTTPError)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, httpErr.Code)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var response types.SMTPConfigResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedSaved.FromAddress, response.FromAddress)
+			assert.Equal(t, tt.expectedSaved.TLSMode, response.TLSMode)
+			assert.Equal(t, tt.expectedSaved.Password != "", response.PasswordSet)
+			assert.NotContains(t, rec.Body.String(), "secret")
+		})
+	}
+}
+
+// failingSender is an email.Sender that always fails
+type failingSender struct{}
+
+// Send fails with a permanent SMTP error, which is not retried
+func (failingSender) Send(recipient, subject string, content email.Content) error {
+	return &textproto.Error{Code: 535, Msg: "authentication failed"}
+}
+
+func TestTestSMTPConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	// A blank password reuses the saved one for the same server and username
+	mockStore.EXPECT().GetSMTPConfig().Return(&store.SMTPConfig{Server: "smtp.example.com", Username: "user@example.com", Password: "saved-secret"}, nil)
+	mockStore.EXPECT().SetSMTPConfig(gomock.Any()).Times(0)
+
+	sender := &email.MockSender{}
+	var used *store.SMTPConfig
+	handler := NewConfigHandlers(&BaseHandlers{store: mockStore}, nil)
+	handler.newSender = func(config *store.SMTPConfig) email.Sender {
+		used = config
+		return sender
+	}
+
+	e := echo.New()
+	body := `{"server":"smtp.example.com","port":"587","username":"user@example.com","recipientEmail":"me@example.com","fromName":"Curator"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/config/smtp/test", bytes.NewReader([]byte(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	assert.NoError(t, handler.TestSMTPConfig(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response types.SMTPTestResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, "me@example.com", response.Recipient)
+	if assert.NotNil(t, used) {
+		assert.Equal(t, "saved-secret", used.Password)
+		assert.Equal(t, "Curator", used.FromName)
+	}
+	if assert.Len(t, sender.SentEmails, 1) {
+		assert.Equal(t, "me@example.com", sender.SentEmails[0].Recipient)
+		assert.NotEmpty(t, sender.SentEmails[0].Text)
+	}
+}
+
+func TestTestSMTPConfig_SendFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewConfigHandlers(&BaseHandlers{store: store.NewMockStore(ctrl)}, nil)
+	handler.newSender = func(config *store.SMTPConfig) email.Sender { return failingSender{} }
+
+	e := echo.New()
+	body := `{"server":"smtp.example.com","port":"587","username":"user@example.com","password":"wrong","recipientEmail":"me@example.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/config/smtp/test", bytes.NewReader([]byte(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	assert.NoError(t, handler.TestSMTPConfig(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response types.SMTPTestResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Contains(t, response.Error, "535")
+}
+
+func TestTestSMTPConfig_UsernameWithoutTLS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewConfigHandlers(&BaseHandlers{store: store.NewMockStore(ctrl)}, nil)
+	handler.newSender = func(config *store.SMTPConfig) email.Sender {
+		t.Fatal("Expected the configuration to be rejected before sending")
+		return nil
+	}
+
+	e := echo.New()
+	body := `{"server":"smtp.example.com","port":"25","username":"user@example.com","password":"secret","recipientEmail":"me@example.com","tlsMode":"none"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/config/smtp/test", bytes.NewReader([]byte(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	err := handler.TestSMTPConfig(e.NewContext(req, rec))
+	httpErr, ok := err.(*echo.HTTPError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+		assert.Contains(t, httpErr.Message, "TLS")
+	}
+}
+
+func TestTestLLMConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`))
+			return
+		}
+		w.Write([]byte(`{"id":"chatcmpl-1","object":"chat.completion","created":0,"model":"test-model","choices":[{"index":0,"message":{"role":"assistant","content":" pong "},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		apiKey           string
+		expectedSuccess  bool
+		expectedCategory string
+	}{
+		{name: "valid key", apiKey: "good-key", expectedSuccess: true},
+		{name: "invalid key", apiKey: "bad-key", expectedSuccess: false, expectedCategory: "authentication"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := store.NewMockStore(ctrl)
+			mockStore.EXPECT().SetLLMConfig(gomock.Any()).Times(0)
+			handler := NewConfigHandlers(&BaseHandlers{store: mockStore}, nil)
+
+			e := echo.New()
+			body := `{"endpoint":"` + server.URL + `/","apiKey":"` + tt.apiKey + `","model":"test-model"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/config/llm/test", bytes.NewReader([]byte(body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			assert.NoError(t, handler.TestLLMConfig(e.NewContext(req, rec)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var response types.LLMTestResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedSuccess, response.Success)
+			assert.Equal(t, "test-model", response.Model)
+			assert.Equal(t, tt.expectedCategory, response.ErrorCategory)
+			if tt.expectedSuccess {
+				assert.Equal(t, "pong", response.Reply)
+			} else {
+				assert.NotEmpty(t, response.Error)
+			}
+		})
+	}
+}
+
+func TestTestLLMConfig_MissingAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	// The saved key belongs to another endpoint, so it isn't reused
+	mockStore.EXPECT().GetLLMConfig().Return(&store.LLMConfig{EndpointURL: "https://other.example.com/v1", APIKey: "saved"}, nil)
+	handler := NewConfigHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/config/llm/test", bytes.NewReader([]byte(`{"endpoint":"https://api.example.com/v1","model":"m"}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	err := handler.TestLLMConfig(e.NewContext(req, rec))
+	assert.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"youtube-curator-v2/internal/feeds"
+	"youtube-curator-v2/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+const defaultFeedItems = 50
+
+// FeedHandlers serves the video catalogue as Atom and JSON feeds
+type FeedHandlers struct {
+	*BaseHandlers
+}
+
+// NewFeedHandlers creates a new instance of feed handlers
+func NewFeedHandlers(base *BaseHandlers) *FeedHandlers {
+	return &FeedHandlers{BaseHandlers: base}
+}
+
+// GetAllAtomFeed handles GET /feeds/all.atom
+func (h *FeedHandlers) GetAllAtomFeed(c echo.Context) error {
+	feed, err := h.buildFeed(c, nil)
+	if err != nil {
+		return err
+	}
+	return writeFeed(c, feeds.AtomContentType, feed.Atom)
+}
+
+// GetAllJSONFeed handles GET /feeds/all.json
+func (h *FeedHandlers) GetAllJSONFeed(c echo.Context) error {
+	feed, err := h.buildFeed(c, nil)
+	if err != nil {
+		return err
+	}
+	return writeFeed(c, feeds.JSONContentType, feed.JSON)
+}
+
+// GetChannelAtomFeed handles GET /feeds/channel/:id.atom
+func (h *FeedHandlers) GetChannelAtomFeed(c echo.Context) error {
+	// Route parameters run up to the next slash, so the extension is part of the ID
+	channelID, ok := strings.CutSuffix(c.Param("id"), ".atom")
+	if !ok || channelID == "" {
+		return echo.NewHTTPError(http.StatusNotFound, "Feed not found")
+	}
+
+	channels, err := h.store.GetChannels()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve channels")
+	}
+	for i := range channels {
+		if channels[i].ID == channelID {
+			feed, err := h.buildFeed(c, &channels[i])
+			if err != nil {
+				return err
+			}
+			return writeFeed(c, feeds.AtomContentType, feed.Atom)
+		}
+	}
+	return echo.NewHTTPError(http.StatusNotFound, "Channel not found")
+}
+
+// buildFeed selects the newest catalogued videos, of channel when it isn't nil. Query parameters:
+// ?summaries=true uses stored summaries as item content, ?excludeWatched=true leaves out watched
+// videos and ?limit= caps the number of items (default 50, at most 500).
+func (h *FeedHandlers) buildFeed(c echo.Context, channel *store.Channel) (*feeds.Feed, error) {
+	if h.videoStore == nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Video store not initialized")
+	}
+
+	limit, err := parseIntParam(c, "limit")
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = defaultFeedItems
+	}
+	if limit > maxVideosPageSize {
+		limit = maxVideosPageSize
+	}
+
+	query := store.VideoQuery{SortBy: store.VideoSortPublished}
+	if c.QueryParam("excludeWatched") == "true" {
+		unwatched := false
+		query.Watched = &unwatched
+	}
+
+	baseURL := c.Scheme() + "://" + c.Request().Host
+	feed := &feeds.Feed{
+		Title:       "YouTube Curator",
+		Description: "New videos from the curated channels",
+		FeedURL:     baseURL + c.Request().URL.RequestURI(),
+		HomeURL:     baseURL + "/",
+		Summaries:   c.QueryParam("summaries") == "true",
+	}
+	if channel != nil {
+		query.ChannelID = channel.ID
+		feed.Title = channel.Title + " - YouTube Curator"
+		feed.Description = "New videos from " + channel.Title
+		feed.HomeURL = "https://www.youtube.com/channel/" + channel.ID
+	}
+
+	videos := query.Apply(h.videoStore.GetAllVideos())
+	if len(videos) > limit {
+		videos = videos[:limit]
+	}
+	feed.Videos = videos
+	return feed, nil
+}
+
+// writeFeed renders a feed and writes it with the given content type
+func writeFeed(c echo.Context, contentType string, render func() ([]byte, error)) error {
+	body, err := render()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to render feed")
+	}
+	return c.Blob(http.StatusOK, contentType, body)
+}
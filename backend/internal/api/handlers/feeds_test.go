@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"youtube-curator-v2/internal/feeds"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+)
+
+const testFeedChannelID = "UCAYF6ZY9gWBR1GW3R7PX7yw"
+
+// newFeedVideoStore returns a video store holding three videos an hour apart, newest first:
+// video-1 and video-2 from the test channel, with video-2 watched, and video-3 from another
+func newFeedVideoStore(t *testing.T) *store.VideoStore {
+	videoStore := store.NewVideoStore(time.Hour)
+	published := time.Now().Add(-time.Hour)
+	for i, channelID := range []string{testFeedChannelID, testFeedChannelID, "UCother"} {
+		id := fmt.Sprintf("yt:video:video-%d", i+1)
+		entry := rss.Entry{ID: id, Title: fmt.Sprintf("Video %d", i+1), Published: published.Add(-time.Duration(i) * time.Hour),
+			Summary: &rss.Summary{Text: "Summary of " + id}}
+		require.NoError(t, videoStore.AddVideo(channelID, entry))
+	}
+	require.NoError(t, videoStore.MarkVideoAsWatched("yt:video:video-2"))
+	return videoStore
+}
+
+func TestGetAllJSONFeed(t *testing.T) {
+	handler := NewFeedHandlers(&BaseHandlers{videoStore: newFeedVideoStore(t)})
+
+	get := func(target string) (*http.Response, []string, []string) {
+		c, rec := newSubscriberContext(http.MethodGet, target, "")
+		require.NoError(t, handler.GetAllJSONFeed(c))
+		var doc struct {
+			FeedURL string `json:"feed_url"`
+			Items   []struct {
+				ID          string `json:"id"`
+				ContentText string `json:"content_text"`
+			} `json:"items"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, "http://example.com"+target, doc.FeedURL)
+		var ids, contents []string
+		for _, item := range doc.Items {
+			ids = append(ids, item.ID)
+			contents = append(contents, item.ContentText)
+		}
+		return rec.Result(), ids, contents
+	}
+
+	res, ids, contents := get("/feeds/all.json")
+	assert.Equal(t, feeds.JSONContentType, res.Header.Get(echo.HeaderContentType))
+	assert.Equal(t, []string{"yt:video:video-1", "yt:video:video-2", "yt:video:video-3"}, ids)
+	assert.Equal(t, "Video 1", contents[0], "Summaries are only included on request")
+
+	_, ids, contents = get("/feeds/all.json?excludeWatched=true&summaries=true&limit=1")
+	assert.Equal(t, []string{"yt:video:video-1"}, ids)
+	assert.Equal(t, []string{"Summary of yt:video:video-1"}, contents)
+
+	c, _ := newSubscriberContext(http.MethodGet, "/feeds/all.json?limit=-1", "")
+	err := handler.GetAllJSONFeed(c)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestGetChannelAtomFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: testFeedChannelID, Title: "Channel"}}, nil).AnyTimes()
+	handler := NewFeedHandlers(&BaseHandlers{store: mockStore, videoStore: newFeedVideoStore(t)})
+
+	feed := func(id string) (*httptest.ResponseRecorder, error) {
+		c, rec := newSubscriberContext(http.MethodGet, "/feeds/channel/"+id+"?excludeWatched=true", "")
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		return rec, handler.GetChannelAtomFeed(c)
+	}
+
+	rec, err := feed(testFeedChannelID + ".atom")
+	require.NoError(t, err)
+	assert.Equal(t, feeds.AtomContentType, rec.Header().Get(echo.HeaderContentType))
+	var doc struct {
+		Title   string `xml:"title"`
+		Entries []struct {
+			ID string `xml:"id"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "Channel - YouTube Curator", doc.Title)
+	require.Len(t, doc.Entries, 1, "Expected only the channel's unwatched video")
+	assert.Equal(t, "yt:video:video-1", doc.Entries[0].ID)
+
+	for _, id := range []string{"UCunknown.atom", testFeedChannelID, testFeedChannelID + ".json", ".atom"} {
+		_, err := feed(id)
+		require.Error(t, err, id)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code, id)
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/jobs"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/summary"
+	"youtube-curator-v2/internal/videoid"
+
+	"github.com/labstack/echo/v4"
+)
+
+// JobHandlers provides handlers for background job endpoints
+type JobHandlers struct {
+	*BaseHandlers
+	jobQueue *jobs.Queue
+}
+
+// NewJobHandlers creates a new instance of job handlers
+func NewJobHandlers(base *BaseHandlers, jobQueue *jobs.Queue) *JobHandlers {
+	return &JobHandlers{BaseHandlers: base, jobQueue: jobQueue}
+}
+
+// EnqueueVideoSummary handles POST /api/videos/:videoId/summary
+// Pass ?regenerate=true to replace a stored summary once the job runs
+func (h *JobHandlers) EnqueueVideoSummary(c echo.Context) error {
+	rawVideoID := c.Param("videoId")
+	if rawVideoID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Video ID is required")
+	}
+
+	// Create and validate video ID
+	vid, err := videoid.NewFromRaw(rawVideoID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if h.jobQueue == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Job queue not initialized")
+	}
+
+	job, err := h.jobQueue.EnqueueSummary(vid.ToFull(), c.QueryParam("regenerate") == "true")
+	if errors.Is(err, jobs.ErrQueueFull) {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "Summary queue is full, try again later")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to queue summary: %v", err))
+	}
+
+	return c.JSON(http.StatusAccepted, types.TransformJob(*job, nil, false))
+}
+
+// GetJob handles GET /api/jobs/:id
+func (h *JobHandlers) GetJob(c echo.Context) error {
+	jobID := c.Param("id")
+	if jobID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Job ID is required")
+	}
+
+	if h.jobQueue == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Job queue not initialized")
+	}
+
+	job, err := h.jobQueue.Get(jobID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve job")
+	}
+	if job == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Job not found")
+	}
+
+	// Include the stored summary once the job has succeeded
+	var stored *store.StoredSummary
+	tracked := false
+	if job.Type == store.JobTypeSummary && job.Status == store.JobStatusSucceeded {
+		stored, err = h.store.GetSummary(job.VideoID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve summary")
+		}
+		if stored != nil {
+			tracked = summary.IsTrackedChannel(h.store, stored.ChannelID)
+		}
+	}
+
+	return c.JSON(http.StatusOK, types.TransformJob(*job, stored, tracked))
+}
@@ -1,25 +1,31 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
-	"sort"
+	"time"
 
 	"youtube-curator-v2/internal/api/types"
-	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/newsletter"
 	"youtube-curator-v2/internal/rss"
 	"youtube-curator-v2/internal/store"
 
 	"github.com/labstack/echo/v4"
 )
 
+// manualRunTimeout bounds a newsletter run or outbox resend started through the API
+const manualRunTimeout = 30 * time.Minute
+
 // NewsletterHandlers provides handlers for newsletter manual triggers
 type NewsletterHandlers struct {
 	*BaseHandlers
+	runner *newsletter.Runner
 }
 
 // NewNewsletterHandlers creates a new instance of newsletter handlers
-func NewNewsletterHandlers(base *BaseHandlers) *NewsletterHandlers {
-	return &NewsletterHandlers{BaseHandlers: base}
+func NewNewsletterHandlers(base *BaseHandlers, runner *newsletter.Runner) *NewsletterHandlers {
+	return &NewsletterHandlers{BaseHandlers: base, runner: runner}
 }
 
 // RunNewsletter handles POST /api/newsletter/run
@@ -40,97 +46,76 @@ func (h *NewsletterHandlers) RunNewsletter(c echo.Context) error {
 	if req.MaxItems < 0 {
 		return echo.NewHTTPError(http.StatusBadRequest, "maxItems must be non-negative")
 	}
-
-	ctx := c.Request().Context()
-	var channels []store.Channel
-	var err error
-
-	if req.ChannelID != "" {
-		// Get specific channel
-		allChannels, err := h.store.GetChannels()
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve channels")
-		}
-
-		// Find the specific channel
-		found := false
-		for _, ch := range allChannels {
-			if ch.ID == req.ChannelID {
-				channels = append(channels, ch)
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return echo.NewHTTPError(http.StatusBadRequest, "Channel not found")
-		}
-	} else {
-		// Get all channels
-		channels, err = h.store.GetChannels()
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve channels")
-		}
+	if req.MaxPerChannel < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "maxPerChannel must be non-negative")
 	}
 
-	if len(channels) == 0 {
-		return echo.NewHTTPError(http.StatusBadRequest, "No channels configured")
+	ctx, cancel := manualRunContext(c)
+	defer cancel()
+	result, err := h.runner.Run(ctx, newsletter.Options{
+		Trigger:           store.RunTriggerManual,
+		ChannelID:         req.ChannelID,
+		IgnoreLastChecked: req.IgnoreLastChecked,
+		MaxItems:          req.MaxItems,
+		MaxPerChannel:     req.MaxPerChannel,
+		DryRun:            req.DryRun,
+	})
+	if err != nil {
+		return newsletterRunError(err)
 	}
 
-	// Process channels and collect new videos
-	var allNewVideos []rss.Entry
-	processedCount := 0
-	errorCount := 0
-
-	for _, channel := range channels {
-		result := h.processor.ProcessChannelWithOptions(ctx, channel.ID, req.IgnoreLastChecked, req.MaxItems)
-
-		if result.Error != nil {
-			errorCount++
-			continue
-		}
+	return c.JSON(http.StatusOK, types.TransformNewsletterRun(result, req.DryRun))
+}
 
-		processedCount++
-		if result.NewVideo != nil {
-			allNewVideos = append(allNewVideos, *result.NewVideo)
+// PreviewNewsletter handles GET /api/newsletter/preview. It renders the digest the next run would
+// send without sending it or advancing the last checked timestamps.
+func (h *NewsletterHandlers) PreviewNewsletter(c echo.Context) error {
+	channelID := c.QueryParam("channelId")
+	if channelID != "" {
+		if err := rss.ValidateChannelID(channelID); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
 	}
+	maxItems, err := parseIntParam(c, "maxItems")
+	if err != nil {
+		return err
+	}
+	maxPerChannel, err := parseIntParam(c, "maxPerChannel")
+	if err != nil {
+		return err
+	}
 
-	// Sort videos by published date
-	sort.Slice(allNewVideos, func(i, j int) bool {
-		return allNewVideos[i].Published.Before(allNewVideos[j].Published)
+	result, err := h.runner.Run(c.Request().Context(), newsletter.Options{
+		Trigger:           store.RunTriggerManual,
+		ChannelID:         channelID,
+		IgnoreLastChecked: c.QueryParam("ignoreLastChecked") == "true",
+		MaxItems:          maxItems,
+		MaxPerChannel:     maxPerChannel,
+		DryRun:            true,
 	})
+	if err != nil {
+		return newsletterRunError(err)
+	}
 
-	// Send email if there are new videos
-	if len(allNewVideos) > 0 {
-		emailBody, err := email.FormatNewVideosEmail(allNewVideos)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to format email: "+err.Error())
-		}
-
-		// Get SMTP config from database
-		smtpConfig, err := h.store.GetSMTPConfig()
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve SMTP configuration: "+err.Error())
-		}
-		if smtpConfig == nil || smtpConfig.RecipientEmail == "" {
-			return echo.NewHTTPError(http.StatusInternalServerError, "SMTP configuration not set")
-		}
+	return c.JSON(http.StatusOK, types.TransformNewsletterPreview(result, c.QueryParam("recipient")))
+}
 
-		subject := "New YouTube Videos Update"
-		if err := h.emailSender.Send(smtpConfig.RecipientEmail, subject, emailBody); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to send email: "+err.Error())
-		}
-	}
+// manualRunContext returns the context for a run or resend started by c. It doesn't end with the
+// request, so a client that disconnects doesn't interrupt deliveries half way.
+func manualRunContext(c echo.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(c.Request().Context()), manualRunTimeout)
+}
 
-	// Return response with stats
-	response := types.NewsletterRunResponse{
-		Message:           "Newsletter run completed",
-		ChannelsProcessed: processedCount,
-		ChannelsWithError: errorCount,
-		NewVideosFound:    len(allNewVideos),
-		EmailSent:         len(allNewVideos) > 0,
+// newsletterRunError maps an error from the newsletter runner to an HTTP error
+func newsletterRunError(err error) error {
+	switch {
+	case errors.Is(err, newsletter.ErrChannelNotFound):
+		return echo.NewHTTPError(http.StatusBadRequest, "Channel not found")
+	case errors.Is(err, newsletter.ErrNoChannels):
+		return echo.NewHTTPError(http.StatusBadRequest, "No channels configured")
+	case errors.Is(err, newsletter.ErrRunInProgress):
+		return echo.NewHTTPError(http.StatusConflict, "A newsletter run is already in progress, try again later")
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "Newsletter run failed: "+err.Error())
 	}
-
-	return c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/config"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/newsletter"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/processor"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/summary"
+)
+
+// stubChannelProcessor returns canned results per channel
+type stubChannelProcessor struct {
+	results map[string]processor.ChannelResult
+
+	mu        sync.Mutex
+	processed int // Channels processed, advancing their last checked timestamp
+	collected int // Channels collected, leaving their last checked timestamp to the runner
+	previewed int // Channels previewed, leaving their last checked timestamp alone
+}
+
+func (p *stubChannelProcessor) ProcessChannel(ctx context.Context, channelID string) processor.ChannelResult {
+	return p.ProcessChannelWithOptions(ctx, channelID, false, 0)
+}
+
+func (p *stubChannelProcessor) ProcessChannelWithOptions(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) processor.ChannelResult {
+	p.mu.Lock()
+	p.processed++
+	p.mu.Unlock()
+	return p.result(channelID)
+}
+
+func (p *stubChannelProcessor) CollectChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) processor.ChannelResult {
+	p.mu.Lock()
+	p.collected++
+	p.mu.Unlock()
+	return p.result(channelID)
+}
+
+func (p *stubChannelProcessor) PreviewChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) processor.ChannelResult {
+	p.mu.Lock()
+	p.previewed++
+	p.mu.Unlock()
+	return p.result(channelID)
+}
+
+func (p *stubChannelProcessor) result(channelID string) processor.ChannelResult {
+	if result, ok := p.results[channelID]; ok {
+		return result
+	}
+	return processor.ChannelResult{ChannelID: channelID}
+}
+
+// newNewsletterHandlers creates newsletter handlers with a runner built from the base dependencies
+func newNewsletterHandlers(base *BaseHandlers) *NewsletterHandlers {
+	return NewNewsletterHandlers(base, newsletter.NewRunner(base.store, base.processor, notify.NewDispatcher(base.emailSender, nil), base.summaryService, base.config))
+}
+
+func runNewsletter(t *testing.T, handler *NewsletterHandlers, body string) types.NewsletterRunResponse {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/newsletter/run", bytes.NewReader([]byte(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, handler.RunNewsletter(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response types.NewsletterRunResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return response
+}
+
+func TestRunNewsletter_GroupsEveryNewVideoByChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	mockStore := store.NewMockStore(ctrl)
+	expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{
+		{ID: "UCbusy", Title: "Busy Channel"},
+		{ID: "UCquiet", Title: "Quiet Channel"},
+	}, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true, MaxVideosPerChannel: 5}, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(&store.SMTPConfig{RecipientEmail: "me@example.com"}, nil)
+
+	sender := &email.MockSender{}
+	handler := newNewsletterHandlers(&BaseHandlers{
+		store:       mockStore,
+		emailSender: sender,
+		processor: &stubChannelProcessor{results: map[string]processor.ChannelResult{
+			"UCbusy": {ChannelID: "UCbusy", NewVideos: []rss.Entry{
+				{ID: "yt:video:b1", Title: "Busy Upload 1", Published: now.Add(-1 * time.Hour)},
+				{ID: "yt:video:b2", Title: "Busy Upload 2", Published: now.Add(-2 * time.Hour)},
+				{ID: "yt:video:b3", Title: "Busy Upload 3", Published: now.Add(-3 * time.Hour)},
+			}},
+			"UCquiet": {ChannelID: "UCquiet", NewVideos: []rss.Entry{
+				{ID: "yt:video:q1", Title: "Quiet Upload", Published: now.Add(-4 * time.Hour)},
+			}},
+		}},
+	})
+
+	// The request cap overrides the configured one
+	response := runNewsletter(t, handler, `{"maxPerChannel":2}`)
+
+	assert.Equal(t, 2, response.ChannelsProcessed)
+	assert.Equal(t, 4, response.NewVideosFound)
+	assert.True(t, response.EmailSent)
+
+	require.Len(t, sender.SentEmails, 1)
+	body := sender.SentEmails[0].Body
+	assert.Equal(t, "me@example.com", sender.SentEmails[0].Recipient)
+	for _, want := range []string{"Busy Channel", "Busy Upload 1", "Busy Upload 2", "+1 more new video(s)", "Quiet Channel", "Quiet Upload"} {
+		assert.Contains(t, body, want)
+	}
+	assert.NotContains(t, body, "Busy Upload 3")
+	assert.Contains(t, sender.SentEmails[0].Text, "* Busy Upload 1", "The digest should carry a plain-text rendering")
+}
+
+func TestRunNewsletter_IncludesSummaries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCchannel", Title: "Channel"}}, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true, IncludeSummaries: true}, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(&store.SMTPConfig{RecipientEmail: "me@example.com"}, nil)
+
+	sender := &email.MockSender{}
+	handler := newNewsletterHandlers(&BaseHandlers{
+		store:          mockStore,
+		emailSender:    sender,
+		config:         &config.Config{NewsletterSummaryConcurrency: 2, NewsletterSummaryBudget: time.Minute},
+		summaryService: summary.NewMockService(nil),
+		processor: &stubChannelProcessor{results: map[string]processor.ChannelResult{
+			"UCchannel": {ChannelID: "UCchannel", NewVideos: []rss.Entry{
+				{ID: "tutorial123", Title: "Tutorial", Published: time.Now(), MediaGroup: rss.MediaGroup{MediaDescription: "Original description"}},
+			}},
+		}},
+	})
+
+	response := runNewsletter(t, handler, `{}`)
+
+	assert.True(t, response.EmailSent)
+	require.Len(t, sender.SentEmails, 1)
+	body := sender.SentEmails[0].Body
+	assert.Contains(t, body, "educational tutorial", "Email should contain the generated summary")
+	assert.NotContains(t, body, "Original description")
+}
+
+func TestRunNewsletter_SendsOneDigestPerSubscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	run := expectRunHistory(mockStore)
+	outbox := expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{
+		{ID: "UCone", Title: "Channel One"},
+		{ID: "UCtwo", Title: "Channel Two"},
+	}, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil)
+	mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{
+		{ID: "1", Email: "one@example.com", ChannelIDs: []string{"UCone"}, Enabled: true},
+		{ID: "2", Email: "tagged@example.com", Tags: []string{"cooking"}, Enabled: true},
+		{ID: "3", Email: "nobody@example.com", ChannelIDs: []string{"UCthree"}, Enabled: true},
+		{ID: "4", Email: "paused@example.com", Enabled: false},
+	}, nil)
+
+	sender := &email.MockSender{}
+	handler := newNewsletterHandlers(&BaseHandlers{
+		store:       mockStore,
+		emailSender: sender,
+		processor: &stubChannelProcessor{results: map[string]processor.ChannelResult{
+			"UCone": {ChannelID: "UCone", NewVideos: []rss.Entry{{ID: "yt:video:1", Title: "First Video", Published: time.Now()}}},
+			"UCtwo": {ChannelID: "UCtwo", NewVideos: []rss.Entry{{ID: "yt:video:2", Title: "Pasta Video", Published: time.Now(), Tags: []string{"Cooking"}}}},
+		}},
+	})
+
+	response := runNewsletter(t, handler, `{}`)
+
+	assert.True(t, response.EmailSent)
+	assert.Equal(t, 2, response.EmailsSent)
+	assert.Equal(t, 0, response.EmailsFailed)
+	require.Len(t, sender.SentEmails, 2)
+
+	assert.Equal(t, "one@example.com", sender.SentEmails[0].Recipient)
+	assert.Contains(t, sender.SentEmails[0].Body, "First Video")
+	assert.NotContains(t, sender.SentEmails[0].Body, "Pasta Video")
+
+	assert.Equal(t, "tagged@example.com", sender.SentEmails[1].Recipient)
+	assert.Contains(t, sender.SentEmails[1].Body, "Pasta Video")
+	assert.NotContains(t, sender.SentEmails[1].Body, "First Video")
+
+	// The run is recorded with a delivery entry per enabled subscriber
+	assert.Equal(t, response.RunID, run.ID)
+	assert.Equal(t, store.RunTriggerManual, run.Trigger)
+	assert.Equal(t, store.RunStatusSucceeded, run.Status)
+	assert.Equal(t, 2, run.NewVideosFound)
+	require.Len(t, run.Channels, 2)
+	assert.Equal(t, store.RunChannel{ChannelID: "UCone", ChannelTitle: "Channel One", NewVideos: 1}, run.Channels[0])
+	require.Len(t, run.Emails, 3)
+	outboxID := run.Emails[0].OutboxID
+	assert.Equal(t, store.RunEmail{Recipient: "one@example.com", Videos: 1, Status: store.EmailStatusSent, OutboxID: outboxID}, run.Emails[0])
+	assert.Equal(t, store.RunEmail{Recipient: "nobody@example.com", Status: store.EmailStatusSkipped}, run.Emails[2])
+
+	// Each digest went out through the outbox
+	require.Len(t, outbox, 2)
+	assert.Equal(t, store.OutboxStatusSent, outbox[outboxID].Status)
+	assert.Equal(t, "one@example.com", outbox[outboxID].Recipient)
+}
+
+func TestRunNewsletter_RecordsFailedRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	run := expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCchannel", Title: "Channel"}}, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(&store.SMTPConfig{RecipientEmail: "me@example.com"}, nil)
+
+	handler := newNewsletterHandlers(&BaseHandlers{
+		store:       mockStore,
+		emailSender: failingSender{},
+		processor: &stubChannelProcessor{results: map[string]processor.ChannelResult{
+			"UCchannel": {ChannelID: "UCchannel", NewVideos: []rss.Entry{{ID: "yt:video:1", Title: "Video", Published: time.Now()}}},
+		}},
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/newsletter/run", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	err := handler.RunNewsletter(e.NewContext(req, httptest.NewRecorder()))
+	require.Error(t, err)
+
+	assert.Equal(t, store.RunStatusFailed, run.Status)
+	assert.Contains(t, run.Error, "failed to send email")
+	assert.NotNil(t, run.FinishedAt)
+	require.Len(t, run.Emails, 1)
+	assert.Equal(t, store.EmailStatusFailed, run.Emails[0].Status)
+	assert.NotEmpty(t, run.Emails[0].Error)
+}
+
+func TestRunNewsletter_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A dry run isn't recorded in the run history
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCchannel", Title: "Channel"}}, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(&store.SMTPConfig{RecipientEmail: "me@example.com"}, nil)
+
+	sender := &email.MockSender{}
+	channelProcessor := &stubChannelProcessor{results: map[string]processor.ChannelResult{
+		"UCchannel": {ChannelID: "UCchannel", NewVideos: []rss.Entry{{ID: "yt:video:1", Title: "Dry Run Video", Published: time.Now()}}},
+	}}
+	handler := newNewsletterHandlers(&BaseHandlers{store: mockStore, emailSender: sender, processor: channelProcessor})
+
+	response := runNewsletter(t, handler, `{"dryRun":true}`)
+
+	assert.Empty(t, sender.SentEmails, "A dry run must not send email")
+	assert.Equal(t, 0, channelProcessor.processed, "A dry run must not advance the last checked timestamps")
+	assert.True(t, response.DryRun)
+	assert.Empty(t, response.RunID)
+	assert.False(t, response.EmailSent)
+	assert.Equal(t, 1, response.NewVideosFound)
+	require.Len(t, response.Emails, 1)
+	assert.Equal(t, "me@example.com", response.Emails[0].Recipient)
+	assert.Equal(t, newsletter.Subject, response.Emails[0].Subject)
+	assert.Equal(t, 1, response.Emails[0].Videos)
+	assert.Contains(t, response.Emails[0].HTML, "Dry Run Video")
+	assert.Contains(t, response.Emails[0].Text, "Dry Run Video")
+}
+
+func TestPreviewNewsletter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A preview isn't recorded in the run history
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{
+		{ID: "UCone", Title: "Channel One"},
+		{ID: "UCtwo", Title: "Channel Two"},
+	}, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil)
+	mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{
+		{ID: "1", Email: "one@example.com", ChannelIDs: []string{"UCone"}, Enabled: true},
+		{ID: "2", Email: "two@example.com", ChannelIDs: []string{"UCtwo"}, Enabled: true},
+	}, nil)
+
+	sender := &email.MockSender{}
+	channelProcessor := &stubChannelProcessor{results: map[string]processor.ChannelResult{
+		"UCone": {ChannelID: "UCone", NewVideos: []rss.Entry{{ID: "yt:video:1", Title: "First Video", Published: time.Now()}}},
+		"UCtwo": {ChannelID: "UCtwo", NewVideos: []rss.Entry{{ID: "yt:video:2", Title: "Second Video", Published: time.Now()}}},
+	}}
+	handler := newNewsletterHandlers(&BaseHandlers{store: mockStore, emailSender: sender, processor: channelProcessor})
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/newsletter/preview?recipient=TWO@example.com", nil), rec)
+	require.NoError(t, handler.PreviewNewsletter(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response types.NewsletterPreviewResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Empty(t, sender.SentEmails, "A preview must not send email")
+	assert.Equal(t, 2, channelProcessor.previewed)
+	assert.Equal(t, 0, channelProcessor.processed, "A preview must not advance the last checked timestamps")
+	assert.Equal(t, 2, response.NewVideosFound)
+	require.Len(t, response.Emails, 2)
+
+	// The requested recipient's email leads the response
+	assert.Equal(t, "two@example.com", response.Recipient)
+	assert.Equal(t, newsletter.Subject, response.Subject)
+	assert.Equal(t, 1, response.Videos)
+	assert.Contains(t, response.HTML, "Second Video")
+	assert.NotContains(t, response.HTML, "First Video")
+	assert.Contains(t, response.Text, "Second Video")
+}
+
+func TestPreviewNewsletter_WithoutRecipients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCone", Title: "Channel One"}}, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(nil, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(nil, nil)
+
+	handler := newNewsletterHandlers(&BaseHandlers{
+		store:       mockStore,
+		emailSender: &email.MockSender{},
+		processor: &stubChannelProcessor{results: map[string]processor.ChannelResult{
+			"UCone": {ChannelID: "UCone", NewVideos: []rss.Entry{{ID: "yt:video:1", Title: "First Video", Published: time.Now()}}},
+		}},
+	})
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/newsletter/preview", nil), rec)
+	require.NoError(t, handler.PreviewNewsletter(c))
+
+	// With nobody to send to, the full digest is rendered
+	var response types.NewsletterPreviewResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Empty(t, response.Recipient)
+	assert.Contains(t, response.HTML, "First Video")
+}
+
+func TestPreviewNewsletter_InvalidParams(t *testing.T) {
+	handler := newNewsletterHandlers(&BaseHandlers{})
+	for _, query := range []string{"?channelId=nope", "?maxItems=-1", "?maxPerChannel=many"} {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/newsletter/preview"+query, nil), httptest.NewRecorder())
+		err := handler.PreviewNewsletter(c)
+		require.Error(t, err, query)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok, query)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code, query)
+	}
+}
+
+func TestRunNewsletter_ChannelErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		channels []store.Channel
+		body     string
+		message  string
+	}{
+		{name: "no channels", body: `{}`, message: "No channels configured"},
+		{name: "unknown channel", channels: []store.Channel{{ID: "UCaaaaaaaaaaaaaaaaaaaaaa"}}, body: `{"channelId":"UCbbbbbbbbbbbbbbbbbbbbbb"}`, message: "Channel not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := store.NewMockStore(ctrl)
+			expectOutbox(mockStore)
+			mockStore.EXPECT().GetChannels().Return(tt.channels, nil)
+			handler := newNewsletterHandlers(&BaseHandlers{store: mockStore, emailSender: &email.MockSender{}, processor: &stubChannelProcessor{}})
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/newsletter/run", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			err := handler.RunNewsletter(e.NewContext(req, httptest.NewRecorder()))
+
+			require.Error(t, err)
+			httpErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			assert.Equal(t, tt.message, httpErr.Message)
+		})
+	}
+}
+
+func TestNewsletterRunError_RunInProgress(t *testing.T) {
+	httpErr, ok := newsletterRunError(newsletter.ErrRunInProgress).(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusConflict, httpErr.Code)
+}
+
+// expectRunHistory accepts the run history writes made by RunNewsletter and returns the last saved run
+func expectRunHistory(mockStore *store.MockStore) *store.Run {
+	saved := &store.Run{}
+	mockStore.EXPECT().SaveRun(gomock.Any()).DoAndReturn(func(run *store.Run) error {
+		*saved = *run
+		return nil
+	}).AnyTimes()
+	mockStore.EXPECT().PruneRuns(store.RunHistoryLimit).Return(0, nil).AnyTimes()
+	return saved
+}
+
+// expectOutbox accepts the outbox and last checked timestamp calls made by RunNewsletter and
+// returns the outbox messages by ID
+func expectOutbox(mockStore *store.MockStore) map[string]store.OutboxMessage {
+	var mu sync.Mutex
+	messages := make(map[string]store.OutboxMessage)
+	mockStore.EXPECT().SaveOutboxMessage(gomock.Any()).DoAndReturn(func(message *store.OutboxMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		messages[message.ID] = *message
+		return nil
+	}).AnyTimes()
+	mockStore.EXPECT().GetOutboxMessages(gomock.Any()).DoAndReturn(func(statuses ...store.OutboxStatus) ([]store.OutboxMessage, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var matching []store.OutboxMessage
+		for _, message := range messages {
+			for _, status := range statuses {
+				if message.Status == status {
+					matching = append(matching, message)
+				}
+			}
+		}
+		return matching, nil
+	}).AnyTimes()
+	mockStore.EXPECT().PruneOutbox(store.OutboxHistoryLimit).Return(0, nil).AnyTimes()
+	mockStore.EXPECT().GetLastCheckedTimestamp(gomock.Any()).Return(time.Time{}, nil).AnyTimes()
+	mockStore.EXPECT().SetLastCheckedTimestamp(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return messages
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/newsletter"
+	"youtube-curator-v2/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+// OutboxHandlers provides handlers for the newsletter outbox endpoints
+type OutboxHandlers struct {
+	*BaseHandlers
+	runner *newsletter.Runner
+}
+
+// NewOutboxHandlers creates a new instance of outbox handlers
+func NewOutboxHandlers(base *BaseHandlers, runner *newsletter.Runner) *OutboxHandlers {
+	return &OutboxHandlers{BaseHandlers: base, runner: runner}
+}
+
+// GetOutboxMessages handles GET /api/outbox
+// Returns the messages in the order they were enqueued; ?status= keeps only pending, sent or dead ones
+func (h *OutboxHandlers) GetOutboxMessages(c echo.Context) error {
+	var statuses []store.OutboxStatus
+	if status := c.QueryParam("status"); status != "" {
+		switch store.OutboxStatus(status) {
+		case store.OutboxStatusPending, store.OutboxStatusSent, store.OutboxStatusDead:
+			statuses = append(statuses, store.OutboxStatus(status))
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid status, must be pending, sent or dead")
+		}
+	}
+
+	messages, err := h.store.GetOutboxMessages(statuses...)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve outbox messages")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformOutboxMessages(messages))
+}
+
+// GetOutboxMessage handles GET /api/outbox/:id
+// Includes the rendered email
+func (h *OutboxHandlers) GetOutboxMessage(c echo.Context) error {
+	messageID := c.Param("id")
+	if messageID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Message ID is required")
+	}
+
+	message, err := h.store.GetOutboxMessage(messageID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve outbox message")
+	}
+	if message == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Outbox message not found")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformOutboxMessage(*message, true))
+}
+
+// ResendOutboxMessage handles POST /api/outbox/:id/resend
+// Delivers a dead-lettered message again and returns its new state
+func (h *OutboxHandlers) ResendOutboxMessage(c echo.Context) error {
+	messageID := c.Param("id")
+	if messageID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Message ID is required")
+	}
+
+	ctx, cancel := manualRunContext(c)
+	defer cancel()
+	message, err := h.runner.Resend(ctx, messageID)
+	switch {
+	case errors.Is(err, newsletter.ErrMessageNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "Outbox message not found")
+	case errors.Is(err, newsletter.ErrNotDeadLetter):
+		return echo.NewHTTPError(http.StatusConflict, "Only dead-lettered messages can be resent")
+	case errors.Is(err, newsletter.ErrRunInProgress):
+		return echo.NewHTTPError(http.StatusConflict, "A newsletter run is in progress, try again later")
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to resend outbox message")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformOutboxMessage(*message, false))
+}
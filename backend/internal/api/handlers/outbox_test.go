@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/newsletter"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/store"
+)
+
+func TestGetOutboxMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetOutboxMessages(store.OutboxStatusDead).Return([]store.OutboxMessage{{
+		ID:        "msg1",
+		RunID:     "run1",
+		Recipient: "me@example.com",
+		Subject:   newsletter.Subject,
+		HTML:      "<p>Videos</p>",
+		Text:      "Videos",
+		Videos:    2,
+		Status:    store.OutboxStatusDead,
+		Attempts:  5,
+		Error:     "max retries exceeded: timeout",
+		CreatedAt: time.Now(),
+	}}, nil)
+	handler := NewOutboxHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/outbox?status=dead", nil), rec)
+	require.NoError(t, handler.GetOutboxMessages(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response types.OutboxMessagesResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, 1, response.TotalCount)
+	message := response.Messages[0]
+	assert.Equal(t, "dead", message.Status)
+	assert.Equal(t, 5, message.Attempts)
+	assert.Equal(t, "run1", message.RunID)
+	assert.Empty(t, message.HTML, "The list should leave out the rendered email")
+
+	// Unknown statuses are rejected
+	c = echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/outbox?status=lost", nil), httptest.NewRecorder())
+	err := handler.GetOutboxMessages(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestGetOutboxMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetOutboxMessage("msg1").Return(&store.OutboxMessage{ID: "msg1", HTML: "<p>Videos</p>", Text: "Videos", Status: store.OutboxStatusSent}, nil)
+	mockStore.EXPECT().GetOutboxMessage("missing").Return(nil, nil)
+	handler := NewOutboxHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/outbox/msg1", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues("msg1")
+	require.NoError(t, handler.GetOutboxMessage(c))
+
+	var response types.OutboxMessageResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "<p>Videos</p>", response.HTML)
+	assert.Equal(t, "Videos", response.Text)
+
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/api/outbox/missing", nil), httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("missing")
+	httpErr, ok := handler.GetOutboxMessage(c).(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, httpErr.Code)
+}
+
+func TestResendOutboxMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	outbox := expectOutbox(mockStore)
+	for _, message := range []*store.OutboxMessage{
+		{ID: "dead", Recipient: "me@example.com", Subject: newsletter.Subject, HTML: "<p>Videos</p>", Status: store.OutboxStatusDead, Attempts: 5, Error: "timeout"},
+		{ID: "sent", Recipient: "me@example.com", Subject: newsletter.Subject, Status: store.OutboxStatusSent, Attempts: 1},
+	} {
+		require.NoError(t, mockStore.SaveOutboxMessage(message))
+	}
+	mockStore.EXPECT().GetOutboxMessage(gomock.Any()).DoAndReturn(func(id string) (*store.OutboxMessage, error) {
+		if message, ok := outbox[id]; ok {
+			return &message, nil
+		}
+		return nil, nil
+	}).AnyTimes()
+
+	sender := &email.MockSender{}
+	base := &BaseHandlers{store: mockStore, emailSender: sender}
+	handler := NewOutboxHandlers(base, newsletter.NewRunner(mockStore, &stubChannelProcessor{}, notify.NewDispatcher(sender, nil), nil, nil))
+
+	resend := func(id string) (*httptest.ResponseRecorder, error) {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/api/outbox/"+id+"/resend", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		return rec, handler.ResendOutboxMessage(c)
+	}
+
+	rec, err := resend("dead")
+	require.NoError(t, err)
+	var response types.OutboxMessageResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "sent", response.Status)
+	assert.Equal(t, 6, response.Attempts)
+	assert.Empty(t, response.Error)
+	require.Len(t, sender.SentEmails, 1)
+	assert.Equal(t, "<p>Videos</p>", sender.SentEmails[0].Body)
+
+	for id, code := range map[string]int{"sent": http.StatusConflict, "missing": http.StatusNotFound} {
+		_, err := resend(id)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok, id)
+		assert.Equal(t, code, httpErr.Code, id)
+	}
+}
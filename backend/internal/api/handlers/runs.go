@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"youtube-curator-v2/internal/api/types"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultRunsLimit = 50
+	maxRunsLimit     = 500
+)
+
+// RunHandlers provides handlers for the newsletter run history endpoints
+type RunHandlers struct {
+	*BaseHandlers
+}
+
+// NewRunHandlers creates a new instance of run handlers
+func NewRunHandlers(base *BaseHandlers) *RunHandlers {
+	return &RunHandlers{BaseHandlers: base}
+}
+
+// GetRuns handles GET /api/runs
+// Returns the most recent runs first; ?limit= caps how many (default 50, at most 500)
+func (h *RunHandlers) GetRuns(c echo.Context) error {
+	limit, err := parseIntParam(c, "limit")
+	if err != nil {
+		return err
+	}
+	if limit == 0 {
+		limit = defaultRunsLimit
+	}
+	if limit > maxRunsLimit {
+		limit = maxRunsLimit
+	}
+
+	runs, err := h.store.GetRuns(limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve runs")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformRuns(runs))
+}
+
+// GetRun handles GET /api/runs/:id
+func (h *RunHandlers) GetRun(c echo.Context) error {
+	runID := c.Param("id")
+	if runID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Run ID is required")
+	}
+
+	run, err := h.store.GetRun(runID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve run")
+	}
+	if run == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Run not found")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformRun(*run))
+}
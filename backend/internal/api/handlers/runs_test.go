@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/store"
+)
+
+func TestGetRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	finished := time.Now()
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetRuns(defaultRunsLimit).Return([]store.Run{{
+		ID:             "run1",
+		Trigger:        store.RunTriggerScheduled,
+		Status:         store.RunStatusSucceeded,
+		StartedAt:      finished.Add(-time.Minute),
+		FinishedAt:     &finished,
+		NewVideosFound: 3,
+		Channels: []store.RunChannel{
+			{ChannelID: "UCone", ChannelTitle: "One", NewVideos: 3},
+			{ChannelID: "UCtwo", ChannelTitle: "Two", Error: "feed unavailable"},
+		},
+		Emails: []store.RunEmail{
+			{Recipient: "a@example.com", Videos: 3, Status: store.EmailStatusSent},
+			{Recipient: "b@example.com", Videos: 1, Status: store.EmailStatusFailed, Error: "mailbox full"},
+			{Recipient: "c@example.com", Status: store.EmailStatusSkipped},
+		},
+	}}, nil)
+	handler := NewRunHandlers(&BaseHandlers{store: mockStore})
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/runs", nil), rec)
+	require.NoError(t, handler.GetRuns(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response types.RunsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, 1, response.TotalCount)
+	run := response.Runs[0]
+	assert.Equal(t, "scheduled", run.Trigger)
+	assert.Equal(t, "succeeded", run.Status)
+	assert.Equal(t, 1, run.ChannelsProcessed)
+	assert.Equal(t, 1, run.ChannelsWithError)
+	assert.Equal(t, 1, run.EmailsSent)
+	assert.Equal(t, 1, run.EmailsFailed)
+	assert.Equal(t, "feed unavailable", run.Channels[1].Error)
+	assert.Equal(t, "skipped", run.Emails[2].Status)
+}
+
+func TestGetRuns_Limit(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedLimit int
+		expectedCode  int
+	}{
+		{name: "custom limit", query: "?limit=5", expectedLimit: 5},
+		{name: "limit is capped", query: "?limit=10000", expectedLimit: maxRunsLimit},
+		{name: "invalid limit", query: "?limit=-1", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := store.NewMockStore(ctrl)
+			if tt.expectedLimit > 0 {
+				mockStore.EXPECT().GetRuns(tt.expectedLimit).Return(nil, nil)
+			}
+			handler := NewRunHandlers(&BaseHandlers{store: mockStore})
+
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/runs"+tt.query, nil), rec)
+			err := handler.GetRuns(c)
+
+			if tt.expectedCode != 0 {
+				require.Error(t, err)
+				httpErr, ok := err.(*echo.HTTPError)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedCode, httpErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"runs":[],"totalCount":0}`, rec.Body.String())
+		})
+	}
+}
+
+func TestGetRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetRun("run1").Return(&store.Run{ID: "run1", Trigger: store.RunTriggerManual, Status: store.RunStatusRunning}, nil)
+	mockStore.EXPECT().GetRun("missing").Return(nil, nil)
+	handler := NewRunHandlers(&BaseHandlers{store: mockStore})
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/runs/run1", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues("run1")
+	require.NoError(t, handler.GetRun(c))
+
+	var response types.RunResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "run1", response.ID)
+	assert.Equal(t, "running", response.Status)
+	assert.Nil(t, response.FinishedAt)
+
+	c = echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/runs/missing", nil), httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("missing")
+	err := handler.GetRun(c)
+	require.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, httpErr.Code)
+}
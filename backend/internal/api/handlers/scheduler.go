@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/scheduler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SchedulerHandlers provides handlers for the newsletter scheduler endpoints
+type SchedulerHandlers struct {
+	*BaseHandlers
+	scheduler *scheduler.Scheduler
+}
+
+// NewSchedulerHandlers creates a new instance of scheduler handlers
+func NewSchedulerHandlers(base *BaseHandlers, scheduler *scheduler.Scheduler) *SchedulerHandlers {
+	return &SchedulerHandlers{BaseHandlers: base, scheduler: scheduler}
+}
+
+// GetSchedulerStatus handles GET /api/scheduler
+func (h *SchedulerHandlers) GetSchedulerStatus(c echo.Context) error {
+	if h.scheduler == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "Scheduler not running")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformSchedulerStatus(h.scheduler.Status()))
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/search"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchHandlers provides handlers for full-text search
+type SearchHandlers struct {
+	*BaseHandlers
+	index *search.Index
+}
+
+// NewSearchHandlers creates a new instance of search handlers
+func NewSearchHandlers(base *BaseHandlers, index *search.Index) *SearchHandlers {
+	return &SearchHandlers{BaseHandlers: base, index: index}
+}
+
+// Search handles GET /api/search?q= - returns ranked videos matching every query term
+func (h *SearchHandlers) Search(c echo.Context) error {
+	if h.index == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Search index not initialized")
+	}
+
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if query == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Query parameter q is required")
+	}
+
+	limit, err := parseIntParam(c, "limit")
+	if err != nil {
+		return err
+	}
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	hits, total := h.index.SearchWithTotal(query, limit)
+
+	response := types.SearchResponse{
+		Query:      query,
+		Hits:       make([]types.SearchHitResponse, 0, len(hits)),
+		TotalCount: total,
+	}
+	for _, hit := range hits {
+		video, err := h.store.GetVideo(hit.VideoID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to load video")
+		}
+		response.Hits = append(response.Hits, types.TransformSearchHit(hit, video))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
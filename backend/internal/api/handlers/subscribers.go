@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SubscriberHandlers provides handlers for newsletter subscriber endpoints
+type SubscriberHandlers struct {
+	*BaseHandlers
+	notifier notify.Notifier
+}
+
+// NewSubscriberHandlers creates a new instance of subscriber handlers. Test notifications are
+// sent through notifier.
+func NewSubscriberHandlers(base *BaseHandlers, notifier notify.Notifier) *SubscriberHandlers {
+	return &SubscriberHandlers{BaseHandlers: base, notifier: notifier}
+}
+
+// GetSubscribers handles GET /api/subscribers
+func (h *SubscriberHandlers) GetSubscribers(c echo.Context) error {
+	subscribers, err := h.store.GetSubscribers()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve subscribers")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformSubscribers(subscribers))
+}
+
+// GetSubscriber handles GET /api/subscribers/:id
+func (h *SubscriberHandlers) GetSubscriber(c echo.Context) error {
+	subscriber, err := h.findSubscriber(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, types.TransformSubscriber(*subscriber))
+}
+
+// CreateSubscriber handles POST /api/subscribers
+func (h *SubscriberHandlers) CreateSubscriber(c echo.Context) error {
+	var req types.SubscriberRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	subscriber, err := h.subscriberFromRequest(req, nil)
+	if err != nil {
+		return err
+	}
+
+	id, err := newSubscriberID()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	now := time.Now()
+	subscriber.ID = id
+	subscriber.CreatedAt = now
+	subscriber.UpdatedAt = now
+
+	if err := h.store.SaveSubscriber(subscriber); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save subscriber")
+	}
+
+	return c.JSON(http.StatusCreated, types.TransformSubscriber(*subscriber))
+}
+
+// UpdateSubscriber handles PUT /api/subscribers/:id
+func (h *SubscriberHandlers) UpdateSubscriber(c echo.Context) error {
+	existing, err := h.findSubscriber(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req types.SubscriberRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	subscriber, err := h.subscriberFromRequest(req, existing)
+	if err != nil {
+		return err
+	}
+	subscriber.ID = existing.ID
+	subscriber.CreatedAt = existing.CreatedAt
+	subscriber.UpdatedAt = time.Now()
+
+	if err := h.store.SaveSubscriber(subscriber); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save subscriber")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformSubscriber(*subscriber))
+}
+
+// DeleteSubscriber handles DELETE /api/subscribers/:id
+func (h *SubscriberHandlers) DeleteSubscriber(c echo.Context) error {
+	subscriber, err := h.findSubscriber(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	if err := h.store.DeleteSubscriber(subscriber.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete subscriber")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// TestSubscriberNotification handles POST /api/subscribers/:id/test
+// Sends a test notification through the subscriber's notification channel
+func (h *SubscriberHandlers) TestSubscriberNotification(c echo.Context) error {
+	subscriber, err := h.findSubscriber(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var target store.NotifyTarget
+	if subscriber.Notify != nil {
+		target = *subscriber.Notify
+	}
+	if target.Type == "" {
+		target.Type = notify.TypeEmail
+	}
+
+	start := time.Now()
+	sendErr := h.notifier.Notify(c.Request().Context(), target, notify.Message{
+		Recipient: subscriber.Email,
+		Subject:   "YouTube Curator test notification",
+		Content:   email.FormatTestEmail(start),
+	})
+	response := types.NotifyTestResponse{
+		Success:    sendErr == nil,
+		Type:       target.Type,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if sendErr != nil {
+		response.Message = "Failed to send test notification"
+		response.Error = sendErr.Error()
+	} else {
+		response.Message = fmt.Sprintf("Test notification sent to %s by %s", subscriber.Email, target.Type)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// findSubscriber loads a subscriber by ID, returning a 404 error if it doesn't exist
+func (h *SubscriberHandlers) findSubscriber(subscriberID string) (*store.Subscriber, error) {
+	if subscriberID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Subscriber ID is required")
+	}
+
+	subscriber, err := h.store.GetSubscriber(subscriberID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve subscriber")
+	}
+	if subscriber == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Subscriber not found")
+	}
+	return subscriber, nil
+}
+
+// subscriberFromRequest validates a subscriber request, for a new subscriber when existing is nil.
+// The email address must be unique across the other subscribers, and every channel must be one of
+// the configured channels.
+func (h *SubscriberHandlers) subscriberFromRequest(req types.SubscriberRequest, existing *store.Subscriber) (*store.Subscriber, error) {
+	selfID := ""
+	if existing != nil {
+		selfID = existing.ID
+	}
+	if strings.TrimSpace(req.Email) == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Email is required")
+	}
+	address, err := mail.ParseAddress(req.Email)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid email address")
+	}
+
+	subscribers, err := h.store.GetSubscribers()
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve subscribers")
+	}
+	for _, other := range subscribers {
+		if other.ID != selfID && strings.EqualFold(other.Email, address.Address) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "A subscriber with this email already exists")
+		}
+	}
+
+	subscriber := &store.Subscriber{
+		Email:   address.Address,
+		Enabled: req.Enabled == nil || *req.Enabled,
+	}
+
+	if req.Notify != nil && req.Notify.Type != "" && req.Notify.Type != notify.TypeEmail {
+		target := store.NotifyTarget{
+			Type:   req.Notify.Type,
+			URL:    strings.TrimSpace(req.Notify.URL),
+			Secret: req.Notify.Secret,
+			Token:  req.Notify.Token,
+		}
+		// Blank secrets keep the saved ones, as long as they would go to the same place
+		if existing != nil && existing.Notify != nil && existing.Notify.Type == target.Type && existing.Notify.URL == target.URL {
+			if target.Secret == "" {
+				target.Secret = existing.Notify.Secret
+			}
+			if target.Token == "" {
+				target.Token = existing.Notify.Token
+			}
+		}
+		if err := notify.ValidateTarget(target); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		subscriber.Notify = &target
+	}
+
+	if len(req.ChannelIDs) > 0 {
+		channels, err := h.store.GetChannels()
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve channels")
+		}
+		configured := make(map[string]bool, len(channels))
+		for _, channel := range channels {
+			configured[channel.ID] = true
+		}
+
+		seen := make(map[string]bool)
+		for _, channelID := range req.ChannelIDs {
+			if err := rss.ValidateChannelID(channelID); err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			if !configured[channelID] {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Channel not found: %s", channelID))
+			}
+			if !seen[channelID] {
+				seen[channelID] = true
+				subscriber.ChannelIDs = append(subscriber.ChannelIDs, channelID)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range req.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		subscriber.Tags = append(subscriber.Tags, tag)
+	}
+
+	return subscriber, nil
+}
+
+// newSubscriberID generates a random hex subscriber identifier
+func newSubscriberID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate subscriber ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
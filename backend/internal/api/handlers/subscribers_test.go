@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/store"
+)
+
+const testSubscriberChannelID = "UCAYF6ZY9gWBR1GW3R7PX7yw"
+
+func newSubscriberContext(method, target, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, bytes.NewReader([]byte(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	return e.NewContext(req, rec), rec
+}
+
+func TestCreateSubscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	handler := NewSubscriberHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: testSubscriberChannelID, Title: "Channel"}}, nil)
+	var saved *store.Subscriber
+	mockStore.EXPECT().SaveSubscriber(gomock.Any()).DoAndReturn(func(subscriber *store.Subscriber) error {
+		saved = subscriber
+		return nil
+	})
+
+	c, rec := newSubscriberContext(http.MethodPost, "/api/subscribers",
+		`{"email":"Alice <alice@example.com>","channelIds":["`+testSubscriberChannelID+`","`+testSubscriberChannelID+`"],"tags":[" golang ","Golang",""]}`)
+	require.NoError(t, handler.CreateSubscriber(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var response types.SubscriberResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.ID)
+	assert.Equal(t, "alice@example.com", response.Email)
+	assert.Equal(t, []string{testSubscriberChannelID}, response.ChannelIDs)
+	assert.Equal(t, []string{"golang"}, response.Tags)
+	assert.True(t, response.Enabled, "Subscribers are enabled by default")
+
+	require.NotNil(t, saved)
+	assert.Equal(t, response.ID, saved.ID)
+	assert.False(t, saved.CreatedAt.IsZero())
+}
+
+func TestCreateSubscriber_Validation(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setup          func(mockStore *store.MockStore)
+		expectedStatus int
+	}{
+		{
+			name:           "missing email",
+			body:           `{"channelIds":[]}`,
+			setup:          func(mockStore *store.MockStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid email",
+			body:           `{"email":"not-an-email"}`,
+			setup:          func(mockStore *store.MockStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate email",
+			body: `{"email":"ALICE@example.com"}`,
+			setup: func(mockStore *store.MockStore) {
+				mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{{ID: "existing", Email: "alice@example.com"}}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "invalid channel ID",
+			body: `{"email":"alice@example.com","channelIds":["not-a-channel"]}`,
+			setup: func(mockStore *store.MockStore) {
+				mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+				mockStore.EXPECT().GetChannels().Return(nil, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "channel not configured",
+			body: `{"email":"alice@example.com","channelIds":["` + testSubscriberChannelID + `"]}`,
+			setup: func(mockStore *store.MockStore) {
+				mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+				mockStore.EXPECT().GetChannels().Return([]store.Channel{}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown notification channel",
+			body: `{"email":"alice@example.com","notify":{"type":"carrier-pigeon","url":"https://example.com"}}`,
+			setup: func(mockStore *store.MockStore) {
+				mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "webhook without a URL",
+			body: `{"email":"alice@example.com","notify":{"type":"webhook"}}`,
+			setup: func(mockStore *store.MockStore) {
+				mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "gotify without a token",
+			body: `{"email":"alice@example.com","notify":{"type":"gotify","url":"https://gotify.example.com"}}`,
+			setup: func(mockStore *store.MockStore) {
+				mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := store.NewMockStore(ctrl)
+			tt.setup(mockStore)
+			handler := NewSubscriberHandlers(&BaseHandlers{store: mockStore}, nil)
+
+			c, _ := newSubscriberContext(http.MethodPost, "/api/subscribers", tt.body)
+			err := handler.CreateSubscriber(c)
+
+			require.Error(t, err)
+			httpErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedStatus, httpErr.Code)
+		})
+	}
+}
+
+func TestUpdateSubscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	created := time.Now().Add(-time.Hour)
+	existing := &store.Subscriber{ID: "abc", Email: "alice@example.com", Enabled: true, CreatedAt: created, UpdatedAt: created}
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetSubscriber("abc").Return(existing, nil)
+	// The subscriber's own address doesn't count as a duplicate
+	mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{*existing}, nil)
+	mockStore.EXPECT().SaveSubscriber(gomock.Any()).Return(nil)
+	handler := NewSubscriberHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	c, rec := newSubscriberContext(http.MethodPut, "/api/subscribers/abc", `{"email":"alice@example.com","tags":["rust"],"enabled":false}`)
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+	require.NoError(t, handler.UpdateSubscriber(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response types.SubscriberResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "abc", response.ID)
+	assert.False(t, response.Enabled)
+	assert.Equal(t, []string{"rust"}, response.Tags)
+	assert.Equal(t, []string{}, response.ChannelIDs)
+	assert.True(t, response.CreatedAt.Equal(created))
+	assert.True(t, response.UpdatedAt.After(created))
+}
+
+func TestUpdateSubscriber_NotifyTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	existing := &store.Subscriber{ID: "abc", Email: "alice@example.com", Enabled: true,
+		Notify: &store.NotifyTarget{Type: "webhook", URL: "https://hooks.example.com/videos", Secret: "saved-secret"}}
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetSubscriber("abc").Return(existing, nil).Times(2)
+	mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{*existing}, nil).Times(2)
+	var saved []store.Subscriber
+	mockStore.EXPECT().SaveSubscriber(gomock.Any()).DoAndReturn(func(subscriber *store.Subscriber) error {
+		saved = append(saved, *subscriber)
+		return nil
+	}).Times(2)
+	handler := NewSubscriberHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	update := func(body string) types.SubscriberResponse {
+		c, rec := newSubscriberContext(http.MethodPut, "/api/subscribers/abc", body)
+		c.SetParamNames("id")
+		c.SetParamValues("abc")
+		require.NoError(t, handler.UpdateSubscriber(c))
+		var response types.SubscriberResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	// A blank secret keeps the saved one for the same URL, and is never returned
+	response := update(`{"email":"alice@example.com","notify":{"type":"webhook","url":"https://hooks.example.com/videos"}}`)
+	assert.Equal(t, types.NotifyTargetResponse{Type: "webhook", URL: "https://hooks.example.com/videos", SecretSet: true}, response.Notify)
+	assert.Equal(t, "saved-secret", saved[0].Notify.Secret)
+	assert.NotContains(t, func() string { b, _ := json.Marshal(response); return string(b) }(), "saved-secret")
+
+	// Switching back to email drops the target
+	response = update(`{"email":"alice@example.com","notify":{"type":"email"}}`)
+	assert.Equal(t, types.NotifyTargetResponse{Type: "email"}, response.Notify)
+	assert.Nil(t, saved[1].Notify)
+}
+
+func TestTestSubscriberNotification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetSubscriber("abc").Return(&store.Subscriber{ID: "abc", Email: "alice@example.com",
+		Notify: &store.NotifyTarget{Type: "webhook", URL: server.URL, Secret: "s3cret"}}, nil)
+	handler := NewSubscriberHandlers(&BaseHandlers{store: mockStore}, notify.NewDispatcher(&email.MockSender{}, server.Client()))
+
+	c, rec := newSubscriberContext(http.MethodPost, "/api/subscribers/abc/test", "")
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+	require.NoError(t, handler.TestSubscriberNotification(c))
+
+	var response types.NotifyTestResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.Success, response.Error)
+	assert.Equal(t, "webhook", response.Type)
+	require.NotNil(t, received)
+	assert.Equal(t, notify.Sign("s3cret", body), received.Header.Get(notify.SignatureHeader))
+}
+
+func TestGetAndDeleteSubscriber_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetSubscriber("missing").Return(nil, nil).Times(2)
+	handler := NewSubscriberHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	for _, run := range []func(echo.Context) error{handler.GetSubscriber, handler.DeleteSubscriber} {
+		c, _ := newSubscriberContext(http.MethodGet, "/api/subscribers/missing", "")
+		c.SetParamNames("id")
+		c.SetParamValues("missing")
+
+		err := run(c)
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	}
+}
+
+func TestDeleteSubscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetSubscriber("abc").Return(&store.Subscriber{ID: "abc", Email: "alice@example.com"}, nil)
+	mockStore.EXPECT().DeleteSubscriber("abc").Return(nil)
+	handler := NewSubscriberHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	c, rec := newSubscriberContext(http.MethodDelete, "/api/subscribers/abc", "")
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+
+	require.NoError(t, handler.DeleteSubscriber(c))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
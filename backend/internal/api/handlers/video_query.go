@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultVideosPageSize = 50
+	maxVideosPageSize     = 500
+)
+
+// videoListParams holds the parsed query parameters of GET /api/videos
+type videoListParams struct {
+	query  store.VideoQuery
+	page   int
+	limit  int // 0 returns every matching video
+	cursor *videoCursor
+}
+
+// videoCursor identifies the last video of a page so the next page starts after it,
+// even if videos were added or removed in between
+type videoCursor struct {
+	ID        string    `json:"id"`
+	Published time.Time `json:"published"`
+	Title     string    `json:"title"`
+	Duration  int       `json:"duration"`
+}
+
+// parseVideoListParams reads the filter, sort and pagination parameters of GET /api/videos
+func parseVideoListParams(c echo.Context) (videoListParams, error) {
+	params := videoListParams{page: 1}
+	query := &params.query
+
+	query.ChannelID = c.QueryParam("channelId")
+
+	switch watched := c.QueryParam("watched"); watched {
+	case "":
+	case "true", "false":
+		value := watched == "true"
+		query.Watched = &value
+	default:
+		return params, echo.NewHTTPError(http.StatusBadRequest, "watched must be true or false")
+	}
+
+	var err error
+	if query.PublishedAfter, err = parseDateParam(c, "publishedAfter"); err != nil {
+		return params, err
+	}
+	if query.PublishedBefore, err = parseDateParam(c, "publishedBefore"); err != nil {
+		return params, err
+	}
+	if query.MinDuration, err = parseIntParam(c, "minDuration"); err != nil {
+		return params, err
+	}
+	if query.MaxDuration, err = parseIntParam(c, "maxDuration"); err != nil {
+		return params, err
+	}
+	if query.MaxDuration > 0 && query.MinDuration > query.MaxDuration {
+		return params, echo.NewHTTPError(http.StatusBadRequest, "minDuration must not be greater than maxDuration")
+	}
+
+	switch sortBy := store.VideoSortField(c.QueryParam("sort")); sortBy {
+	case "", store.VideoSortPublished, store.VideoSortDuration:
+		query.SortBy = sortBy
+	case store.VideoSortTitle:
+		query.SortBy = sortBy
+		query.Ascending = true // Alphabetical unless asked otherwise
+	default:
+		return params, echo.NewHTTPError(http.StatusBadRequest, "sort must be one of published, title or duration")
+	}
+	switch order := c.QueryParam("order"); order {
+	case "":
+	case "asc", "desc":
+		query.Ascending = order == "asc"
+	default:
+		return params, echo.NewHTTPError(http.StatusBadRequest, "order must be asc or desc")
+	}
+
+	if params.limit, err = parseIntParam(c, "limit"); err != nil {
+		return params, err
+	}
+	if params.limit > maxVideosPageSize {
+		params.limit = maxVideosPageSize
+	}
+
+	if cursor := c.QueryParam("cursor"); cursor != "" {
+		if params.cursor, err = decodeVideoCursor(cursor); err != nil {
+			return params, echo.NewHTTPError(http.StatusBadRequest, "Invalid cursor")
+		}
+	} else if c.QueryParam("page") != "" {
+		if params.page, err = parseIntParam(c, "page"); err != nil {
+			return params, err
+		}
+		if params.page < 1 {
+			return params, echo.NewHTTPError(http.StatusBadRequest, "page must be at least 1")
+		}
+	}
+
+	// Paging without an explicit limit uses the default page size
+	if params.limit == 0 && (params.cursor != nil || c.QueryParam("page") != "") {
+		params.limit = defaultVideosPageSize
+	}
+
+	return params, nil
+}
+
+// paginateVideos selects the requested page of already sorted videos
+func paginateVideos(videos []store.VideoEntry, params videoListParams) ([]store.VideoEntry, *types.Pagination) {
+	total := len(videos)
+	if params.limit == 0 {
+		totalPages := 0
+		if total > 0 {
+			totalPages = 1
+		}
+		return videos, &types.Pagination{
+			CurrentPage:  1,
+			TotalPages:   totalPages,
+			TotalItems:   total,
+			ItemsPerPage: total,
+		}
+	}
+
+	start := (params.page - 1) * params.limit
+	if params.cursor != nil {
+		after := params.cursor.entry()
+		start = sort.Search(total, func(i int) bool {
+			return params.query.Less(after, videos[i])
+		})
+	}
+	if start > total {
+		start = total
+	}
+	end := start + params.limit
+	if end > total {
+		end = total
+	}
+	page := videos[start:end]
+
+	pagination := &types.Pagination{
+		CurrentPage:  start/params.limit + 1,
+		TotalPages:   (total + params.limit - 1) / params.limit,
+		TotalItems:   total,
+		ItemsPerPage: params.limit,
+		HasNext:      end < total,
+		HasPrevious:  start > 0,
+	}
+	if pagination.HasNext && len(page) > 0 {
+		pagination.NextCursor = encodeVideoCursor(page[len(page)-1])
+	}
+	return page, pagination
+}
+
+// entry returns a placeholder video holding the cursor's sort keys
+func (cursor *videoCursor) entry() store.VideoEntry {
+	var video store.VideoEntry
+	video.Entry.ID = cursor.ID
+	video.Entry.Published = cursor.Published
+	video.Entry.Title = cursor.Title
+	video.Entry.Duration = cursor.Duration
+	return video
+}
+
+// encodeVideoCursor builds an opaque cursor pointing just after video
+func encodeVideoCursor(video store.VideoEntry) string {
+	data, _ := json.Marshal(videoCursor{
+		ID:        video.Entry.ID,
+		Published: video.Entry.Published,
+		Title:     video.Entry.Title,
+		Duration:  video.Entry.Duration,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeVideoCursor parses a cursor produced by encodeVideoCursor
+func decodeVideoCursor(raw string) (*videoCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+	var cursor videoCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID == "" {
+		return nil, fmt.Errorf("cursor has no video ID")
+	}
+	return &cursor, nil
+}
+
+// parseIntParam parses a non-negative integer query parameter, returning 0 when it's absent
+func parseIntParam(c echo.Context, name string) (int, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s must be a non-negative integer", name))
+	}
+	return value, nil
+}
+
+// parseDateParam parses an RFC 3339 timestamp or YYYY-MM-DD date query parameter
+func parseDateParam(c echo.Context, name string) (time.Time, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s must be an RFC 3339 timestamp or YYYY-MM-DD date", name))
+}
@@ -2,14 +2,16 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"sort"
 	"strings"
 	"time"
 
 	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/summary"
 	"youtube-curator-v2/internal/videoid"
 
 	"github.com/labstack/echo/v4"
@@ -25,12 +27,18 @@ func NewVideoHandlers(base *BaseHandlers) *VideoHandlers {
 	return &VideoHandlers{BaseHandlers: base}
 }
 
-// GetVideos handles GET /api/videos - returns all videos from the video store
+// GetVideos handles GET /api/videos - returns videos from the video store,
+// optionally filtered, sorted and paginated
 func (h *VideoHandlers) GetVideos(c echo.Context) error {
 	if h.videoStore == nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Video store not initialized")
 	}
 
+	params, err := parseVideoListParams(c)
+	if err != nil {
+		return err
+	}
+
 	// Check for refresh parameter
 	refresh := c.QueryParam("refresh") == "true"
 
@@ -69,13 +77,14 @@ func (h *VideoHandlers) GetVideos(c echo.Context) error {
 		videos = h.videoStore.GetAllVideos()
 	}
 
-	// Sort videos by published date (newest first)
-	sort.Slice(videos, func(i, j int) bool {
-		return videos[i].Entry.Published.After(videos[j].Entry.Published)
-	})
+	// Filter and sort (newest first by default), then select the requested page
+	videos = params.query.Apply(videos)
+	page, pagination := paginateVideos(videos, params)
 
 	// Prepare response using the transformation function
-	response := types.TransformVideos(videos, h.videoStore.GetLastRefreshedAt())
+	response := types.TransformVideos(page, h.videoStore.GetLastRefreshedAt())
+	response.TotalCount = pagination.TotalItems
+	response.Pagination = pagination
 
 	return c.JSON(http.StatusOK, response)
 }
@@ -107,6 +116,7 @@ func (h *VideoHandlers) MarkVideoAsWatched(c echo.Context) error {
 }
 
 // GetVideoSummary handles GET /api/videos/:videoId/summary
+// Pass ?regenerate=true to bypass and overwrite a stored summary
 func (h *VideoHandlers) GetVideoSummary(c echo.Context) error {
 	rawVideoID := c.Param("videoId")
 	if rawVideoID == "" {
@@ -120,19 +130,19 @@ func (h *VideoHandlers) GetVideoSummary(c echo.Context) error {
 	}
 	videoID := vid.ToFull()
 
-	// Check if summary service is available
-	if h.summaryService == nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Summary service not available")
-	}
-
 	// Get or generate summary
 	ctx := c.Request().Context()
-	result := h.summaryService.GetOrGenerateSummary(ctx, videoID)
+	var result *summary.SummaryResult
+	if c.QueryParam("regenerate") == "true" {
+		result = h.summaryService.RegenerateSummary(ctx, videoID)
+	} else {
+		result = h.summaryService.GetOrGenerateSummary(ctx, videoID)
+	}
 
 	if result.Error != nil {
 		// Handle different types of errors
 		switch {
-		case strings.Contains(result.Error.Error(), "LLM not configured"):
+		case errors.Is(result.Error, summary.ErrLLMNotConfigured):
 			return echo.NewHTTPError(http.StatusServiceUnavailable, "LLM service not configured")
 		case strings.Contains(result.Error.Error(), "no subtitles available"):
 			return echo.NewHTTPError(http.StatusNotFound, "No subtitles available for this video")
@@ -146,9 +156,96 @@ func (h *VideoHandlers) GetVideoSummary(c echo.Context) error {
 		Summary:        result.Summary,
 		Thinking:       result.Thinking,
 		SourceLanguage: result.SourceLanguage,
+		Model:          result.Model,
 		GeneratedAt:    result.GeneratedAt.Format(time.RFC3339),
 		Tracked:        result.Tracked,
 	}
 
 	return c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// GetVideoSummaryStream handles GET /api/videos/:videoId/summary/stream
+// Progress phases, token deltas and thinking content are sent as Server-Sent Events,
+// followed by a final "done" event with the complete summary or an "error" event.
+func (h *VideoHandlers) GetVideoSummaryStream(c echo.Context) error {
+	rawVideoID := c.Param("videoId")
+	if rawVideoID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Video ID is required")
+	}
+
+	// Create and validate video ID
+	vid, err := videoid.NewFromRaw(rawVideoID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	videoID := vid.ToFull()
+
+	ctx := c.Request().Context()
+	events := make(chan summary.StreamEvent)
+	go h.summaryService.StreamSummary(ctx, videoID, c.QueryParam("regenerate") == "true", events)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	for event := range events {
+		var payload interface{}
+		switch event.Type {
+		case summary.StreamEventPhase:
+			payload = types.SummaryStreamPhase{Phase: event.Phase}
+		case summary.StreamEventToken, summary.StreamEventThinking:
+			payload = types.SummaryStreamText{Text: event.Text}
+		case summary.StreamEventDone:
+			payload = types.VideoSummaryResponse{
+				VideoID:        videoID,
+				Summary:        event.Result.Summary,
+				Thinking:       event.Result.Thinking,
+				SourceLanguage: event.Result.SourceLanguage,
+				Model:          event.Result.Model,
+				GeneratedAt:    event.Result.GeneratedAt.Format(time.RFC3339),
+				Tracked:        event.Result.Tracked,
+			}
+		case summary.StreamEventError:
+			payload = types.ErrorResponse{Error: event.Err.Error(), Code: summaryErrorCode(event.Err)}
+		default:
+			continue
+		}
+
+		if err := writeSSE(res, string(event.Type), payload); err != nil {
+			// Client went away; StreamSummary stops once the request context is cancelled
+			log.Printf("Failed to write summary stream event for video %s: %v", videoID, err)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// summaryErrorCode maps summary errors to stable codes for streamed error events
+func summaryErrorCode(err error) string {
+	switch {
+	case errors.Is(err, summary.ErrLLMNotConfigured):
+		return "llm_not_configured"
+	case strings.Contains(err.Error(), "no subtitles available"):
+		return "no_subtitles"
+	default:
+		return "generation_failed"
+	}
+}
+
+// writeSSE writes a single Server-Sent Event with a JSON payload and flushes it to the client
+func writeSSE(res *echo.Response, event string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
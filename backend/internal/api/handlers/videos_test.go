@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"youtube-curator-v2/internal/summary"
+)
+
+// unconfiguredSummaryService behaves like the provider's summary service before an LLM is configured
+type unconfiguredSummaryService struct{}
+
+func (unconfiguredSummaryService) GetOrGenerateSummary(ctx context.Context, videoID string) *summary.SummaryResult {
+	return &summary.SummaryResult{VideoID: videoID, Error: summary.ErrLLMNotConfigured}
+}
+
+func (unconfiguredSummaryService) RegenerateSummary(ctx context.Context, videoID string) *summary.SummaryResult {
+	return &summary.SummaryResult{VideoID: videoID, Error: summary.ErrLLMNotConfigured}
+}
+
+func (unconfiguredSummaryService) StreamSummary(ctx context.Context, videoID string, regenerate bool, events chan<- summary.StreamEvent) {
+	defer close(events)
+	events <- summary.StreamEvent{Type: summary.StreamEventError, Err: summary.ErrLLMNotConfigured}
+}
+
+func TestGetVideoSummary_LLMNotConfigured(t *testing.T) {
+	handler := NewVideoHandlers(&BaseHandlers{summaryService: unconfiguredSummaryService{}})
+
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/videos/dQw4w9WgXcQ/summary", nil), httptest.NewRecorder())
+	c.SetParamNames("videoId")
+	c.SetParamValues("dQw4w9WgXcQ")
+
+	err := handler.GetVideoSummary(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusServiceUnavailable, httpErr.Code)
+	}
+}
+
+func TestGetVideoSummaryStream_LLMNotConfigured(t *testing.T) {
+	handler := NewVideoHandlers(&BaseHandlers{summaryService: unconfiguredSummaryService{}})
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/videos/dQw4w9WgXcQ/summary/stream", nil), rec)
+	c.SetParamNames("videoId")
+	c.SetParamValues("dQw4w9WgXcQ")
+
+	assert.NoError(t, handler.GetVideoSummaryStream(c))
+	assert.Contains(t, rec.Body.String(), "event: error")
+	assert.Contains(t, rec.Body.String(), `"code":"llm_not_configured"`)
+}
+
+func TestSummaryErrorCode(t *testing.T) {
+	assert.Equal(t, "llm_not_configured", summaryErrorCode(fmt.Errorf("generate: %w", summary.ErrLLMNotConfigured)))
+	assert.Equal(t, "generation_failed", summaryErrorCode(fmt.Errorf("LLM request failed")))
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/webhooks"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = store.WebhookDeliveryHistoryLimit
+)
+
+// WebhookHandlers provides handlers for the outgoing webhook endpoints
+type WebhookHandlers struct {
+	*BaseHandlers
+	dispatcher *webhooks.Dispatcher
+}
+
+// NewWebhookHandlers creates a new instance of webhook handlers. Deliveries are retried through
+// dispatcher.
+func NewWebhookHandlers(base *BaseHandlers, dispatcher *webhooks.Dispatcher) *WebhookHandlers {
+	return &WebhookHandlers{BaseHandlers: base, dispatcher: dispatcher}
+}
+
+// GetWebhooks handles GET /api/webhooks
+func (h *WebhookHandlers) GetWebhooks(c echo.Context) error {
+	hooks, err := h.store.GetWebhooks()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve webhooks")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformWebhooks(hooks))
+}
+
+// GetWebhook handles GET /api/webhooks/:id
+func (h *WebhookHandlers) GetWebhook(c echo.Context) error {
+	webhook, err := h.findWebhook(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, types.TransformWebhook(*webhook))
+}
+
+// CreateWebhook handles POST /api/webhooks
+func (h *WebhookHandlers) CreateWebhook(c echo.Context) error {
+	var req types.WebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	webhook, err := webhookFromRequest(req, nil)
+	if err != nil {
+		return err
+	}
+
+	id, err := newWebhookID()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	now := time.Now()
+	webhook.ID = id
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	if err := h.store.SaveWebhook(webhook); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save webhook")
+	}
+
+	return c.JSON(http.StatusCreated, types.TransformWebhook(*webhook))
+}
+
+// UpdateWebhook handles PUT /api/webhooks/:id
+func (h *WebhookHandlers) UpdateWebhook(c echo.Context) error {
+	existing, err := h.findWebhook(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req types.WebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	webhook, err := webhookFromRequest(req, existing)
+	if err != nil {
+		return err
+	}
+	webhook.ID = existing.ID
+	webhook.CreatedAt = existing.CreatedAt
+	webhook.UpdatedAt = time.Now()
+
+	if err := h.store.SaveWebhook(webhook); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save webhook")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformWebhook(*webhook))
+}
+
+// DeleteWebhook handles DELETE /api/webhooks/:id
+func (h *WebhookHandlers) DeleteWebhook(c echo.Context) error {
+	webhook, err := h.findWebhook(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	if err := h.store.DeleteWebhook(webhook.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete webhook")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries handles GET /api/webhooks/:id/deliveries
+// Returns the most recent deliveries first; ?limit= caps how many (default 50, at most 1000)
+func (h *WebhookHandlers) GetWebhookDeliveries(c echo.Context) error {
+	webhook, err := h.findWebhook(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	limit, err := parseIntParam(c, "limit")
+	if err != nil {
+		return err
+	}
+	if limit == 0 {
+		limit = defaultDeliveriesLimit
+	}
+	if limit > maxDeliveriesLimit {
+		limit = maxDeliveriesLimit
+	}
+
+	deliveries, err := h.store.GetWebhookDeliveries(webhook.ID, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve webhook deliveries")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformWebhookDeliveries(deliveries))
+}
+
+// RedeliverWebhookDelivery handles POST /api/webhooks/:id/deliveries/:deliveryId/redeliver
+// Posts a finished delivery's payload again and returns its new state
+func (h *WebhookHandlers) RedeliverWebhookDelivery(c echo.Context) error {
+	webhook, err := h.findWebhook(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	deliveryID := c.Param("deliveryId")
+	delivery, err := h.store.GetWebhookDelivery(deliveryID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve webhook delivery")
+	}
+	if delivery == nil || delivery.WebhookID != webhook.ID {
+		return echo.NewHTTPError(http.StatusNotFound, "Webhook delivery not found")
+	}
+
+	delivery, err = h.dispatcher.Redeliver(c.Request().Context(), deliveryID)
+	switch {
+	case errors.Is(err, webhooks.ErrDeliveryNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "Webhook delivery not found")
+	case errors.Is(err, webhooks.ErrDeliveryPending):
+		return echo.NewHTTPError(http.StatusConflict, "Webhook delivery is still pending")
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to redeliver webhook delivery")
+	}
+
+	return c.JSON(http.StatusOK, types.TransformWebhookDelivery(*delivery))
+}
+
+// findWebhook loads a webhook by ID, returning a 404 error if it doesn't exist
+func (h *WebhookHandlers) findWebhook(webhookID string) (*store.Webhook, error) {
+	if webhookID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Webhook ID is required")
+	}
+
+	webhook, err := h.store.GetWebhook(webhookID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve webhook")
+	}
+	if webhook == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Webhook not found")
+	}
+	return webhook, nil
+}
+
+// webhookFromRequest validates a webhook request, for a new webhook when existing is nil. A blank
+// secret keeps the saved one as long as the URL is unchanged.
+func webhookFromRequest(req types.WebhookRequest, existing *store.Webhook) (*store.Webhook, error) {
+	webhook := &store.Webhook{
+		URL:     strings.TrimSpace(req.URL),
+		Secret:  req.Secret,
+		Enabled: req.Enabled == nil || *req.Enabled,
+	}
+	if err := notify.ValidateTarget(store.NotifyTarget{Type: notify.TypeWebhook, URL: webhook.URL}); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if existing != nil && existing.URL == webhook.URL && webhook.Secret == "" {
+		webhook.Secret = existing.Secret
+	}
+
+	if len(req.Events) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "At least one event is required")
+	}
+	seen := make(map[string]bool)
+	for _, event := range req.Events {
+		known := false
+		for _, eventType := range webhooks.EventTypes {
+			known = known || event == eventType
+		}
+		if !known {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown event %q, must be one of %s", event, strings.Join(webhooks.EventTypes, ", ")))
+		}
+		if !seen[event] {
+			seen[event] = true
+			webhook.Events = append(webhook.Events, event)
+		}
+	}
+
+	return webhook, nil
+}
+
+// newWebhookID generates a random hex webhook identifier
+func newWebhookID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
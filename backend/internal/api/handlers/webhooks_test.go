@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"youtube-curator-v2/internal/api/types"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/webhooks"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	var saved *store.Webhook
+	mockStore.EXPECT().SaveWebhook(gomock.Any()).DoAndReturn(func(webhook *store.Webhook) error {
+		saved = webhook
+		return nil
+	})
+	handler := NewWebhookHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	c, rec := newSubscriberContext(http.MethodPost, "/api/webhooks",
+		`{"url":" https://hooks.example.com/curator ","secret":"s3cret","events":["video.discovered","summary.generated","video.discovered"]}`)
+	require.NoError(t, handler.CreateWebhook(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "s3cret")
+
+	var response types.WebhookResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.ID)
+	assert.Equal(t, "https://hooks.example.com/curator", response.URL)
+	assert.Equal(t, []string{webhooks.EventVideoDiscovered, webhooks.EventSummaryGenerated}, response.Events)
+	assert.True(t, response.SecretSet)
+	assert.True(t, response.Enabled, "Webhooks are enabled by default")
+
+	require.NotNil(t, saved)
+	assert.Equal(t, response.ID, saved.ID)
+	assert.Equal(t, "s3cret", saved.Secret)
+	assert.False(t, saved.CreatedAt.IsZero())
+}
+
+func TestCreateWebhook_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "missing URL", body: `{"events":["video.discovered"]}`},
+		{name: "invalid URL", body: `{"url":"ftp://hooks.example.com","events":["video.discovered"]}`},
+		{name: "no events", body: `{"url":"https://hooks.example.com","events":[]}`},
+		{name: "unknown event", body: `{"url":"https://hooks.example.com","events":["video.deleted"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := NewWebhookHandlers(&BaseHandlers{store: store.NewMockStore(ctrl)}, nil)
+
+			c, _ := newSubscriberContext(http.MethodPost, "/api/webhooks", tt.body)
+			err := handler.CreateWebhook(c)
+
+			require.Error(t, err)
+			httpErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+		})
+	}
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	created := time.Now().Add(-time.Hour)
+	existing := &store.Webhook{ID: "abc", URL: "https://hooks.example.com/curator", Secret: "saved-secret",
+		Events: []string{webhooks.EventVideoDiscovered}, Enabled: true, CreatedAt: created, UpdatedAt: created}
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetWebhook("abc").Return(existing, nil).Times(2)
+	var saved []store.Webhook
+	mockStore.EXPECT().SaveWebhook(gomock.Any()).DoAndReturn(func(webhook *store.Webhook) error {
+		saved = append(saved, *webhook)
+		return nil
+	}).Times(2)
+	handler := NewWebhookHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	update := func(body string) types.WebhookResponse {
+		c, rec := newSubscriberContext(http.MethodPut, "/api/webhooks/abc", body)
+		c.SetParamNames("id")
+		c.SetParamValues("abc")
+		require.NoError(t, handler.UpdateWebhook(c))
+		var response types.WebhookResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	// A blank secret keeps the saved one for the same URL
+	response := update(`{"url":"https://hooks.example.com/curator","events":["newsletter.sent"],"enabled":false}`)
+	assert.Equal(t, "abc", response.ID)
+	assert.True(t, response.SecretSet)
+	assert.False(t, response.Enabled)
+	assert.Equal(t, []string{webhooks.EventNewsletterSent}, response.Events)
+	assert.True(t, response.CreatedAt.Equal(created))
+	assert.True(t, response.UpdatedAt.After(created))
+	assert.Equal(t, "saved-secret", saved[0].Secret)
+
+	// ...but not when the URL changes
+	response = update(`{"url":"https://elsewhere.example.com/curator","events":["newsletter.sent"]}`)
+	assert.False(t, response.SecretSet)
+	assert.Empty(t, saved[1].Secret)
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetWebhook("abc").Return(&store.Webhook{ID: "abc"}, nil)
+	mockStore.EXPECT().GetWebhookDeliveries("abc", defaultDeliveriesLimit).Return([]store.WebhookDelivery{
+		{ID: "2", WebhookID: "abc", Event: webhooks.EventVideoDiscovered, Payload: `{"event":"video.discovered"}`, Status: store.WebhookDeliveryFailed, Attempts: 5, StatusCode: 503},
+		{ID: "1", WebhookID: "abc", Event: webhooks.EventVideoDiscovered, Status: store.WebhookDeliveryPending},
+	}, nil)
+	handler := NewWebhookHandlers(&BaseHandlers{store: mockStore}, nil)
+
+	c, rec := newSubscriberContext(http.MethodGet, "/api/webhooks/abc/deliveries", "")
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+	require.NoError(t, handler.GetWebhookDeliveries(c))
+
+	var response types.WebhookDeliveriesResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, 2, response.TotalCount)
+	assert.Equal(t, "failed", response.Deliveries[0].Status)
+	assert.Equal(t, 503, response.Deliveries[0].StatusCode)
+	assert.JSONEq(t, `{"event":"video.discovered"}`, string(response.Deliveries[0].Payload))
+	assert.Equal(t, "null", string(response.Deliveries[1].Payload))
+}
+
+func TestRedeliverWebhookDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	webhook := &store.Webhook{ID: "abc", URL: server.URL, Events: []string{webhooks.EventVideoDiscovered}, Enabled: true}
+	failed := &store.WebhookDelivery{ID: "d1", WebhookID: "abc", Event: webhooks.EventVideoDiscovered, URL: server.URL,
+		Payload: `{}`, Status: store.WebhookDeliveryFailed, Attempts: 5}
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetWebhook("abc").Return(webhook, nil).AnyTimes()
+	mockStore.EXPECT().GetWebhookDelivery("d1").Return(failed, nil).Times(2)
+	mockStore.EXPECT().GetWebhookDelivery("other").Return(&store.WebhookDelivery{ID: "other", WebhookID: "xyz"}, nil)
+	mockStore.EXPECT().SaveWebhookDelivery(gomock.Any()).Return(nil)
+	handler := NewWebhookHandlers(&BaseHandlers{store: mockStore}, webhooks.NewDispatcher(mockStore, server.Client()))
+
+	redeliver := func(deliveryID string) (*httptest.ResponseRecorder, error) {
+		c, rec := newSubscriberContext(http.MethodPost, "/api/webhooks/abc/deliveries/"+deliveryID+"/redeliver", "")
+		c.SetParamNames("id", "deliveryId")
+		c.SetParamValues("abc", deliveryID)
+		return rec, handler.RedeliverWebhookDelivery(c)
+	}
+
+	// Another webhook's delivery can't be redelivered through this one
+	_, err := redeliver("other")
+	require.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, httpErr.Code)
+
+	rec, err := redeliver("d1")
+	require.NoError(t, err)
+	var response types.WebhookDeliveryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "delivered", response.Status)
+	assert.Equal(t, 6, response.Attempts)
+	assert.Equal(t, 1, requests)
+}
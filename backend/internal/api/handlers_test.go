@@ -2,13 +2,16 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"youtube-curator-v2/internal/api/handlers"
+	"youtube-curator-v2/internal/api/types"
 	"youtube-curator-v2/internal/config"
+	"youtube-curator-v2/internal/email"
 	"youtube-curator-v2/internal/processor"
 	"youtube-curator-v2/internal/rss"
 	"youtube-curator-v2/internal/store"
@@ -16,6 +19,8 @@ import (
 	"youtube-curator-v2/internal/ytdlp"
 
 	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -45,7 +50,7 @@ func (m *MockFeedProvider) FetchFeed(ctx context.Context, channelID string) (*rs
 // MockEmailSender for testing
 type MockEmailSender struct{}
 
-func (m *MockEmailSender) Send(to, subject, body string) error {
+func (m *MockEmailSender) Send(to, subject string, content email.Content) error {
 	return nil
 }
 
@@ -60,6 +65,14 @@ func (m *MockChannelProcessor) ProcessChannelWithOptions(ctx context.Context, ch
 	return processor.ChannelResult{ChannelID: channelID}
 }
 
+func (m *MockChannelProcessor) CollectChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) processor.ChannelResult {
+	return processor.ChannelResult{ChannelID: channelID}
+}
+
+func (m *MockChannelProcessor) PreviewChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) processor.ChannelResult {
+	return processor.ChannelResult{ChannelID: channelID}
+}
+
 func TestGetVideos_EmptyCache_FetchesFromChannels(t *testing.T) {
 	// Setup
 	ctrl := gomock.NewController(t)
@@ -346,3 +359,107 @@ func TestGetVideos_WithRefreshParameter_PreservesWatchedState(t *testing.T) {
 		t.Error("Expected video to remain marked as watched after refresh")
 	}
 }
+
+func TestGetVideos_FiltersSortsAndPaginates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	videoStore := store.NewVideoStore(1 * time.Hour)
+
+	now := time.Now()
+	for i, title := range []string{"Delta", "alpha", "Charlie", "Bravo", "Echo"} {
+		entry := rss.Entry{
+			ID:        "yt:video:video" + string(rune('a'+i)),
+			Title:     title,
+			Published: now.Add(-time.Duration(i) * time.Hour),
+			Duration:  (i + 1) * 60,
+		}
+		channelID := "channel1"
+		if i == 4 {
+			channelID = "channel2"
+		}
+		videoStore.AddVideo(channelID, entry)
+	}
+	videoStore.MarkVideoAsWatched("yt:video:videob")
+
+	baseHandlers := handlers.NewBaseHandlers(mockStore, NewMockFeedProvider(), &MockEmailSender{}, &config.Config{}, &MockChannelProcessor{}, videoStore, ytdlp.NewMockEnricher(), summary.NewMockService(mockStore))
+	videoHandlers := handlers.NewVideoHandlers(baseHandlers)
+
+	get := func(query string) types.VideosResponse {
+		t.Helper()
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/videos?"+query, nil)
+		rec := httptest.NewRecorder()
+		if err := videoHandlers.GetVideos(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("GetVideos(%q) returned error: %v", query, err)
+		}
+		var response types.VideosResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+	titles := func(response types.VideosResponse) []string {
+		var result []string
+		for _, video := range response.Videos {
+			result = append(result, video.Title)
+		}
+		return result
+	}
+
+	// Default: every video, newest first, with pagination describing a single page
+	response := get("")
+	assert.Equal(t, []string{"Delta", "alpha", "Charlie", "Bravo", "Echo"}, titles(response))
+	require.NotNil(t, response.Pagination)
+	assert.Equal(t, 5, response.Pagination.TotalItems)
+	assert.False(t, response.Pagination.HasNext)
+
+	// Filters
+	assert.Equal(t, []string{"Echo"}, titles(get("channelId=channel2")))
+	assert.Equal(t, []string{"alpha"}, titles(get("watched=true")))
+	assert.Equal(t, []string{"alpha", "Charlie"}, titles(get("minDuration=120&maxDuration=180")))
+
+	// Sorting
+	assert.Equal(t, []string{"alpha", "Bravo", "Charlie", "Delta", "Echo"}, titles(get("sort=title")))
+	assert.Equal(t, []string{"Echo", "Bravo", "Charlie", "alpha", "Delta"}, titles(get("sort=duration")))
+
+	// Page-based pagination
+	response = get("sort=title&page=2&limit=2")
+	assert.Equal(t, []string{"Charlie", "Delta"}, titles(response))
+	assert.Equal(t, 2, response.Pagination.CurrentPage)
+	assert.Equal(t, 3, response.Pagination.TotalPages)
+	assert.True(t, response.Pagination.HasNext)
+	assert.True(t, response.Pagination.HasPrevious)
+
+	// Cursor-based pagination continues after the last video of the previous page
+	response = get("sort=title&limit=2")
+	assert.Equal(t, []string{"alpha", "Bravo"}, titles(response))
+	require.NotEmpty(t, response.Pagination.NextCursor)
+	response = get("sort=title&limit=2&cursor=" + response.Pagination.NextCursor)
+	assert.Equal(t, []string{"Charlie", "Delta"}, titles(response))
+}
+
+func TestGetVideos_InvalidQueryParameters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	videoStore := store.NewVideoStore(1 * time.Hour)
+	videoStore.AddVideo("channel1", rss.Entry{ID: "yt:video:video1", Title: "Video", Published: time.Now()})
+
+	baseHandlers := handlers.NewBaseHandlers(mockStore, NewMockFeedProvider(), &MockEmailSender{}, &config.Config{}, &MockChannelProcessor{}, videoStore, ytdlp.NewMockEnricher(), summary.NewMockService(mockStore))
+	videoHandlers := handlers.NewVideoHandlers(baseHandlers)
+
+	for _, query := range []string{"watched=maybe", "sort=views", "order=up", "page=0", "limit=-1", "publishedAfter=yesterday", "minDuration=10&maxDuration=5", "cursor=not-a-cursor"} {
+		t.Run(query, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/videos?"+query, nil)
+			rec := httptest.NewRecorder()
+			err := videoHandlers.GetVideos(e.NewContext(req, rec))
+			httpErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok, "expected an HTTP error, got %v", err)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+		})
+	}
+}
@@ -4,10 +4,16 @@ import (
 	"youtube-curator-v2/internal/api/handlers"
 	"youtube-curator-v2/internal/config"
 	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/jobs"
+	"youtube-curator-v2/internal/newsletter"
+	"youtube-curator-v2/internal/notify"
 	"youtube-curator-v2/internal/processor"
 	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/scheduler"
+	"youtube-curator-v2/internal/search"
 	"youtube-curator-v2/internal/store"
 	"youtube-curator-v2/internal/summary"
+	"youtube-curator-v2/internal/webhooks"
 	"youtube-curator-v2/internal/ytdlp"
 
 	"github.com/labstack/echo/v4"
@@ -15,7 +21,7 @@ import (
 )
 
 // SetupRouter creates and configures the Echo router with all API endpoints
-func SetupRouter(store store.Store, feedProvider rss.FeedProvider, emailSender email.Sender, cfg *config.Config, channelProcessor processor.ChannelProcessor, videoStore *store.VideoStore, ytdlpEnricher ytdlp.Enricher, summaryService summary.SummaryServiceInterface) *echo.Echo {
+func SetupRouter(store store.Store, feedProvider rss.FeedProvider, emailSender email.Sender, cfg *config.Config, channelProcessor processor.ChannelProcessor, videoStore *store.VideoStore, ytdlpEnricher ytdlp.Enricher, summaryService summary.SummaryServiceInterface, jobQueue *jobs.Queue, searchIndex *search.Index, notifier notify.Notifier, webhookDispatcher *webhooks.Dispatcher, newsletterRunner *newsletter.Runner, newsletterScheduler *scheduler.Scheduler) *echo.Echo {
 	e := echo.New()
 
 	// Middleware
@@ -28,9 +34,22 @@ func SetupRouter(store store.Store, feedProvider rss.FeedProvider, emailSender e
 
 	// Create domain-specific handlers
 	channelHandlers := handlers.NewChannelHandlers(baseHandlers)
-	configHandlers := handlers.NewConfigHandlers(baseHandlers)
+	configHandlers := handlers.NewConfigHandlers(baseHandlers, newsletterScheduler)
 	videoHandlers := handlers.NewVideoHandlers(baseHandlers)
-	newsletterHandlers := handlers.NewNewsletterHandlers(baseHandlers)
+	newsletterHandlers := handlers.NewNewsletterHandlers(baseHandlers, newsletterRunner)
+	jobHandlers := handlers.NewJobHandlers(baseHandlers, jobQueue)
+	searchHandlers := handlers.NewSearchHandlers(baseHandlers, searchIndex)
+	subscriberHandlers := handlers.NewSubscriberHandlers(baseHandlers, notifier)
+	schedulerHandlers := handlers.NewSchedulerHandlers(baseHandlers, newsletterScheduler)
+	runHandlers := handlers.NewRunHandlers(baseHandlers)
+	outboxHandlers := handlers.NewOutboxHandlers(baseHandlers, newsletterRunner)
+	webhookHandlers := handlers.NewWebhookHandlers(baseHandlers, webhookDispatcher)
+	feedHandlers := handlers.NewFeedHandlers(baseHandlers)
+
+	// Feeds are served outside /api so feed readers get short URLs
+	e.GET("/feeds/all.atom", feedHandlers.GetAllAtomFeed)
+	e.GET("/feeds/all.json", feedHandlers.GetAllJSONFeed)
+	e.GET("/feeds/channel/:id", feedHandlers.GetChannelAtomFeed)
 
 	// API routes
 	api := e.Group("/api")
@@ -39,6 +58,9 @@ func SetupRouter(store store.Store, feedProvider rss.FeedProvider, emailSender e
 	api.GET("/channels", channelHandlers.GetChannels)
 	api.POST("/channels", channelHandlers.AddChannel)
 	api.POST("/channels/import", channelHandlers.ImportChannels)
+	api.POST("/channels/import/opml", channelHandlers.ImportChannelsOPML)
+	api.POST("/channels/import/takeout", channelHandlers.ImportChannelsTakeout)
+	api.GET("/channels/export/opml", channelHandlers.ExportChannelsOPML)
 	api.DELETE("/channels/:id", channelHandlers.RemoveChannel)
 
 	// Configuration endpoints
@@ -46,18 +68,58 @@ func SetupRouter(store store.Store, feedProvider rss.FeedProvider, emailSender e
 	api.PUT("/config/interval", configHandlers.SetCheckInterval)
 	api.GET("/config/smtp", configHandlers.GetSMTPConfig)
 	api.PUT("/config/smtp", configHandlers.SetSMTPConfig)
+	api.POST("/config/smtp/test", configHandlers.TestSMTPConfig)
 	api.GET("/config/llm", configHandlers.GetLLMConfig)
 	api.PUT("/config/llm", configHandlers.SetLLMConfig)
+	api.POST("/config/llm/test", configHandlers.TestLLMConfig)
 	api.GET("/config/newsletter", configHandlers.GetNewsletterConfig)
 	api.PUT("/config/newsletter", configHandlers.SetNewsletterConfig)
 
 	// Newsletter endpoints
 	api.POST("/newsletter/run", newsletterHandlers.RunNewsletter)
+	api.GET("/newsletter/preview", newsletterHandlers.PreviewNewsletter)
+
+	// Scheduler endpoints
+	api.GET("/scheduler", schedulerHandlers.GetSchedulerStatus)
+
+	// Newsletter run history endpoints
+	api.GET("/runs", runHandlers.GetRuns)
+	api.GET("/runs/:id", runHandlers.GetRun)
+
+	// Newsletter outbox endpoints
+	api.GET("/outbox", outboxHandlers.GetOutboxMessages)
+	api.GET("/outbox/:id", outboxHandlers.GetOutboxMessage)
+	api.POST("/outbox/:id/resend", outboxHandlers.ResendOutboxMessage)
+
+	// Newsletter subscriber endpoints
+	api.GET("/subscribers", subscriberHandlers.GetSubscribers)
+	api.POST("/subscribers", subscriberHandlers.CreateSubscriber)
+	api.GET("/subscribers/:id", subscriberHandlers.GetSubscriber)
+	api.PUT("/subscribers/:id", subscriberHandlers.UpdateSubscriber)
+	api.DELETE("/subscribers/:id", subscriberHandlers.DeleteSubscriber)
+	api.POST("/subscribers/:id/test", subscriberHandlers.TestSubscriberNotification)
+
+	// Outgoing webhook endpoints
+	api.GET("/webhooks", webhookHandlers.GetWebhooks)
+	api.POST("/webhooks", webhookHandlers.CreateWebhook)
+	api.GET("/webhooks/:id", webhookHandlers.GetWebhook)
+	api.PUT("/webhooks/:id", webhookHandlers.UpdateWebhook)
+	api.DELETE("/webhooks/:id", webhookHandlers.DeleteWebhook)
+	api.GET("/webhooks/:id/deliveries", webhookHandlers.GetWebhookDeliveries)
+	api.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", webhookHandlers.RedeliverWebhookDelivery)
 
 	// Video endpoints
 	api.GET("/videos", videoHandlers.GetVideos)
 	api.POST("/videos/:videoId/watch", videoHandlers.MarkVideoAsWatched)
 	api.GET("/videos/:videoId/summary", videoHandlers.GetVideoSummary)
+	api.POST("/videos/:videoId/summary", jobHandlers.EnqueueVideoSummary)
+	api.GET("/videos/:videoId/summary/stream", videoHandlers.GetVideoSummaryStream)
+
+	// Search endpoints
+	api.GET("/search", searchHandlers.Search)
+
+	// Background job endpoints
+	api.GET("/jobs/:id", jobHandlers.GetJob)
 
 	return e
 }
@@ -14,11 +14,15 @@ type ConfigInterval struct {
 
 // SMTPConfigRequest represents a request to update SMTP configuration
 type SMTPConfigRequest struct {
-	Server         string `json:"server" validate:"required"`
-	Port           string `json:"port" validate:"required"`
-	Username       string `json:"username" validate:"required"`
-	Password       string `json:"password" validate:"required"`
-	RecipientEmail string `json:"recipientEmail" validate:"required,email"`
+	Server             string `json:"server" validate:"required"`
+	Port               string `json:"port" validate:"required"`
+	Username           string `json:"username"` // Empty for an unauthenticated relay
+	Password           string `json:"password"` // Required with a username
+	RecipientEmail     string `json:"recipientEmail" validate:"required,email"`
+	FromAddress        string `json:"fromAddress,omitempty" validate:"omitempty,email"` // Required without a username
+	FromName           string `json:"fromName,omitempty"`
+	TLSMode            string `json:"tlsMode,omitempty"` // "none", "starttls" or "implicit"
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
 }
 
 // LLMConfigRequest represents a request to update LLM configuration
@@ -30,7 +34,10 @@ type LLMConfigRequest struct {
 
 // NewsletterConfigRequest represents a request to update newsletter configuration
 type NewsletterConfigRequest struct {
-	Enabled bool `json:"enabled"`
+	Enabled             bool   `json:"enabled"`
+	MaxVideosPerChannel int    `json:"maxVideosPerChannel,omitempty"`
+	IncludeSummaries    bool   `json:"includeSummaries,omitempty"`
+	CronSchedule        string `json:"cronSchedule,omitempty"` // Overrides the check interval when set
 }
 
 // ImportChannelsRequest represents a request to import multiple channels
@@ -49,4 +56,31 @@ type RunNewsletterRequest struct {
 	ChannelID         string `json:"channelId,omitempty"`
 	IgnoreLastChecked bool   `json:"ignoreLastChecked,omitempty"`
 	MaxItems          int    `json:"maxItems,omitempty"`
-}
\ No newline at end of file
+	MaxPerChannel     int    `json:"maxPerChannel,omitempty"` // Overrides the configured per-channel cap when set
+	DryRun            bool   `json:"dryRun,omitempty"`        // Return the rendered emails instead of sending them
+}
+
+// SubscriberRequest represents a request to create or update a newsletter subscriber
+type SubscriberRequest struct {
+	Email      string               `json:"email" validate:"required"`
+	ChannelIDs []string             `json:"channelIds,omitempty"`
+	Tags       []string             `json:"tags,omitempty"`
+	Notify     *NotifyTargetRequest `json:"notify,omitempty"`  // Defaults to email
+	Enabled    *bool                `json:"enabled,omitempty"` // Defaults to true
+}
+
+// WebhookRequest represents a request to create or update an outgoing webhook
+type WebhookRequest struct {
+	URL     string   `json:"url" validate:"required"`
+	Secret  string   `json:"secret,omitempty"`  // Signing key; blank keeps the saved one for the same URL
+	Events  []string `json:"events"`            // video.discovered, summary.generated or newsletter.sent
+	Enabled *bool    `json:"enabled,omitempty"` // Defaults to true
+}
+
+// NotifyTargetRequest selects the notification channel a subscriber's digest is delivered through
+type NotifyTargetRequest struct {
+	Type   string `json:"type"`             // email, webhook, slack, discord, ntfy or gotify
+	URL    string `json:"url,omitempty"`    // Webhook URL, ntfy topic URL or Gotify server URL
+	Secret string `json:"secret,omitempty"` // Webhook signing key; blank keeps the saved one for the same URL
+	Token  string `json:"token,omitempty"`  // ntfy access token or Gotify application token; blank keeps the saved one for the same URL
+}
@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
@@ -11,12 +14,13 @@ type ErrorResponse struct {
 
 // Pagination represents pagination information
 type Pagination struct {
-	CurrentPage  int  `json:"currentPage"`
-	TotalPages   int  `json:"totalPages"`
-	TotalItems   int  `json:"totalItems"`
-	ItemsPerPage int  `json:"itemsPerPage"`
-	HasNext      bool `json:"hasNext"`
-	HasPrevious  bool `json:"hasPrevious"`
+	CurrentPage  int    `json:"currentPage"`
+	TotalPages   int    `json:"totalPages"`
+	TotalItems   int    `json:"totalItems"`
+	ItemsPerPage int    `json:"itemsPerPage"`
+	HasNext      bool   `json:"hasNext"`
+	HasPrevious  bool   `json:"hasPrevious"`
+	NextCursor   string `json:"nextCursor,omitempty"` // Pass as cursor to fetch the next page
 }
 
 // ChannelResponse represents a channel in API responses
@@ -105,20 +109,71 @@ type VideosResponse struct {
 
 // NewsletterRunResponse represents the response from triggering a newsletter run
 type NewsletterRunResponse struct {
-	Message           string `json:"message"`
-	ChannelsProcessed int    `json:"channelsProcessed"`
-	ChannelsWithError int    `json:"channelsWithError"`
-	NewVideosFound    int    `json:"newVideosFound"`
-	EmailSent         bool   `json:"emailSent"`
+	Message           string                    `json:"message"`
+	RunID             string                    `json:"runId,omitempty"` // Run history entry, see GET /api/runs/:id; empty for dry runs
+	ChannelsProcessed int                       `json:"channelsProcessed"`
+	ChannelsWithError int                       `json:"channelsWithError"`
+	NewVideosFound    int                       `json:"newVideosFound"`
+	EmailSent         bool                      `json:"emailSent"`
+	EmailsSent        int                       `json:"emailsSent"`   // Personalised digests delivered, one per recipient
+	EmailsFailed      int                       `json:"emailsFailed"` // Recipients whose digest could not be sent
+	DryRun            bool                      `json:"dryRun,omitempty"`
+	Emails            []NewsletterEmailResponse `json:"emails,omitempty"` // Rendered emails, only for dry runs
+}
+
+// NewsletterPreviewResponse represents the response for GET /api/newsletter/preview. The top-level
+// email is the one for the requested recipient, or the first one rendered.
+type NewsletterPreviewResponse struct {
+	ChannelsProcessed int                       `json:"channelsProcessed"`
+	ChannelsWithError int                       `json:"channelsWithError"`
+	NewVideosFound    int                       `json:"newVideosFound"`
+	Recipient         string                    `json:"recipient,omitempty"` // Empty when there are no recipients yet
+	Subject           string                    `json:"subject,omitempty"`
+	Videos            int                       `json:"videos"`
+	HTML              string                    `json:"html"` // Empty when there is nothing new
+	Text              string                    `json:"text"`
+	Emails            []NewsletterEmailResponse `json:"emails"` // Every rendered email, one per recipient
+}
+
+// NewsletterEmailResponse represents a newsletter email rendered for a recipient
+type NewsletterEmailResponse struct {
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject"`
+	Videos    int    `json:"videos"`
+	HTML      string `json:"html"`
+	Text      string `json:"text"`
 }
 
 // SMTPConfigResponse represents SMTP configuration in API responses (without password)
 type SMTPConfigResponse struct {
-	Server         string `json:"server"`
-	Port           string `json:"port"`
-	Username       string `json:"username"`
-	RecipientEmail string `json:"recipientEmail"`
-	PasswordSet    bool   `json:"passwordSet"`
+	Server             string `json:"server"`
+	Port               string `json:"port"`
+	Username           string `json:"username"`
+	RecipientEmail     string `json:"recipientEmail"`
+	PasswordSet        bool   `json:"passwordSet"`
+	FromAddress        string `json:"fromAddress"`
+	FromName           string `json:"fromName"`
+	TLSMode            string `json:"tlsMode"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+}
+
+// SMTPTestResponse reports the outcome of sending a test email
+type SMTPTestResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	Recipient  string `json:"recipient"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// LLMTestResponse reports the outcome of a test chat completion
+type LLMTestResponse struct {
+	Success       bool   `json:"success"`
+	Model         string `json:"model"`
+	LatencyMs     int64  `json:"latencyMs"`
+	Reply         string `json:"reply,omitempty"`
+	Error         string `json:"error,omitempty"`
+	ErrorCategory string `json:"errorCategory,omitempty"` // See openai.ErrorCategory
 }
 
 // LLMConfigResponse represents LLM configuration in API responses (without API key)
@@ -130,17 +185,79 @@ type LLMConfigResponse struct {
 
 // NewsletterConfigResponse represents newsletter configuration in API responses
 type NewsletterConfigResponse struct {
-	Enabled bool `json:"enabled"`
+	Enabled             bool   `json:"enabled"`
+	MaxVideosPerChannel int    `json:"maxVideosPerChannel"`
+	IncludeSummaries    bool   `json:"includeSummaries"`
+	CronSchedule        string `json:"cronSchedule,omitempty"`
 }
 
 // VideoSummaryResponse represents a video summary in API responses
 type VideoSummaryResponse struct {
 	VideoID        string `json:"videoId"`
 	Summary        string `json:"summary"`
-	Thinking       string `json:"thinking,omitempty"`  // LLM thinking content from <think> blocks
+	Thinking       string `json:"thinking,omitempty"` // LLM thinking content from <think> blocks
 	SourceLanguage string `json:"sourceLanguage"`
-	GeneratedAt    string `json:"generatedAt"` // ISO 8601 format
-	Tracked        bool   `json:"tracked"`     // Whether this video is from a tracked channel
+	Model          string `json:"model,omitempty"` // Model used to generate the summary
+	GeneratedAt    string `json:"generatedAt"`     // ISO 8601 format
+	Tracked        bool   `json:"tracked"`         // Whether this video is from a tracked channel
+}
+
+// SummaryStreamPhase is the payload of a "phase" event on the summary stream
+type SummaryStreamPhase struct {
+	Phase string `json:"phase"` // fetching_metadata, downloading_subtitles or generating
+}
+
+// SummaryStreamText is the payload of "token" and "thinking" events on the summary stream
+type SummaryStreamText struct {
+	Text string `json:"text"`
+}
+
+// JobResponse represents a background job in API responses
+type JobResponse struct {
+	ID         string                `json:"id"`
+	Type       string                `json:"type"`
+	VideoID    string                `json:"videoId"`
+	Status     string                `json:"status"` // queued, running, succeeded or failed
+	Error      string                `json:"error,omitempty"`
+	CreatedAt  time.Time             `json:"createdAt"`
+	StartedAt  *time.Time            `json:"startedAt,omitempty"`
+	FinishedAt *time.Time            `json:"finishedAt,omitempty"`
+	Summary    *VideoSummaryResponse `json:"summary,omitempty"` // Populated once a summary job has succeeded
+}
+
+// SubscriberResponse represents a newsletter subscriber in API responses
+type SubscriberResponse struct {
+	ID         string               `json:"id"`
+	Email      string               `json:"email"`
+	ChannelIDs []string             `json:"channelIds"`
+	Tags       []string             `json:"tags"`
+	Notify     NotifyTargetResponse `json:"notify"`
+	Enabled    bool                 `json:"enabled"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	UpdatedAt  time.Time            `json:"updatedAt"`
+}
+
+// NotifyTargetResponse represents a subscriber's notification channel, without its secrets
+type NotifyTargetResponse struct {
+	Type      string `json:"type"`
+	URL       string `json:"url,omitempty"`
+	SecretSet bool   `json:"secretSet"`
+	TokenSet  bool   `json:"tokenSet"`
+}
+
+// NotifyTestResponse reports the outcome of sending a test notification to a subscriber
+type NotifyTestResponse struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	Type       string `json:"type"` // Notification channel the test went through
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SubscribersResponse represents the response for GET /api/subscribers
+type SubscribersResponse struct {
+	Subscribers []SubscriberResponse `json:"subscribers"`
+	TotalCount  int                  `json:"totalCount"`
 }
 
 // ImportChannelsResponse represents the response from importing channels
@@ -159,4 +276,143 @@ type ImportFailure struct {
 type Channel struct {
 	ID    string `json:"id"`
 	Title string `json:"title"`
-}
\ No newline at end of file
+}
+
+// SearchResponse represents the response for GET /api/search
+type SearchResponse struct {
+	Query      string              `json:"query"`
+	Hits       []SearchHitResponse `json:"hits"`
+	TotalCount int                 `json:"totalCount"`
+}
+
+// SearchHitResponse represents a video matching a search query
+type SearchHitResponse struct {
+	VideoID      string                `json:"videoId"`
+	Title        string                `json:"title"`
+	ChannelID    string                `json:"channelId,omitempty"`
+	Published    *time.Time            `json:"published,omitempty"`
+	ThumbnailURL string                `json:"thumbnailUrl,omitempty"`
+	Score        float64               `json:"score"`
+	Matches      []SearchMatchResponse `json:"matches"`
+}
+
+// SearchMatchResponse represents a highlighted snippet of a matching field
+type SearchMatchResponse struct {
+	Field            string `json:"field"`                      // title, summary, description or transcript
+	Snippet          string `json:"snippet"`                    // HTML-escaped, matches wrapped in <mark></mark>
+	TimestampSeconds *int   `json:"timestampSeconds,omitempty"` // Offset into the video for transcript matches
+}
+
+// SchedulerStatusResponse represents the response for GET /api/scheduler
+type SchedulerStatusResponse struct {
+	Enabled         bool       `json:"enabled"`
+	Schedule        string     `json:"schedule"` // Cron expression, or "@every <interval>" for interval schedules
+	Source          string     `json:"source"`   // cron, interval or environment
+	NextRun         *time.Time `json:"nextRun,omitempty"`
+	LastRun         *time.Time `json:"lastRun,omitempty"`
+	LastRunFinished *time.Time `json:"lastRunFinished,omitempty"`
+	Running         bool       `json:"running"`
+	Error           string     `json:"error,omitempty"` // Why the stored schedule couldn't be applied
+}
+
+// RunResponse represents a newsletter run in API responses
+type RunResponse struct {
+	ID                string               `json:"id"`
+	Trigger           string               `json:"trigger"` // scheduled or manual
+	Status            string               `json:"status"`  // running, succeeded or failed
+	Error             string               `json:"error,omitempty"`
+	StartedAt         time.Time            `json:"startedAt"`
+	FinishedAt        *time.Time           `json:"finishedAt,omitempty"`
+	ChannelsProcessed int                  `json:"channelsProcessed"`
+	ChannelsWithError int                  `json:"channelsWithError"`
+	NewVideosFound    int                  `json:"newVideosFound"`
+	EmailsSent        int                  `json:"emailsSent"`
+	EmailsFailed      int                  `json:"emailsFailed"`
+	Channels          []RunChannelResponse `json:"channels"`
+	Emails            []RunEmailResponse   `json:"emails"`
+}
+
+// RunChannelResponse represents the outcome of checking one channel during a newsletter run
+type RunChannelResponse struct {
+	ChannelID    string `json:"channelId"`
+	ChannelTitle string `json:"channelTitle,omitempty"`
+	NewVideos    int    `json:"newVideos"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RunEmailResponse represents the delivery of one newsletter email during a run
+type RunEmailResponse struct {
+	Recipient string `json:"recipient"`
+	Videos    int    `json:"videos"`
+	Status    string `json:"status"` // sent, failed or skipped
+	Error     string `json:"error,omitempty"`
+	OutboxID  string `json:"outboxId,omitempty"` // Outbox message the email was sent from, see GET /api/outbox/:id
+}
+
+// RunsResponse represents the response for GET /api/runs
+type RunsResponse struct {
+	Runs       []RunResponse `json:"runs"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// OutboxMessageResponse represents a newsletter email in the outbox
+type OutboxMessageResponse struct {
+	ID        string     `json:"id"`
+	RunID     string     `json:"runId,omitempty"`
+	Recipient string     `json:"recipient"`
+	Subject   string     `json:"subject"`
+	HTML      string     `json:"html,omitempty"` // Only included for a single message
+	Text      string     `json:"text,omitempty"` // Only included for a single message
+	Videos    int        `json:"videos"`
+	Status    string     `json:"status"` // pending, sent or dead
+	Attempts  int        `json:"attempts"`
+	Error     string     `json:"error,omitempty"` // Last delivery error
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	SentAt    *time.Time `json:"sentAt,omitempty"`
+}
+
+// OutboxMessagesResponse represents the response for GET /api/outbox
+type OutboxMessagesResponse struct {
+	Messages   []OutboxMessageResponse `json:"messages"`
+	TotalCount int                     `json:"totalCount"`
+}
+
+// WebhookResponse represents an outgoing webhook in API responses, without its secret
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	SecretSet bool      `json:"secretSet"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WebhooksResponse represents the response for GET /api/webhooks
+type WebhooksResponse struct {
+	Webhooks   []WebhookResponse `json:"webhooks"`
+	TotalCount int               `json:"totalCount"`
+}
+
+// WebhookDeliveryResponse represents an event posted, or being posted, to a webhook
+type WebhookDeliveryResponse struct {
+	ID          string          `json:"id"`
+	WebhookID   string          `json:"webhookId"`
+	Event       string          `json:"event"`
+	URL         string          `json:"url"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"` // pending, delivered or failed
+	Attempts    int             `json:"attempts"`
+	StatusCode  int             `json:"statusCode,omitempty"` // HTTP status of the last failed attempt
+	Error       string          `json:"error,omitempty"`      // Last delivery error
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+	DeliveredAt *time.Time      `json:"deliveredAt,omitempty"`
+}
+
+// WebhookDeliveriesResponse represents the response for GET /api/webhooks/:id/deliveries
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	TotalCount int                       `json:"totalCount"`
+}
@@ -1,9 +1,15 @@
 package types
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
+	"youtube-curator-v2/internal/newsletter"
+	"youtube-curator-v2/internal/notify"
 	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/scheduler"
+	"youtube-curator-v2/internal/search"
 	"youtube-curator-v2/internal/store"
 )
 
@@ -117,4 +123,350 @@ func TransformVideos(videoEntries []store.VideoEntry, lastRefresh time.Time) Vid
 		TotalCount:  len(videoEntries),
 		LastRefresh: lastRefresh,
 	}
-}
\ No newline at end of file
+}
+
+// TransformJob converts a store.Job to JobResponse, attaching the summary when provided
+func TransformJob(job store.Job, summary *store.StoredSummary, tracked bool) JobResponse {
+	response := JobResponse{
+		ID:         job.ID,
+		Type:       job.Type,
+		VideoID:    job.VideoID,
+		Status:     string(job.Status),
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+
+	if summary != nil {
+		response.Summary = &VideoSummaryResponse{
+			VideoID:        summary.VideoID,
+			Summary:        summary.Text,
+			Thinking:       summary.Thinking,
+			SourceLanguage: summary.SourceLanguage,
+			Model:          summary.Model,
+			GeneratedAt:    summary.GeneratedAt.Format(time.RFC3339),
+			Tracked:        tracked,
+		}
+	}
+
+	return response
+}
+
+// TransformSubscriber converts a store.Subscriber to SubscriberResponse
+func TransformSubscriber(subscriber store.Subscriber) SubscriberResponse {
+	response := SubscriberResponse{
+		ID:         subscriber.ID,
+		Email:      subscriber.Email,
+		ChannelIDs: subscriber.ChannelIDs,
+		Tags:       subscriber.Tags,
+		Notify:     NotifyTargetResponse{Type: notify.TypeEmail},
+		Enabled:    subscriber.Enabled,
+		CreatedAt:  subscriber.CreatedAt,
+		UpdatedAt:  subscriber.UpdatedAt,
+	}
+	if target := subscriber.Notify; target != nil && target.Type != "" {
+		response.Notify = NotifyTargetResponse{
+			Type:      target.Type,
+			URL:       target.URL,
+			SecretSet: target.Secret != "",
+			TokenSet:  target.Token != "",
+		}
+	}
+	if response.ChannelIDs == nil {
+		response.ChannelIDs = []string{}
+	}
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+	return response
+}
+
+// TransformSubscribers converts a slice of store.Subscriber to SubscribersResponse
+func TransformSubscribers(subscribers []store.Subscriber) SubscribersResponse {
+	responses := make([]SubscriberResponse, len(subscribers))
+	for i, subscriber := range subscribers {
+		responses[i] = TransformSubscriber(subscriber)
+	}
+	return SubscribersResponse{
+		Subscribers: responses,
+		TotalCount:  len(responses),
+	}
+}
+
+// TransformRun converts a store.Run to RunResponse, counting the channel and email outcomes
+func TransformRun(run store.Run) RunResponse {
+	response := RunResponse{
+		ID:             run.ID,
+		Trigger:        string(run.Trigger),
+		Status:         string(run.Status),
+		Error:          run.Error,
+		StartedAt:      run.StartedAt,
+		FinishedAt:     run.FinishedAt,
+		NewVideosFound: run.NewVideosFound,
+		Channels:       make([]RunChannelResponse, len(run.Channels)),
+		Emails:         make([]RunEmailResponse, len(run.Emails)),
+	}
+
+	for i, channel := range run.Channels {
+		response.Channels[i] = RunChannelResponse{
+			ChannelID:    channel.ChannelID,
+			ChannelTitle: channel.ChannelTitle,
+			NewVideos:    channel.NewVideos,
+			Error:        channel.Error,
+		}
+		if channel.Error != "" {
+			response.ChannelsWithError++
+		} else {
+			response.ChannelsProcessed++
+		}
+	}
+
+	for i, sent := range run.Emails {
+		response.Emails[i] = RunEmailResponse{
+			Recipient: sent.Recipient,
+			Videos:    sent.Videos,
+			Status:    string(sent.Status),
+			Error:     sent.Error,
+			OutboxID:  sent.OutboxID,
+		}
+		switch sent.Status {
+		case store.EmailStatusSent:
+			response.EmailsSent++
+		case store.EmailStatusFailed:
+			response.EmailsFailed++
+		}
+	}
+
+	return response
+}
+
+// TransformRuns converts a slice of store.Run to RunsResponse
+func TransformRuns(runs []store.Run) RunsResponse {
+	responses := make([]RunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = TransformRun(run)
+	}
+	return RunsResponse{
+		Runs:       responses,
+		TotalCount: len(responses),
+	}
+}
+
+// TransformOutboxMessage converts a store.OutboxMessage to OutboxMessageResponse, with the
+// rendered email only if includeContent is set
+func TransformOutboxMessage(message store.OutboxMessage, includeContent bool) OutboxMessageResponse {
+	response := OutboxMessageResponse{
+		ID:        message.ID,
+		RunID:     message.RunID,
+		Recipient: message.Recipient,
+		Subject:   message.Subject,
+		Videos:    message.Videos,
+		Status:    string(message.Status),
+		Attempts:  message.Attempts,
+		Error:     message.Error,
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+		SentAt:    message.SentAt,
+	}
+	if includeContent {
+		response.HTML = message.HTML
+		response.Text = message.Text
+	}
+	return response
+}
+
+// TransformOutboxMessages converts a slice of store.OutboxMessage to OutboxMessagesResponse,
+// leaving out the rendered emails
+func TransformOutboxMessages(messages []store.OutboxMessage) OutboxMessagesResponse {
+	responses := make([]OutboxMessageResponse, len(messages))
+	for i, message := range messages {
+		responses[i] = TransformOutboxMessage(message, false)
+	}
+	return OutboxMessagesResponse{
+		Messages:   responses,
+		TotalCount: len(responses),
+	}
+}
+
+// TransformWebhook converts a store.Webhook to WebhookResponse
+func TransformWebhook(webhook store.Webhook) WebhookResponse {
+	response := WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		SecretSet: webhook.Secret != "",
+		Events:    webhook.Events,
+		Enabled:   webhook.Enabled,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+	if response.Events == nil {
+		response.Events = []string{}
+	}
+	return response
+}
+
+// TransformWebhooks converts a slice of store.Webhook to WebhooksResponse
+func TransformWebhooks(webhooks []store.Webhook) WebhooksResponse {
+	responses := make([]WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		responses[i] = TransformWebhook(webhook)
+	}
+	return WebhooksResponse{
+		Webhooks:   responses,
+		TotalCount: len(responses),
+	}
+}
+
+// TransformWebhookDelivery converts a store.WebhookDelivery to WebhookDeliveryResponse
+func TransformWebhookDelivery(delivery store.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:          delivery.ID,
+		WebhookID:   delivery.WebhookID,
+		Event:       delivery.Event,
+		URL:         delivery.URL,
+		Payload:     json.RawMessage(delivery.Payload),
+		Status:      string(delivery.Status),
+		Attempts:    delivery.Attempts,
+		StatusCode:  delivery.StatusCode,
+		Error:       delivery.Error,
+		CreatedAt:   delivery.CreatedAt,
+		UpdatedAt:   delivery.UpdatedAt,
+		DeliveredAt: delivery.DeliveredAt,
+	}
+	if delivery.Payload == "" {
+		response.Payload = json.RawMessage("null")
+	}
+	return response
+}
+
+// TransformWebhookDeliveries converts a slice of store.WebhookDelivery to WebhookDeliveriesResponse
+func TransformWebhookDeliveries(deliveries []store.WebhookDelivery) WebhookDeliveriesResponse {
+	responses := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = TransformWebhookDelivery(delivery)
+	}
+	return WebhookDeliveriesResponse{
+		Deliveries: responses,
+		TotalCount: len(responses),
+	}
+}
+
+// TransformNewsletterRun converts a newsletter.Result to NewsletterRunResponse
+func TransformNewsletterRun(result *newsletter.Result, dryRun bool) NewsletterRunResponse {
+	response := NewsletterRunResponse{
+		Message:           "Newsletter run completed",
+		RunID:             result.RunID,
+		ChannelsProcessed: result.ChannelsProcessed,
+		ChannelsWithError: result.ChannelsWithError,
+		NewVideosFound:    result.NewVideosFound,
+		EmailSent:         result.EmailsSent > 0,
+		EmailsSent:        result.EmailsSent,
+		EmailsFailed:      result.EmailsFailed,
+		DryRun:            dryRun,
+	}
+	if dryRun {
+		response.Message = "Newsletter dry run completed, no emails were sent"
+		response.Emails = transformNewsletterEmails(result.Emails)
+	}
+	return response
+}
+
+// TransformNewsletterPreview converts a dry-run newsletter.Result to NewsletterPreviewResponse,
+// leading with the email for recipient when there is one
+func TransformNewsletterPreview(result *newsletter.Result, recipient string) NewsletterPreviewResponse {
+	response := NewsletterPreviewResponse{
+		ChannelsProcessed: result.ChannelsProcessed,
+		ChannelsWithError: result.ChannelsWithError,
+		NewVideosFound:    result.NewVideosFound,
+		Emails:            transformNewsletterEmails(result.Emails),
+	}
+	if len(response.Emails) == 0 {
+		return response
+	}
+	selected := response.Emails[0]
+	for _, rendered := range response.Emails {
+		if strings.EqualFold(rendered.Recipient, recipient) {
+			selected = rendered
+			break
+		}
+	}
+	response.Recipient = selected.Recipient
+	response.Subject = selected.Subject
+	response.Videos = selected.Videos
+	response.HTML = selected.HTML
+	response.Text = selected.Text
+	return response
+}
+
+// transformNewsletterEmails converts rendered newsletter emails to NewsletterEmailResponses
+func transformNewsletterEmails(emails []newsletter.Email) []NewsletterEmailResponse {
+	responses := make([]NewsletterEmailResponse, len(emails))
+	for i, rendered := range emails {
+		responses[i] = NewsletterEmailResponse{
+			Recipient: rendered.Recipient,
+			Subject:   rendered.Subject,
+			Videos:    rendered.Videos,
+			HTML:      rendered.Content.HTML,
+			Text:      rendered.Content.Text,
+		}
+	}
+	return responses
+}
+
+// TransformSMTPConfig converts a store.SMTPConfig to SMTPConfigResponse, leaving out the password
+func TransformSMTPConfig(config *store.SMTPConfig) SMTPConfigResponse {
+	return SMTPConfigResponse{
+		Server:             config.Server,
+		Port:               config.Port,
+		Username:           config.Username,
+		RecipientEmail:     config.RecipientEmail,
+		PasswordSet:        config.Password != "",
+		FromAddress:        config.FromAddress,
+		FromName:           config.FromName,
+		TLSMode:            config.TLSMode,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+}
+
+// TransformSchedulerStatus converts a scheduler.Status to SchedulerStatusResponse
+func TransformSchedulerStatus(status scheduler.Status) SchedulerStatusResponse {
+	return SchedulerStatusResponse{
+		Enabled:         status.Enabled,
+		Schedule:        status.Schedule,
+		Source:          string(status.Source),
+		NextRun:         status.NextRun,
+		LastRun:         status.LastRun,
+		LastRunFinished: status.LastRunFinished,
+		Running:         status.Running,
+		Error:           status.Error,
+	}
+}
+
+// TransformSearchHit converts a search hit to SearchHitResponse, adding catalogue details when the video is known
+func TransformSearchHit(hit search.Hit, video *store.VideoEntry) SearchHitResponse {
+	response := SearchHitResponse{
+		VideoID: hit.VideoID,
+		Title:   hit.Title,
+		Score:   hit.Score,
+		Matches: make([]SearchMatchResponse, len(hit.Matches)),
+	}
+	if video != nil {
+		published := video.Entry.Published
+		response.ChannelID = video.ChannelID
+		response.Published = &published
+		response.ThumbnailURL = video.Entry.MediaGroup.MediaThumbnail.URL
+	}
+
+	for i, match := range hit.Matches {
+		response.Matches[i] = SearchMatchResponse{
+			Field:   string(match.Field),
+			Snippet: match.Snippet,
+		}
+		if match.StartMs >= 0 {
+			seconds := match.StartMs / 1000
+			response.Matches[i].TimestampSeconds = &seconds
+		}
+	}
+	return response
+}
@@ -23,6 +23,13 @@ type Config struct {
 	EnableAPI      bool   // Whether to enable the API server
 	CronSchedule   string // e.g. '0 0 * * *' for daily at midnight
 	RSSConcurrency int    // Number of concurrent RSS fetches, default 5
+	SummaryWorkers int    // Number of background summary workers, default 2
+
+	VideoRetentionDays       int // Remove catalogued videos published longer ago than this, 0 keeps all
+	VideoRetentionPerChannel int // Keep at most this many catalogued videos per channel, 0 keeps all
+
+	NewsletterSummaryConcurrency int           // Summaries generated at once for the newsletter, default 2
+	NewsletterSummaryBudget      time.Duration // Total time allowed for newsletter summaries, default 5m
 
 	DebugMockRSS     bool
 	DebugSkipCron    bool
@@ -109,6 +116,56 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	summaryWorkers := 2 // default to 2 concurrent summary jobs
+	summaryWorkersStr := os.Getenv("SUMMARY_WORKERS")
+	if summaryWorkersStr != "" {
+		if parsed, err := parseIntEnv("SUMMARY_WORKERS", summaryWorkersStr); err == nil && parsed > 0 {
+			summaryWorkers = parsed
+		} else {
+			fmt.Printf("Warning: Invalid SUMMARY_WORKERS value '%s'. Using default value: %d\n", summaryWorkersStr, summaryWorkers)
+		}
+	}
+
+	videoRetentionDays := 90 // default to keeping three months of videos
+	videoRetentionDaysStr := os.Getenv("VIDEO_RETENTION_DAYS")
+	if videoRetentionDaysStr != "" {
+		if parsed, err := parseIntEnv("VIDEO_RETENTION_DAYS", videoRetentionDaysStr); err == nil && parsed >= 0 {
+			videoRetentionDays = parsed
+		} else {
+			fmt.Printf("Warning: Invalid VIDEO_RETENTION_DAYS value '%s'. Using default value: %d\n", videoRetentionDaysStr, videoRetentionDays)
+		}
+	}
+
+	videoRetentionPerChannel := 0 // default to no per-channel limit
+	videoRetentionPerChannelStr := os.Getenv("VIDEO_RETENTION_PER_CHANNEL")
+	if videoRetentionPerChannelStr != "" {
+		if parsed, err := parseIntEnv("VIDEO_RETENTION_PER_CHANNEL", videoRetentionPerChannelStr); err == nil && parsed >= 0 {
+			videoRetentionPerChannel = parsed
+		} else {
+			fmt.Printf("Warning: Invalid VIDEO_RETENTION_PER_CHANNEL value '%s'. Using default value: %d\n", videoRetentionPerChannelStr, videoRetentionPerChannel)
+		}
+	}
+
+	newsletterSummaryConcurrency := 2 // default to 2 concurrent newsletter summaries
+	newsletterSummaryConcurrencyStr := os.Getenv("NEWSLETTER_SUMMARY_CONCURRENCY")
+	if newsletterSummaryConcurrencyStr != "" {
+		if parsed, err := parseIntEnv("NEWSLETTER_SUMMARY_CONCURRENCY", newsletterSummaryConcurrencyStr); err == nil && parsed > 0 {
+			newsletterSummaryConcurrency = parsed
+		} else {
+			fmt.Printf("Warning: Invalid NEWSLETTER_SUMMARY_CONCURRENCY value '%s'. Using default value: %d\n", newsletterSummaryConcurrencyStr, newsletterSummaryConcurrency)
+		}
+	}
+
+	newsletterSummaryBudget := 5 * time.Minute // default to 5 minutes for all newsletter summaries
+	newsletterSummaryBudgetStr := os.Getenv("NEWSLETTER_SUMMARY_BUDGET")
+	if newsletterSummaryBudgetStr != "" {
+		if parsed, err := time.ParseDuration(newsletterSummaryBudgetStr); err == nil && parsed > 0 {
+			newsletterSummaryBudget = parsed
+		} else {
+			fmt.Printf("Warning: Invalid NEWSLETTER_SUMMARY_BUDGET value '%s'. Using default value: %s\n", newsletterSummaryBudgetStr, newsletterSummaryBudget)
+		}
+	}
+
 	return &Config{
 		DBPath:         dbPath,
 		SMTPServer:     smtpServer,
@@ -121,6 +178,13 @@ func LoadConfig() (*Config, error) {
 		EnableAPI:      enableAPI,
 		CronSchedule:   cronSchedule,
 		RSSConcurrency: rssConcurrency,
+		SummaryWorkers: summaryWorkers,
+
+		VideoRetentionDays:       videoRetentionDays,
+		VideoRetentionPerChannel: videoRetentionPerChannel,
+
+		NewsletterSummaryConcurrency: newsletterSummaryConcurrency,
+		NewsletterSummaryBudget:      newsletterSummaryBudget,
 
 		DebugMockRSS:     debugMockRSS,
 		DebugSkipCron:    debugSkipCron,
@@ -2,14 +2,25 @@ package email
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"html/template"
-	"net/smtp"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
 	"strings"
+	texttemplate "text/template"
+	"time"
 
 	"embed"
 	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
 )
 
 //go:embed templates/*.tmpl
@@ -19,14 +30,34 @@ var templateFS embed.FS
 type EmailSender struct {
 	SMTPServer   string
 	SMTPPort     string
-	SMTPUsername string
+	SMTPUsername string // Leave empty to send through an unauthenticated relay
 	SMTPPassword string
+
+	FromAddress        string  // Envelope sender and From address; defaults to SMTPUsername
+	FromName           string  // Optional display name for the From header
+	TLSMode            TLSMode // Defaults to implicit TLS on port 465 and STARTTLS when offered elsewhere
+	InsecureSkipVerify bool    // Accept any server certificate, e.g. for self-hosted relays
+}
+
+// Content is the body of an email, rendered both as HTML and as plain text
+type Content struct {
+	HTML string
+	Text string // Optional; without it the email is sent as HTML only
+}
+
+// ConfigError reports an email that can't be sent as configured, such as one without a sender
+// address or to a server lacking a required extension. Retrying won't help until the settings change.
+type ConfigError struct {
+	Err error
 }
 
+func (e *ConfigError) Error() string { return e.Err.Error() }
+func (e *ConfigError) Unwrap() error { return e.Err }
+
 // Sender defines the interface for sending emails
 // This allows for mocking in tests and easier dependency injection
 type Sender interface {
-	Send(recipient string, subject string, htmlContent string) error
+	Send(recipient string, subject string, content Content) error
 }
 
 // NewEmailSender creates a new EmailSender instance
@@ -39,108 +70,308 @@ func NewEmailSender(server, port, username, password string) *EmailSender {
 	}
 }
 
-// SendEmail sends an email with the given subject, body, and recipient
-// Send sends an HTML email to the specified recipient
-func (c *EmailSender) Send(recipient string, subject string, htmlContent string) error {
+// NewEmailSenderFromConfig creates an EmailSender from stored SMTP settings
+func NewEmailSenderFromConfig(config *store.SMTPConfig) *EmailSender {
+	return &EmailSender{
+		SMTPServer:         config.Server,
+		SMTPPort:           config.Port,
+		SMTPUsername:       config.Username,
+		SMTPPassword:       config.Password,
+		FromAddress:        config.FromAddress,
+		FromName:           config.FromName,
+		TLSMode:            TLSMode(config.TLSMode),
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+}
+
+// fromAddress returns the address emails are sent from
+func (c *EmailSender) fromAddress() string {
+	if c.FromAddress != "" {
+		return c.FromAddress
+	}
+	return c.SMTPUsername
+}
+
+// Send sends an email to the specified recipient, as multipart/alternative when it has a plain-text body
+func (c *EmailSender) Send(recipient string, subject string, content Content) error {
 	if recipient == "" {
-		return errors.New("recipient email cannot be empty")
+		return &ConfigError{Err: errors.New("recipient email cannot be empty")}
 	}
 
 	// Validate recipient contains @
 	if !strings.Contains(recipient, "@") {
-		return errors.New("invalid recipient email format")
+		return &ConfigError{Err: errors.New("invalid recipient email format")}
 	}
 
-	// Set up authentication
-	auth := smtp.PlainAuth("", c.SMTPUsername, c.SMTPPassword, c.SMTPServer)
-
-	// Construct MIME headers
-	headers := make(map[string]string)
-	headers["From"] = c.SMTPUsername
-	headers["To"] = recipient
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/html; charset=\"UTF-8\""
+	from := c.fromAddress()
+	if from == "" {
+		return &ConfigError{Err: errors.New("sender address is not configured")}
+	}
+	fromHeader := (&mail.Address{Name: c.FromName, Address: from}).String()
 
-	// Build message from headers
-	var message strings.Builder
-	for k, v := range headers {
-		message.WriteString(fmt.Sprintf("%s: %s\r\n", k, v))
+	message, err := buildMessage(fromHeader, recipient, subject, content, time.Now())
+	if err != nil {
+		return err
 	}
-	message.WriteString("\r\n" + htmlContent)
 
-	// Send email
-	err := smtp.SendMail(
-		fmt.Sprintf("%s:%s", c.SMTPServer, c.SMTPPort),
-		auth,
-		c.SMTPUsername,
-		[]string{recipient},
-		[]byte(message.String()),
-	)
+	return c.deliver(from, recipient, message)
+}
 
-	return err
+// FormatTestEmail formats the email sent to check SMTP settings
+func FormatTestEmail(sentAt time.Time) Content {
+	timestamp := sentAt.Format("Jan 02, 2006 15:04 MST")
+	return Content{
+		HTML: "<p>This is a test email from YouTube Curator.</p><p>Your SMTP settings work. Sent " + timestamp + ".</p>",
+		Text: "This is a test email from YouTube Curator.\n\nYour SMTP settings work. Sent " + timestamp + ".\n",
+	}
 }
 
-// FormatNewVideosEmail formats an email for new video notifications
-func FormatNewVideosEmail(videos []rss.Entry) (string, error) {
-	tmplContent, err := templateFS.ReadFile("templates/videos_email_template.tmpl")
+// buildMessage assembles an RFC 5322 message. Non-ASCII header values are encoded as UTF-8
+// encoded-words and bodies are quoted-printable, so long lines and non-ASCII text survive transport.
+func buildMessage(from, to, subject string, content Content, now time.Time) ([]byte, error) {
+	messageID, err := newMessageID(from, now)
 	if err != nil {
-		return "", fmt.Errorf("failed to read template: %w", err)
-	}
-
-	// Create and parse the template
-	funcMap := template.FuncMap{
-		"cleanHTML": func(s string) string {
-			// This is a very basic way to remove tags; consider a library for production.
-			// For MVP, this should be okay.
-			return rss.CleanContent(s, 300, false) // Using existing CleanContent
-		},
-		"truncateLines5": func(s string) string {
-			if s == "" {
-				return s
-			}
-			lines := strings.Split(s, "\n")
-			if len(lines) <= 5 {
-				return s
-			}
-			truncated := strings.Join(lines[:5], "\n")
-			return truncated + "..."
-		},
-		"formatDuration": func(seconds int) string {
-			if seconds <= 0 {
-				return ""
-			}
-			minutes := seconds / 60
-			remainingSeconds := seconds % 60
-			if minutes >= 60 {
-				hours := minutes / 60
-				minutes = minutes % 60
-				return fmt.Sprintf("%d:%02d:%02d", hours, minutes, remainingSeconds)
-			}
-			return fmt.Sprintf("%d:%02d", minutes, remainingSeconds)
-		},
-		"joinTags": func(tags []string) string {
-			if len(tags) == 0 {
-				return ""
-			}
-			// Limit to first 5 tags for email
-			displayTags := tags
-			if len(tags) > 5 {
-				displayTags = tags[:5]
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	writeHeader := func(name, value string) {
+		message.WriteString(name + ": " + value + "\r\n")
+	}
+	writeHeader("From", from)
+	writeHeader("To", to)
+	writeHeader("Subject", mime.QEncoding.Encode("UTF-8", subject))
+	writeHeader("Date", now.Format(time.RFC1123Z))
+	writeHeader("Message-ID", messageID)
+	writeHeader("MIME-Version", "1.0")
+
+	if content.Text == "" {
+		writeHeader("Content-Type", `text/html; charset="UTF-8"`)
+		writeHeader("Content-Transfer-Encoding", "quoted-printable")
+		message.WriteString("\r\n")
+		if err := writeQuotedPrintable(&message, content.HTML); err != nil {
+			return nil, err
+		}
+		return message.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&message)
+	writeHeader("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()}))
+	message.WriteString("\r\n")
+
+	// Parts go from least to most preferred, so clients that can render HTML show it
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{`text/plain; charset="UTF-8"`, content.Text},
+		{`text/html; charset="UTF-8"`, content.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create message part: %w", err)
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close multipart message: %w", err)
+	}
+
+	return message.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return fmt.Errorf("failed to encode message body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("failed to encode message body: %w", err)
+	}
+	return nil
+}
+
+// newMessageID generates a unique Message-ID in the sender's domain
+func newMessageID(from string, now time.Time) (string, error) {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate message ID: %w", err)
+	}
+	return fmt.Sprintf("<%d.%s@%s>", now.UnixNano(), hex.EncodeToString(b), domain), nil
+}
+
+// ChannelDigest is the section of a newsletter listing the new videos of one channel
+type ChannelDigest struct {
+	ChannelID    string
+	ChannelTitle string
+	Videos       []rss.Entry // Newest first
+	Omitted      int         // New videos left out by the per-channel cap
+}
+
+// NewChannelDigest builds a channel's digest section from its new videos, newest first.
+// At most maxVideos are kept; 0 keeps them all.
+func NewChannelDigest(channelID, channelTitle string, videos []rss.Entry, maxVideos int) ChannelDigest {
+	sorted := make([]rss.Entry, len(videos))
+	copy(sorted, videos)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Published.After(sorted[j].Published)
+	})
+
+	if channelTitle == "" && len(sorted) > 0 {
+		channelTitle = sorted[0].Author.Name
+	}
+
+	digest := ChannelDigest{ChannelID: channelID, ChannelTitle: channelTitle, Videos: sorted}
+	if maxVideos > 0 && len(sorted) > maxVideos {
+		digest.Videos = sorted[:maxVideos]
+		digest.Omitted = len(sorted) - maxVideos
+	}
+	return digest
+}
+
+// SelectDigests builds a personalised copy of digests holding only the videos wants accepts,
+// keeping at most maxVideos per channel (0 keeps all). Channels left without videos are dropped.
+func SelectDigests(digests []ChannelDigest, wants func(channelID string, video rss.Entry) bool, maxVideos int) []ChannelDigest {
+	var selected []ChannelDigest
+	for _, digest := range digests {
+		var videos []rss.Entry
+		for _, video := range digest.Videos {
+			if wants == nil || wants(digest.ChannelID, video) {
+				videos = append(videos, video)
 			}
-			return strings.Join(displayTags, ", ")
-		},
+		}
+		if len(videos) > 0 {
+			selected = append(selected, NewChannelDigest(digest.ChannelID, digest.ChannelTitle, videos, maxVideos))
+		}
+	}
+	return selected
+}
+
+// DigestEntries returns pointers to the videos listed across digests, so they can be
+// annotated (e.g. with summaries) before formatting
+func DigestEntries(digests []ChannelDigest) []*rss.Entry {
+	var entries []*rss.Entry
+	for i := range digests {
+		for j := range digests[i].Videos {
+			entries = append(entries, &digests[i].Videos[j])
+		}
 	}
+	return entries
+}
+
+// CountDigestVideos returns the number of videos listed across digests
+func CountDigestVideos(digests []ChannelDigest) int {
+	count := 0
+	for _, digest := range digests {
+		count += len(digest.Videos)
+	}
+	return count
+}
+
+// FormatNewVideosEmail formats an email for new video notifications as a single, ungrouped list
+func FormatNewVideosEmail(videos []rss.Entry) (Content, error) {
+	return FormatDigestEmail([]ChannelDigest{{Videos: videos}})
+}
+
+// templateFuncs are shared by the HTML and plain-text email templates
+var templateFuncs = map[string]any{
+	"cleanHTML": func(s string) string {
+		// This is a very basic way to remove tags; consider a library for production.
+		// For MVP, this should be okay.
+		return rss.CleanContent(s, 300, false) // Using existing CleanContent
+	},
+	"truncateLines5": func(s string) string {
+		if s == "" {
+			return s
+		}
+		lines := strings.Split(s, "\n")
+		if len(lines) <= 5 {
+			return s
+		}
+		truncated := strings.Join(lines[:5], "\n")
+		return truncated + "..."
+	},
+	"formatDuration": func(seconds int) string {
+		if seconds <= 0 {
+			return ""
+		}
+		minutes := seconds / 60
+		remainingSeconds := seconds % 60
+		if minutes >= 60 {
+			hours := minutes / 60
+			minutes = minutes % 60
+			return fmt.Sprintf("%d:%02d:%02d", hours, minutes, remainingSeconds)
+		}
+		return fmt.Sprintf("%d:%02d", minutes, remainingSeconds)
+	},
+	"add": func(a, b int) int {
+		return a + b
+	},
+	"joinTags": func(tags []string) string {
+		if len(tags) == 0 {
+			return ""
+		}
+		// Limit to first 5 tags for email
+		displayTags := tags
+		if len(tags) > 5 {
+			displayTags = tags[:5]
+		}
+		return strings.Join(displayTags, ", ")
+	},
+	"indent": func(spaces int, s string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n"+pad)
+	},
+}
+
+// FormatDigestEmail formats a newsletter with one section per channel, listing the channels
+// with the most recent upload first. Channels without videos are left out.
+func FormatDigestEmail(digests []ChannelDigest) (Content, error) {
+	var sections []ChannelDigest
+	for _, digest := range digests {
+		if len(digest.Videos) > 0 {
+			sections = append(sections, digest)
+		}
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].Videos[0].Published.After(sections[j].Videos[0].Published)
+	})
 
-	t, err := template.New("newVideosEmail").Funcs(funcMap).Parse(string(tmplContent))
+	htmlContent, err := templateFS.ReadFile("templates/videos_email_template.tmpl")
+	if err != nil {
+		return Content{}, fmt.Errorf("failed to read template: %w", err)
+	}
+	htmlTmpl, err := template.New("newVideosEmail").Funcs(template.FuncMap(templateFuncs)).Parse(string(htmlContent))
 	if err != nil {
-		return "", fmt.Errorf("failed to parse email template: %w", err)
+		return Content{}, fmt.Errorf("failed to parse email template: %w", err)
 	}
 
-	var body bytes.Buffer
-	if err := t.Execute(&body, videos); err != nil {
-		return "", fmt.Errorf("failed to execute email template: %w", err)
+	textContent, err := templateFS.ReadFile("templates/videos_email_text.tmpl")
+	if err != nil {
+		return Content{}, fmt.Errorf("failed to read text template: %w", err)
+	}
+	textTmpl, err := texttemplate.New("newVideosEmailText").Funcs(texttemplate.FuncMap(templateFuncs)).Parse(string(textContent))
+	if err != nil {
+		return Content{}, fmt.Errorf("failed to parse text email template: %w", err)
+	}
+
+	var htmlBody, textBody bytes.Buffer
+	if err := htmlTmpl.Execute(&htmlBody, sections); err != nil {
+		return Content{}, fmt.Errorf("failed to execute email template: %w", err)
+	}
+	if err := textTmpl.Execute(&textBody, sections); err != nil {
+		return Content{}, fmt.Errorf("failed to execute text email template: %w", err)
 	}
 
-	return body.String(), nil
+	return Content{HTML: htmlBody.String(), Text: textBody.String()}, nil
 }
@@ -1,6 +1,12 @@
 package email
 
 import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
 	"strings"
 	"testing"
 	"time"
@@ -93,20 +99,253 @@ func TestFormatNewVideosEmail(t *testing.T) {
 	}
 
 	// Check that the result contains expected elements
-	if !strings.Contains(result, "Test Video") {
+	if !strings.Contains(result.HTML, "Test Video") {
 		t.Error("Email should contain video title")
 	}
-	if !strings.Contains(result, "Test Channel") {
+	if !strings.Contains(result.HTML, "Test Channel") {
 		t.Error("Email should contain channel name")
 	}
-	if !strings.Contains(result, "Line 1") {
+	if !strings.Contains(result.HTML, "Line 1") {
 		t.Error("Email should contain description")
 	}
 	// Check that truncation happened (should not contain Line 6)
-	if strings.Contains(result, "Line 6") {
+	if strings.Contains(result.HTML, "Line 6") {
 		t.Error("Email should truncate description at 5 lines")
 	}
-	if !strings.Contains(result, "Line 5...") {
+	if !strings.Contains(result.HTML, "Line 5...") {
 		t.Error("Email should show truncation indicator")
 	}
+
+	// The plain-text rendering carries the same content without markup
+	for _, want := range []string{"* Test Video", "Channel: Test Channel", "Watch: https://youtube.com/watch?v=test", "Published: Jan 01, 2023 12:00 UTC", "    Line 5..."} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("Plain-text email should contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if strings.Contains(result.Text, "<") || strings.Contains(result.Text, "Line 6") {
+		t.Errorf("Plain-text email should have no markup and a truncated description, got:\n%s", result.Text)
+	}
+}
+
+func TestNewChannelDigest(t *testing.T) {
+	base, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+	videos := []rss.Entry{
+		{ID: "old", Title: "Old", Published: base, Author: rss.Author{Name: "Feed Author"}},
+		{ID: "newest", Title: "Newest", Published: base.Add(2 * time.Hour)},
+		{ID: "middle", Title: "Middle", Published: base.Add(time.Hour)},
+	}
+
+	digest := NewChannelDigest("UC123", "", videos, 2)
+	if digest.ChannelTitle != "" {
+		// The newest video has no author, so there is nothing to fall back to
+		t.Errorf("Expected empty channel title, got %q", digest.ChannelTitle)
+	}
+	if len(digest.Videos) != 2 || digest.Videos[0].ID != "newest" || digest.Videos[1].ID != "middle" {
+		t.Errorf("Expected the two newest videos newest first, got %+v", digest.Videos)
+	}
+	if digest.Omitted != 1 {
+		t.Errorf("Expected 1 omitted video, got %d", digest.Omitted)
+	}
+	if videos[0].ID != "old" {
+		t.Error("NewChannelDigest should not reorder the caller's slice")
+	}
+
+	uncapped := NewChannelDigest("UC123", "My Channel", videos, 0)
+	if len(uncapped.Videos) != 3 || uncapped.Omitted != 0 {
+		t.Errorf("Expected all 3 videos without a cap, got %d (omitted %d)", len(uncapped.Videos), uncapped.Omitted)
+	}
+	if CountDigestVideos([]ChannelDigest{digest, uncapped}) != 5 {
+		t.Errorf("Expected 5 videos across digests, got %d", CountDigestVideos([]ChannelDigest{digest, uncapped}))
+	}
+}
+
+func TestFormatDigestEmail(t *testing.T) {
+	base, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+	digests := []ChannelDigest{
+		NewChannelDigest("UCquiet", "Quiet Channel", []rss.Entry{
+			{ID: "q1", Title: "Quiet Upload", Published: base},
+		}, 0),
+		NewChannelDigest("UCempty", "Empty Channel", nil, 0),
+		NewChannelDigest("UCbusy", "Busy Channel", []rss.Entry{
+			{ID: "b1", Title: "Busy Upload 1", Published: base.Add(3 * time.Hour)},
+			{ID: "b2", Title: "Busy Upload 2", Published: base.Add(2 * time.Hour)},
+			{ID: "b3", Title: "Busy Upload 3", Published: base.Add(1 * time.Hour)},
+		}, 2),
+	}
+
+	result, err := FormatDigestEmail(digests)
+	if err != nil {
+		t.Fatalf("FormatDigestEmail failed: %v", err)
+	}
+
+	for name, body := range map[string]string{"HTML": result.HTML, "Text": result.Text} {
+		for _, want := range []string{"Busy Upload 1", "Busy Upload 2", "Quiet Upload", "3 new video(s)", "+1 more new video(s)", "https://www.youtube.com/channel/UCbusy/videos"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s email should contain %q", name, want)
+			}
+		}
+		if strings.Contains(body, "Busy Upload 3") {
+			t.Errorf("%s email should leave out videos over the per-channel cap", name)
+		}
+		if strings.Contains(body, "Empty Channel") {
+			t.Errorf("%s email should leave out channels without videos", name)
+		}
+		// The channel with the most recent upload comes first
+		if strings.Index(body, "Busy Channel") > strings.Index(body, "Quiet Channel") {
+			t.Errorf("Expected Busy Channel before Quiet Channel in %s email", name)
+		}
+	}
+}
+
+func TestFormatDigestEmail_Summaries(t *testing.T) {
+	base, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+	digests := []ChannelDigest{
+		NewChannelDigest("UC123", "My Channel", []rss.Entry{
+			{ID: "summarised", Title: "Summarised", Published: base.Add(time.Hour), MediaGroup: rss.MediaGroup{MediaDescription: "Summarised description"}},
+			{ID: "plain", Title: "Plain", Published: base, MediaGroup: rss.MediaGroup{MediaDescription: "Plain description"}},
+		}, 0),
+	}
+
+	entries := DigestEntries(digests)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 digest entries, got %d", len(entries))
+	}
+	entries[0].Summary = &rss.Summary{Text: "A <concise> summary"}
+
+	result, err := FormatDigestEmail(digests)
+	if err != nil {
+		t.Fatalf("FormatDigestEmail failed: %v", err)
+	}
+
+	if !strings.Contains(result.HTML, "A &lt;concise&gt; summary") {
+		t.Error("Email should contain the escaped summary")
+	}
+	if !strings.Contains(result.Text, "    A <concise> summary") {
+		t.Error("Plain-text email should contain the summary verbatim")
+	}
+	for name, body := range map[string]string{"HTML": result.HTML, "Text": result.Text} {
+		if strings.Contains(body, "Summarised description") {
+			t.Errorf("%s email should show the summary instead of the description", name)
+		}
+		if !strings.Contains(body, "Plain description") {
+			t.Errorf("%s email should fall back to the description when there is no summary", name)
+		}
+	}
+}
+
+func TestSelectDigests(t *testing.T) {
+	base, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+	digests := []ChannelDigest{
+		NewChannelDigest("UCone", "One", []rss.Entry{
+			{ID: "1a", Published: base.Add(3 * time.Hour)},
+			{ID: "1b", Published: base.Add(2 * time.Hour), Tags: []string{"go"}},
+			{ID: "1c", Published: base.Add(1 * time.Hour), Tags: []string{"go"}},
+		}, 0),
+		NewChannelDigest("UCtwo", "Two", []rss.Entry{
+			{ID: "2a", Published: base},
+		}, 0),
+	}
+
+	taggedGo := func(channelID string, video rss.Entry) bool {
+		return len(video.Tags) > 0 && video.Tags[0] == "go"
+	}
+	selected := SelectDigests(digests, taggedGo, 1)
+	if len(selected) != 1 {
+		t.Fatalf("Expected only channel One to remain, got %d digests", len(selected))
+	}
+	if selected[0].ChannelTitle != "One" || len(selected[0].Videos) != 1 || selected[0].Videos[0].ID != "1b" || selected[0].Omitted != 1 {
+		t.Errorf("Unexpected personalised digest: %+v", selected[0])
+	}
+
+	// The source digests are left untouched
+	if len(digests[0].Videos) != 3 {
+		t.Errorf("SelectDigests should not modify its input, got %d videos", len(digests[0].Videos))
+	}
+
+	if all := SelectDigests(digests, nil, 0); CountDigestVideos(all) != 4 {
+		t.Errorf("Expected every video without a filter, got %d", CountDigestVideos(all))
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+	content := Content{
+		HTML: "<p>Nouvelles vidéos</p>" + strings.Repeat("x", 1200),
+		Text: "Nouvelles vidéos",
+	}
+
+	raw, err := buildMessage("curator@example.com", "me@example.com", "Nouvelles vidéos ✨", content, now)
+	if err != nil {
+		t.Fatalf("buildMessage failed: %v", err)
+	}
+	for _, line := range strings.Split(string(raw), "\r\n") {
+		if len(line) > 998 {
+			t.Fatalf("Message lines must not exceed 998 characters, got %d", len(line))
+		}
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Failed to parse message: %v", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Nouvelles vidéos ✨" {
+		t.Errorf("Expected the UTF-8 subject to round-trip, got %q (%v)", subject, err)
+	}
+	if msg.Header.Get("Subject") == subject {
+		t.Error("Non-ASCII subject should be encoded")
+	}
+	if date, err := msg.Header.Date(); err != nil || !date.Equal(now) {
+		t.Errorf("Expected Date header %v, got %v (%v)", now, date, err)
+	}
+	if id := msg.Header.Get("Message-ID"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@example.com>") {
+		t.Errorf("Expected a Message-ID in the sender's domain, got %q", id)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Expected multipart/alternative, got %q (%v)", mediaType, err)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	var bodies []string
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read part: %v", err)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatalf("Failed to decode part: %v", err)
+		}
+		parts = append(parts, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+
+	if len(parts) != 2 || !strings.HasPrefix(parts[0], "text/plain") || !strings.HasPrefix(parts[1], "text/html") {
+		t.Fatalf("Expected plain-text then HTML parts, got %v", parts)
+	}
+	if bodies[0] != content.Text || bodies[1] != content.HTML {
+		t.Errorf("Part bodies should round-trip, got %q and %q", bodies[0], bodies[1][:40])
+	}
+
+	// Without a plain-text body the message is a single HTML part
+	raw, err = buildMessage("curator@example.com", "me@example.com", "Hello", Content{HTML: "<p>Hi</p>"}, now)
+	if err != nil {
+		t.Fatalf("buildMessage failed: %v", err)
+	}
+	msg, err = mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Failed to parse message: %v", err)
+	}
+	if got := msg.Header.Get("Subject"); got != "Hello" {
+		t.Errorf("ASCII subjects should be left as is, got %q", got)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(msg.Header.Get("Content-Type")); mediaType != "text/html" {
+		t.Errorf("Expected text/html, got %q", mediaType)
+	}
 }
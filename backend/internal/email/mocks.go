@@ -10,14 +10,16 @@ type MockSender struct {
 type SentEmail struct {
 	Recipient string
 	Subject   string
-	Body      string
+	Body      string // HTML body
+	Text      string // Plain-text body
 }
 
-func (m *MockSender) Send(recipient, subject, htmlContent string) error {
+func (m *MockSender) Send(recipient, subject string, content Content) error {
 	m.SentEmails = append(m.SentEmails, SentEmail{
 		Recipient: recipient,
 		Subject:   subject,
-		Body:      htmlContent,
+		Body:      content.HTML,
+		Text:      content.Text,
 	})
 	return nil
 }
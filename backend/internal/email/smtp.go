@@ -0,0 +1,132 @@
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// TLSMode selects how the connection to the SMTP server is secured
+type TLSMode string
+
+const (
+	TLSModeNone     TLSMode = "none"     // Plain connection; STARTTLS is never attempted
+	TLSModeStartTLS TLSMode = "starttls" // Upgrade with STARTTLS, failing if the server doesn't offer it
+	TLSModeImplicit TLSMode = "implicit" // TLS from the first byte, usually on port 465
+)
+
+// smtpTimeout bounds a whole SMTP conversation so a stalled server can't block a newsletter run
+const smtpTimeout = time.Minute
+
+// ParseTLSMode validates a TLS mode name. An empty name selects the default behaviour.
+func ParseTLSMode(mode string) (TLSMode, error) {
+	switch TLSMode(mode) {
+	case "", TLSModeNone, TLSModeStartTLS, TLSModeImplicit:
+		return TLSMode(mode), nil
+	}
+	return "", fmt.Errorf("invalid TLS mode %q: must be one of none, starttls or implicit", mode)
+}
+
+// ValidateAuthTLS rejects settings that would authenticate over a plain connection. net/smtp
+// refuses to send credentials without TLS to anything but localhost, so such a configuration
+// could never deliver a message.
+func ValidateAuthTLS(server string, mode TLSMode, username string) error {
+	if username == "" || mode != TLSModeNone || isLocalhost(server) {
+		return nil
+	}
+	return errors.New("TLS mode none can't be used with a username: credentials are only sent over TLS, except to localhost")
+}
+
+func isLocalhost(server string) bool {
+	return server == "localhost" || server == "127.0.0.1" || server == "::1"
+}
+
+// effectiveTLSMode resolves the default mode to implicit TLS on port 465. Elsewhere the
+// default stays empty, meaning STARTTLS is used when the server offers it.
+func (c *EmailSender) effectiveTLSMode() TLSMode {
+	if c.TLSMode == "" && c.SMTPPort == "465" {
+		return TLSModeImplicit
+	}
+	return c.TLSMode
+}
+
+// deliver sends a prepared message over SMTP according to the sender's TLS and authentication settings
+func (c *EmailSender) deliver(from, recipient string, message []byte) error {
+	mode, err := ParseTLSMode(string(c.effectiveTLSMode()))
+	if err != nil {
+		return &ConfigError{Err: err}
+	}
+
+	addr := net.JoinHostPort(c.SMTPServer, c.SMTPPort)
+	tlsConfig := &tls.Config{
+		ServerName:         c.SMTPServer,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	var conn net.Conn
+	if mode == TLSModeImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to set SMTP deadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, c.SMTPServer)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if mode != TLSModeNone && mode != TLSModeImplicit {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("failed to start TLS: %w", err)
+			}
+		} else if mode == TLSModeStartTLS {
+			return &ConfigError{Err: errors.New("SMTP server does not support STARTTLS")}
+		}
+	}
+
+	if c.SMTPUsername != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return &ConfigError{Err: errors.New("SMTP server does not support authentication")}
+		}
+		if err := client.Auth(smtp.PlainAuth("", c.SMTPUsername, c.SMTPPassword, c.SMTPServer)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("SMTP server rejected sender %s: %w", from, err)
+	}
+	if err := client.Rcpt(recipient); err != nil {
+		return fmt.Errorf("SMTP server rejected recipient %s: %w", recipient, err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start message data: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected message: %w", err)
+	}
+	// The message is accepted at this point, so failing here would only make the outbox send it again
+	if err := client.Quit(); err != nil {
+		log.Printf("SMTP server %s accepted the message but QUIT failed: %v", addr, err)
+	}
+	return nil
+}
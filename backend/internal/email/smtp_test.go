@@ -0,0 +1,319 @@
+package email
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer is a minimal SMTP server that records a single message
+type fakeSMTPServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config // Offered through STARTTLS when set
+	auth      bool        // Advertise AUTH PLAIN
+	failQuit  bool        // Answer QUIT with an error after the message was accepted
+
+	mu       sync.Mutex
+	from     string
+	rcpt     []string
+	data     string
+	authLine string
+	tls      bool
+}
+
+func newFakeSMTPServer(t *testing.T, implicitTLS bool, startTLS bool, auth bool) *fakeSMTPServer {
+	t.Helper()
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{selfSignedCertificate(t)}}
+
+	var listener net.Listener
+	var err error
+	if implicitTLS {
+		listener, err = tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	} else {
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTPServer{listener: listener, auth: auth, tls: implicitTLS}
+	if startTLS {
+		server.tlsConfig = tlsConfig
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTPServer) sender(t *testing.T) *EmailSender {
+	host, port, err := net.SplitHostPort(s.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to split listener address: %v", err)
+	}
+	return &EmailSender{SMTPServer: host, SMTPPort: port}
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 fake ESMTP")
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO", "HELO":
+			s.mu.Lock()
+			secure := s.tls
+			s.mu.Unlock()
+			lines := []string{"fake"}
+			if s.tlsConfig != nil && !secure {
+				lines = append(lines, "STARTTLS")
+			}
+			if s.auth {
+				lines = append(lines, "AUTH PLAIN")
+			}
+			for i, l := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				tp.PrintfLine("250%s%s", sep, l)
+			}
+		case "STARTTLS":
+			tp.PrintfLine("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			tp = textproto.NewConn(conn)
+			s.mu.Lock()
+			s.tls = true
+			s.mu.Unlock()
+		case "AUTH":
+			s.mu.Lock()
+			s.authLine = line
+			s.mu.Unlock()
+			tp.PrintfLine("235 Authenticated")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = line
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcpt = append(s.rcpt, line)
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 Go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = string(data)
+			s.mu.Unlock()
+			tp.PrintfLine("250 Queued")
+		case "QUIT":
+			if s.failQuit {
+				tp.PrintfLine("421 Connection lost")
+				return
+			}
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+// selfSignedCertificate creates a throwaway certificate for 127.0.0.1
+func selfSignedCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fake smtp"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestEmailSender_UnauthenticatedRelay(t *testing.T) {
+	server := newFakeSMTPServer(t, false, false, false)
+	sender := server.sender(t)
+	sender.TLSMode = TLSModeNone
+	sender.FromAddress = "curator@example.com"
+	sender.FromName = "YouTube Curator"
+
+	if err := sender.Send("me@example.com", "Hello", Content{HTML: "<p>Hi</p>", Text: "Hi"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.authLine != "" {
+		t.Errorf("Expected no authentication without a username, got %q", server.authLine)
+	}
+	if !strings.HasPrefix(server.from, "MAIL FROM:<curator@example.com>") {
+		t.Errorf("Expected the configured envelope sender, got %q", server.from)
+	}
+	if len(server.rcpt) != 1 || !strings.Contains(server.rcpt[0], "<me@example.com>") {
+		t.Errorf("Unexpected recipients %v", server.rcpt)
+	}
+	headers, err := textproto.NewReader(bufio.NewReader(strings.NewReader(server.data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("Failed to parse message headers: %v", err)
+	}
+	if got := headers.Get("From"); got != `"YouTube Curator" <curator@example.com>` {
+		t.Errorf("Expected From header with display name, got %q", got)
+	}
+}
+
+func TestEmailSender_StartTLS(t *testing.T) {
+	server := newFakeSMTPServer(t, false, true, true)
+	sender := server.sender(t)
+	sender.TLSMode = TLSModeStartTLS
+	sender.InsecureSkipVerify = true
+	sender.SMTPUsername = "user@example.com"
+	sender.SMTPPassword = "secret"
+
+	if err := sender.Send("me@example.com", "Hello", Content{HTML: "<p>Hi</p>"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if !server.tls {
+		t.Error("Expected the connection to be upgraded with STARTTLS")
+	}
+	if !strings.HasPrefix(server.authLine, "AUTH PLAIN") {
+		t.Errorf("Expected PLAIN authentication, got %q", server.authLine)
+	}
+	if !strings.Contains(server.from, "<user@example.com>") {
+		t.Errorf("Expected the username as the default sender, got %q", server.from)
+	}
+}
+
+func TestEmailSender_StartTLSRequired(t *testing.T) {
+	server := newFakeSMTPServer(t, false, false, false)
+	sender := server.sender(t)
+	sender.TLSMode = TLSModeStartTLS
+	sender.FromAddress = "curator@example.com"
+
+	err := sender.Send("me@example.com", "Hello", Content{HTML: "<p>Hi</p>"})
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("Expected a STARTTLS error, got %v", err)
+	}
+}
+
+func TestEmailSender_ImplicitTLS(t *testing.T) {
+	server := newFakeSMTPServer(t, true, false, false)
+	sender := server.sender(t)
+	sender.TLSMode = TLSModeImplicit
+	sender.FromAddress = "curator@example.com"
+
+	if err := sender.Send("me@example.com", "Hello", Content{HTML: "<p>Hi</p>"}); err == nil {
+		t.Fatal("Expected the self-signed certificate to be rejected")
+	}
+
+	sender.InsecureSkipVerify = true
+	if err := sender.Send("me@example.com", "Hello", Content{HTML: "<p>Hi</p>"}); err != nil {
+		t.Fatalf("Send with skip-verify failed: %v", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.data == "" {
+		t.Error("Expected the message to be delivered")
+	}
+}
+
+func TestEmailSender_QuitFailureAfterDelivery(t *testing.T) {
+	server := newFakeSMTPServer(t, false, false, false)
+	server.failQuit = true
+	sender := server.sender(t)
+	sender.TLSMode = TLSModeNone
+	sender.FromAddress = "curator@example.com"
+
+	// Reporting the error would make the outbox send the accepted message a second time
+	if err := sender.Send("me@example.com", "Hello", Content{HTML: "<p>Hi</p>"}); err != nil {
+		t.Fatalf("Expected a QUIT failure after delivery to be ignored, got %v", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.data == "" {
+		t.Error("Expected the message to be delivered")
+	}
+}
+
+func TestValidateAuthTLS(t *testing.T) {
+	if err := ValidateAuthTLS("smtp.example.com", TLSModeNone, "user@example.com"); err == nil {
+		t.Error("Expected credentials over a plain connection to be rejected")
+	}
+	for _, server := range []string{"localhost", "127.0.0.1", "::1"} {
+		if err := ValidateAuthTLS(server, TLSModeNone, "user@example.com"); err != nil {
+			t.Errorf("Expected plain authentication to %s to be allowed, got %v", server, err)
+		}
+	}
+	if err := ValidateAuthTLS("relay.lan", TLSModeNone, ""); err != nil {
+		t.Errorf("Expected an unauthenticated relay to be allowed, got %v", err)
+	}
+	if err := ValidateAuthTLS("smtp.example.com", TLSModeStartTLS, "user@example.com"); err != nil {
+		t.Errorf("Expected authentication over STARTTLS to be allowed, got %v", err)
+	}
+}
+
+func TestParseTLSMode(t *testing.T) {
+	for _, mode := range []string{"", "none", "starttls", "implicit"} {
+		if _, err := ParseTLSMode(mode); err != nil {
+			t.Errorf("ParseTLSMode(%q) returned %v", mode, err)
+		}
+	}
+	if _, err := ParseTLSMode("ssl"); err == nil {
+		t.Error("Expected an error for an unknown TLS mode")
+	}
+
+	sender := &EmailSender{SMTPPort: "465"}
+	if sender.effectiveTLSMode() != TLSModeImplicit {
+		t.Error("Port 465 should default to implicit TLS")
+	}
+	sender.SMTPPort = "587"
+	if sender.effectiveTLSMode() != "" {
+		t.Error("Other ports should default to opportunistic STARTTLS")
+	}
+}
@@ -0,0 +1,260 @@
+// Package feeds renders catalogued videos as Atom and JSON Feed 1.1 documents, so the curated
+// stream can be followed from any feed reader.
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"youtube-curator-v2/internal/store"
+)
+
+// Content types the feeds are served with
+const (
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	JSONContentType = "application/feed+json; charset=utf-8"
+)
+
+const (
+	generator      = "YouTube Curator"
+	jsonFeedSchema = "https://jsonfeed.org/version/1.1"
+	mediaNamespace = "http://search.yahoo.com/mrss/"
+)
+
+// Feed describes a feed of catalogued videos
+type Feed struct {
+	Title       string
+	Description string
+	FeedURL     string             // Where the feed itself is served; also the Atom feed ID
+	HomeURL     string             // Web page the feed corresponds to
+	Videos      []store.VideoEntry // In feed order, normally newest first
+	Summaries   bool               // Use stored summaries as item content
+}
+
+// item is the feed-independent view of a video
+type item struct {
+	id          string
+	url         string
+	title       string
+	author      string
+	authorURL   string
+	thumbnail   string
+	description string
+	summary     string // Stored summary, when the feed includes summaries and the video has one
+	tags        []string
+	published   time.Time
+	updated     time.Time
+}
+
+func (f Feed) items() []item {
+	items := make([]item, 0, len(f.Videos))
+	for _, video := range f.Videos {
+		entry := video.Entry
+		it := item{
+			id:          entry.ID,
+			url:         entry.Link.Href,
+			title:       entry.Title,
+			author:      entry.Author.Name,
+			authorURL:   entry.Author.URI,
+			thumbnail:   entry.MediaGroup.MediaThumbnail.URL,
+			description: strings.TrimSpace(entry.MediaGroup.MediaDescription),
+			tags:        entry.Tags,
+			published:   entry.Published,
+			updated:     entry.Published,
+		}
+		if it.description == "" {
+			it.description = strings.TrimSpace(entry.Content)
+		}
+		if f.Summaries && entry.Summary != nil && entry.Summary.Text != "" {
+			it.summary = entry.Summary.Text
+			if entry.Summary.SummaryGeneratedAt.After(it.updated) {
+				it.updated = entry.Summary.SummaryGeneratedAt
+			}
+		}
+		items = append(items, it)
+	}
+	return items
+}
+
+// updated is when the feed last changed: the latest item update, or now for an empty feed
+func updated(items []item) time.Time {
+	var latest time.Time
+	for _, it := range items {
+		if it.updated.After(latest) {
+			latest = it.updated
+		}
+	}
+	if latest.IsZero() {
+		latest = time.Now()
+	}
+	return latest
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Media     string      `xml:"xmlns:media,attr"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Generator string      `xml:"generator"`
+	Links     []atomLink  `xml:"link"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID         string          `xml:"id"`
+	Title      string          `xml:"title"`
+	Link       atomLink        `xml:"link"`
+	Author     *atomAuthor     `xml:"author,omitempty"`
+	Published  string          `xml:"published"`
+	Updated    string          `xml:"updated"`
+	Summary    string          `xml:"summary,omitempty"` // Video description
+	Content    *atomContent    `xml:"content,omitempty"` // Stored summary
+	Categories []atomCategory  `xml:"category"`
+	Thumbnail  *mediaThumbnail `xml:"media:thumbnail,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type mediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+// Atom renders the feed as an Atom 1.0 document
+func (f Feed) Atom() ([]byte, error) {
+	items := f.items()
+	doc := atomFeed{
+		Media:     mediaNamespace,
+		ID:        f.FeedURL,
+		Title:     f.Title,
+		Subtitle:  f.Description,
+		Updated:   updated(items).UTC().Format(time.RFC3339),
+		Generator: generator,
+		Links:     []atomLink{{Rel: "self", Type: "application/atom+xml", Href: f.FeedURL}},
+	}
+	if f.HomeURL != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "alternate", Type: "text/html", Href: f.HomeURL})
+	}
+
+	for _, it := range items {
+		entry := atomEntry{
+			ID:        it.id,
+			Title:     it.title,
+			Link:      atomLink{Rel: "alternate", Type: "text/html", Href: it.url},
+			Published: it.published.UTC().Format(time.RFC3339),
+			Updated:   it.updated.UTC().Format(time.RFC3339),
+			Summary:   it.description,
+		}
+		if it.author != "" {
+			entry.Author = &atomAuthor{Name: it.author, URI: it.authorURL}
+		}
+		if it.summary != "" {
+			entry.Content = &atomContent{Type: "text", Body: it.summary}
+		}
+		for _, tag := range it.tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		if it.thumbnail != "" {
+			entry.Thumbnail = &mediaThumbnail{URL: it.thumbnail}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Atom feed: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+type jsonFeed struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url,omitempty"`
+	FeedURL     string     `json:"feed_url,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url,omitempty"`
+	Title         string       `json:"title"`
+	ContentText   string       `json:"content_text"`
+	Summary       string       `json:"summary,omitempty"`
+	Image         string       `json:"image,omitempty"`
+	DatePublished time.Time    `json:"date_published"`
+	DateModified  time.Time    `json:"date_modified"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// JSON renders the feed as a JSON Feed 1.1 document. Items carry the stored summary as their
+// content when the feed includes summaries, and the video description otherwise.
+func (f Feed) JSON() ([]byte, error) {
+	doc := jsonFeed{
+		Version:     jsonFeedSchema,
+		Title:       f.Title,
+		HomePageURL: f.HomeURL,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Items:       []jsonItem{},
+	}
+
+	for _, it := range f.items() {
+		entry := jsonItem{
+			ID:            it.id,
+			URL:           it.url,
+			Title:         it.title,
+			ContentText:   it.description,
+			Image:         it.thumbnail,
+			DatePublished: it.published.UTC(),
+			DateModified:  it.updated.UTC(),
+			Tags:          it.tags,
+		}
+		if it.summary != "" {
+			entry.ContentText = it.summary
+			entry.Summary = it.description
+		}
+		if entry.ContentText == "" {
+			entry.ContentText = it.title // Items must have content
+		}
+		if it.author != "" {
+			entry.Authors = []jsonAuthor{{Name: it.author, URL: it.authorURL}}
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON feed: %w", err)
+	}
+	return body, nil
+}
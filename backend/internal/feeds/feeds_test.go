@@ -0,0 +1,164 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+)
+
+var (
+	published = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	generated = published.Add(2 * time.Hour)
+)
+
+func testFeed(summaries bool) Feed {
+	summarised := rss.Entry{
+		ID:        "yt:video:video-1",
+		Title:     "Summarised <Video>",
+		Link:      rss.Link{Href: "https://www.youtube.com/watch?v=video-1"},
+		Published: published,
+		Author:    rss.Author{Name: "Channel 1", URI: "https://www.youtube.com/channel/channel-1"},
+		Tags:      []string{"go"},
+		Summary:   &rss.Summary{Text: "A short summary", SummaryGeneratedAt: generated},
+	}
+	summarised.MediaGroup.MediaDescription = "The description"
+	summarised.MediaGroup.MediaThumbnail.URL = "https://i.ytimg.com/vi/video-1/hqdefault.jpg"
+
+	return Feed{
+		Title:     "Curated",
+		FeedURL:   "http://localhost:8080/feeds/all.atom",
+		HomeURL:   "http://localhost:8080/",
+		Summaries: summaries,
+		Videos: []store.VideoEntry{
+			{ChannelID: "channel-1", Entry: summarised},
+			{ChannelID: "channel-2", Entry: rss.Entry{ID: "yt:video:video-2", Title: "Bare Video", Published: published.Add(-time.Hour)}},
+		},
+	}
+}
+
+func TestAtom(t *testing.T) {
+	body, err := testFeed(true).Atom()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(string(body), xml.Header) || !strings.Contains(string(body), `xmlns="http://www.w3.org/2005/Atom"`) {
+		t.Errorf("Expected an Atom document, got %s", body)
+	}
+	if !strings.Contains(string(body), `<media:thumbnail url="https://i.ytimg.com/vi/video-1/hqdefault.jpg">`) {
+		t.Errorf("Expected a media thumbnail, got %s", body)
+	}
+
+	var doc struct {
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Links   []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Title   string `xml:"title"`
+			Updated string `xml:"updated"`
+			Summary string `xml:"summary"`
+			Content string `xml:"content"`
+			Author  struct {
+				Name string `xml:"name"`
+			} `xml:"author"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Failed to parse the feed: %v", err)
+	}
+	if doc.ID != "http://localhost:8080/feeds/all.atom" || len(doc.Links) != 2 || doc.Links[0].Rel != "self" {
+		t.Errorf("Unexpected feed metadata: %+v", doc)
+	}
+	if doc.Updated != generated.Format(time.RFC3339) {
+		t.Errorf("Expected the feed to be updated when the summary was generated, got %s", doc.Updated)
+	}
+	if len(doc.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(doc.Entries))
+	}
+	entry := doc.Entries[0]
+	if entry.ID != "yt:video:video-1" || entry.Title != "Summarised <Video>" || entry.Summary != "The description" ||
+		entry.Content != "A short summary" || entry.Author.Name != "Channel 1" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if doc.Entries[1].Content != "" || doc.Entries[1].Summary != "" {
+		t.Errorf("Expected no content for a video without description or summary, got %+v", doc.Entries[1])
+	}
+}
+
+func TestAtom_WithoutSummaries(t *testing.T) {
+	body, err := testFeed(false).Atom()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(string(body), "A short summary") || strings.Contains(string(body), "<content") {
+		t.Errorf("Expected summaries to be left out, got %s", body)
+	}
+	if strings.Contains(string(body), generated.Format(time.RFC3339)) {
+		t.Errorf("Expected the summary time to be ignored, got %s", body)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var doc struct {
+		Version string `json:"version"`
+		FeedURL string `json:"feed_url"`
+		Items   []struct {
+			ID           string    `json:"id"`
+			URL          string    `json:"url"`
+			ContentText  string    `json:"content_text"`
+			Summary      string    `json:"summary"`
+			Image        string    `json:"image"`
+			DateModified time.Time `json:"date_modified"`
+			Authors      []struct {
+				Name string `json:"name"`
+			} `json:"authors"`
+		} `json:"items"`
+	}
+
+	body, err := testFeed(true).JSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Failed to parse the feed: %v", err)
+	}
+	if doc.Version != "https://jsonfeed.org/version/1.1" || len(doc.Items) != 2 {
+		t.Fatalf("Unexpected feed: %s", body)
+	}
+	item := doc.Items[0]
+	if item.ContentText != "A short summary" || item.Summary != "The description" || !item.DateModified.Equal(generated) ||
+		item.Image == "" || len(item.Authors) != 1 || item.Authors[0].Name != "Channel 1" {
+		t.Errorf("Unexpected item: %+v", item)
+	}
+	if doc.Items[1].ContentText != "Bare Video" {
+		t.Errorf("Expected the title to stand in for missing content, got %+v", doc.Items[1])
+	}
+
+	body, err = testFeed(false).JSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	doc.Items = nil
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Failed to parse the feed: %v", err)
+	}
+	if doc.Items[0].ContentText != "The description" || doc.Items[0].Summary != "" {
+		t.Errorf("Expected the description as content without summaries, got %+v", doc.Items[0])
+	}
+
+	body, err = Feed{Title: "Empty"}.JSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(body), `"items": []`) {
+		t.Errorf("Expected an empty item list, got %s", body)
+	}
+}
@@ -0,0 +1,240 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/summary"
+)
+
+// DefaultQueueCapacity is the maximum number of jobs waiting for a worker
+const DefaultQueueCapacity = 256
+
+// jobTimeout bounds a single job; LLM calls with retries can take a while
+const jobTimeout = 30 * time.Minute
+
+// ErrQueueFull is returned when no more jobs can be accepted
+var ErrQueueFull = errors.New("job queue is full")
+
+// Queue runs background jobs on a bounded worker pool and persists their state in the store
+type Queue struct {
+	store          store.Store
+	summaryService summary.SummaryServiceInterface
+	workers        int
+	pending        chan string // IDs of jobs waiting for a worker
+	startOnce      sync.Once
+	running        sync.WaitGroup // Workers and the resume backlog, for Wait
+}
+
+// NewQueue creates a new job queue with the given number of workers
+func NewQueue(store store.Store, summaryService summary.SummaryServiceInterface, workers int) *Queue {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Queue{
+		store:          store,
+		summaryService: summaryService,
+		workers:        workers,
+		pending:        make(chan string, DefaultQueueCapacity),
+	}
+}
+
+// Start resumes jobs left unfinished by a previous run and starts the workers.
+// Workers stop when ctx is cancelled; calling Start more than once has no effect.
+func (q *Queue) Start(ctx context.Context) error {
+	var err error
+	q.startOnce.Do(func() {
+		err = q.resume(ctx)
+		for i := 0; i < q.workers; i++ {
+			q.running.Add(1)
+			go func(workerID int) {
+				defer q.running.Done()
+				q.worker(ctx, workerID)
+			}(i)
+		}
+	})
+	return err
+}
+
+// Wait blocks until the workers have stopped after the context passed to Start is cancelled.
+// Jobs interrupted by the shutdown are left running in the store and resume on the next Start.
+func (q *Queue) Wait() {
+	q.running.Wait()
+}
+
+// resume re-queues jobs that were queued or running when the process last stopped.
+// Jobs that don't fit in the queue are handed to the workers as room frees up.
+func (q *Queue) resume(ctx context.Context) error {
+	unfinished, err := q.store.GetJobsByStatus(store.JobStatusQueued, store.JobStatusRunning)
+	if err != nil {
+		return fmt.Errorf("failed to load unfinished jobs: %w", err)
+	}
+
+	var backlog []string
+	for i := range unfinished {
+		job := &unfinished[i]
+		if job.Status == store.JobStatusRunning {
+			job.Status = store.JobStatusQueued
+			job.StartedAt = nil
+			if err := q.store.SaveJob(job); err != nil {
+				return fmt.Errorf("failed to reset job %s: %w", job.ID, err)
+			}
+		}
+
+		select {
+		case q.pending <- job.ID:
+			log.Printf("Resumed job %s (%s %s)", job.ID, job.Type, job.VideoID)
+		default:
+			backlog = append(backlog, job.ID)
+		}
+	}
+
+	if len(backlog) > 0 {
+		log.Printf("Job queue full, %d resumed jobs will be queued as workers free up", len(backlog))
+		q.running.Add(1)
+		go func() {
+			defer q.running.Done()
+			for _, jobID := range backlog {
+				select {
+				case q.pending <- jobID:
+				case <-ctx.Done():
+					return // Still queued in the store, so they resume on the next start
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// EnqueueSummary persists a new summary job and schedules it for a worker
+func (q *Queue) EnqueueSummary(videoID string, regenerate bool) (*store.Job, error) {
+	jobID, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	job := &store.Job{
+		ID:         jobID,
+		Type:       store.JobTypeSummary,
+		VideoID:    videoID,
+		Regenerate: regenerate,
+		Status:     store.JobStatusQueued,
+		CreatedAt:  time.Now(),
+	}
+	if err := q.store.SaveJob(job); err != nil {
+		return nil, fmt.Errorf("failed to save job: %w", err)
+	}
+
+	select {
+	case q.pending <- job.ID:
+		return job, nil
+	default:
+		q.finish(job, ErrQueueFull)
+		return nil, ErrQueueFull
+	}
+}
+
+// Get returns the current state of a job, or nil if it doesn't exist
+func (q *Queue) Get(jobID string) (*store.Job, error) {
+	return q.store.GetJob(jobID)
+}
+
+// worker processes jobs until ctx is cancelled
+func (q *Queue) worker(ctx context.Context, workerID int) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case jobID := <-q.pending:
+			q.run(ctx, workerID, jobID)
+		}
+	}
+}
+
+// run executes a single job and records its outcome
+func (q *Queue) run(ctx context.Context, workerID int, jobID string) {
+	job, err := q.store.GetJob(jobID)
+	if err != nil {
+		log.Printf("Worker %d failed to load job %s: %v", workerID, jobID, err)
+		return
+	}
+	if job == nil || job.Status != store.JobStatusQueued {
+		return
+	}
+
+	startedAt := time.Now()
+	job.Status = store.JobStatusRunning
+	job.StartedAt = &startedAt
+	if err := q.store.SaveJob(job); err != nil {
+		log.Printf("Worker %d failed to mark job %s as running: %v", workerID, jobID, err)
+		return
+	}
+
+	log.Printf("Worker %d running job %s (%s %s)", workerID, job.ID, job.Type, job.VideoID)
+
+	jobCtx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	switch job.Type {
+	case store.JobTypeSummary:
+		err = q.runSummary(jobCtx, job)
+	default:
+		err = fmt.Errorf("unknown job type %q", job.Type)
+	}
+
+	// Leave the job running in the store if we were interrupted by shutdown so it resumes on restart
+	if ctx.Err() != nil {
+		log.Printf("Worker %d interrupted while running job %s", workerID, job.ID)
+		return
+	}
+
+	q.finish(job, err)
+}
+
+// runSummary generates (or regenerates) the summary for the job's video
+func (q *Queue) runSummary(ctx context.Context, job *store.Job) error {
+	if q.summaryService == nil {
+		return errors.New("summary service not available")
+	}
+
+	var result *summary.SummaryResult
+	if job.Regenerate {
+		result = q.summaryService.RegenerateSummary(ctx, job.VideoID)
+	} else {
+		result = q.summaryService.GetOrGenerateSummary(ctx, job.VideoID)
+	}
+	return result.Error
+}
+
+// finish records the final state of a job
+func (q *Queue) finish(job *store.Job, err error) {
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err != nil {
+		job.Status = store.JobStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = store.JobStatusSucceeded
+		job.Error = ""
+	}
+
+	if saveErr := q.store.SaveJob(job); saveErr != nil {
+		log.Printf("Failed to save final state of job %s: %v", job.ID, saveErr)
+	}
+}
+
+// newJobID generates a random hex job identifier
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,230 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/summary"
+)
+
+// fakeSummaryService records calls and returns a configurable error
+type fakeSummaryService struct {
+	mu          sync.Mutex
+	generated   []string
+	regenerated []string
+	err         error
+}
+
+func (f *fakeSummaryService) GetOrGenerateSummary(ctx context.Context, videoID string) *summary.SummaryResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.generated = append(f.generated, videoID)
+	return &summary.SummaryResult{VideoID: videoID, Summary: "summary", Error: f.err}
+}
+
+func (f *fakeSummaryService) RegenerateSummary(ctx context.Context, videoID string) *summary.SummaryResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.regenerated = append(f.regenerated, videoID)
+	return &summary.SummaryResult{VideoID: videoID, Summary: "summary", Error: f.err}
+}
+
+func (f *fakeSummaryService) StreamSummary(ctx context.Context, videoID string, regenerate bool, events chan<- summary.StreamEvent) {
+	close(events)
+}
+
+func newTestStore(t *testing.T) store.Store {
+	t.Helper()
+	db, err := store.NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// waitForStatus polls the store until the job reaches a final state
+func waitForStatus(t *testing.T, db store.Store, jobID string) *store.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := db.GetJob(jobID)
+		if err != nil {
+			t.Fatalf("Failed to get job: %v", err)
+		}
+		if job != nil && (job.Status == store.JobStatusSucceeded || job.Status == store.JobStatusFailed) {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for job %s to finish", jobID)
+	return nil
+}
+
+func TestQueue_EnqueueSummary_Succeeds(t *testing.T) {
+	db := newTestStore(t)
+	service := &fakeSummaryService{}
+	queue := NewQueue(db, service, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := queue.Start(ctx); err != nil {
+		t.Fatalf("Failed to start queue: %v", err)
+	}
+
+	job, err := queue.EnqueueSummary("yt:video:dQw4w9WgXcQ", false)
+	if err != nil {
+		t.Fatalf("Failed to enqueue job: %v", err)
+	}
+	if job.Status != store.JobStatusQueued {
+		t.Errorf("Expected new job to be queued, got %s", job.Status)
+	}
+
+	finished := waitForStatus(t, db, job.ID)
+	if finished.Status != store.JobStatusSucceeded {
+		t.Fatalf("Expected job to succeed, got %s (%s)", finished.Status, finished.Error)
+	}
+	if finished.StartedAt == nil || finished.FinishedAt == nil {
+		t.Errorf("Expected start and finish times to be recorded")
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if len(service.generated) != 1 || len(service.regenerated) != 0 {
+		t.Errorf("Expected one GetOrGenerateSummary call, got %d generate and %d regenerate", len(service.generated), len(service.regenerated))
+	}
+}
+
+func TestQueue_EnqueueSummary_RecordsFailure(t *testing.T) {
+	db := newTestStore(t)
+	service := &fakeSummaryService{err: errors.New("no subtitles available for video")}
+	queue := NewQueue(db, service, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := queue.Start(ctx); err != nil {
+		t.Fatalf("Failed to start queue: %v", err)
+	}
+
+	job, err := queue.EnqueueSummary("yt:video:dQw4w9WgXcQ", true)
+	if err != nil {
+		t.Fatalf("Failed to enqueue job: %v", err)
+	}
+
+	finished := waitForStatus(t, db, job.ID)
+	if finished.Status != store.JobStatusFailed {
+		t.Fatalf("Expected job to fail, got %s", finished.Status)
+	}
+	if finished.Error != "no subtitles available for video" {
+		t.Errorf("Unexpected job error: %q", finished.Error)
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if len(service.regenerated) != 1 {
+		t.Errorf("Expected RegenerateSummary to be called once, got %d", len(service.regenerated))
+	}
+}
+
+func TestQueue_Start_ResumesUnfinishedJobs(t *testing.T) {
+	db := newTestStore(t)
+
+	// Simulate jobs left behind by a previous process
+	startedAt := time.Now().Add(-time.Minute)
+	leftovers := []*store.Job{
+		{ID: "queued-job", Type: store.JobTypeSummary, VideoID: "yt:video:aaaaaaaaaaa", Status: store.JobStatusQueued, CreatedAt: time.Now().Add(-2 * time.Minute)},
+		{ID: "running-job", Type: store.JobTypeSummary, VideoID: "yt:video:bbbbbbbbbbb", Status: store.JobStatusRunning, CreatedAt: time.Now().Add(-time.Minute), StartedAt: &startedAt},
+	}
+	for _, job := range leftovers {
+		if err := db.SaveJob(job); err != nil {
+			t.Fatalf("Failed to save job: %v", err)
+		}
+	}
+
+	service := &fakeSummaryService{}
+	queue := NewQueue(db, service, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := queue.Start(ctx); err != nil {
+		t.Fatalf("Failed to start queue: %v", err)
+	}
+
+	for _, job := range leftovers {
+		finished := waitForStatus(t, db, job.ID)
+		if finished.Status != store.JobStatusSucceeded {
+			t.Errorf("Expected resumed job %s to succeed, got %s", job.ID, finished.Status)
+		}
+	}
+}
+
+func TestQueue_Start_ResumesJobsBeyondCapacity(t *testing.T) {
+	db := newTestStore(t)
+
+	var leftovers []string
+	for _, id := range []string{"job-1", "job-2", "job-3", "job-4"} {
+		job := &store.Job{ID: id, Type: store.JobTypeSummary, VideoID: "yt:video:" + id, Status: store.JobStatusQueued, CreatedAt: time.Now()}
+		if err := db.SaveJob(job); err != nil {
+			t.Fatalf("Failed to save job: %v", err)
+		}
+		leftovers = append(leftovers, id)
+	}
+
+	queue := NewQueue(db, &fakeSummaryService{}, 1)
+	queue.pending = make(chan string, 1) // Fewer slots than leftover jobs
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := queue.Start(ctx); err != nil {
+		t.Fatalf("Failed to start queue: %v", err)
+	}
+
+	for _, id := range leftovers {
+		if finished := waitForStatus(t, db, id); finished.Status != store.JobStatusSucceeded {
+			t.Errorf("Expected resumed job %s to succeed, got %s", id, finished.Status)
+		}
+	}
+}
+
+// blockingSummaryService blocks until the context is cancelled
+type blockingSummaryService struct {
+	fakeSummaryService
+	started chan struct{}
+}
+
+func (b *blockingSummaryService) GetOrGenerateSummary(ctx context.Context, videoID string) *summary.SummaryResult {
+	close(b.started)
+	<-ctx.Done()
+	return &summary.SummaryResult{VideoID: videoID, Error: ctx.Err()}
+}
+
+func TestQueue_Shutdown_LeavesJobRunning(t *testing.T) {
+	db := newTestStore(t)
+	service := &blockingSummaryService{started: make(chan struct{})}
+	queue := NewQueue(db, service, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := queue.Start(ctx); err != nil {
+		t.Fatalf("Failed to start queue: %v", err)
+	}
+	job, err := queue.EnqueueSummary("yt:video:dQw4w9WgXcQ", false)
+	if err != nil {
+		t.Fatalf("Failed to enqueue job: %v", err)
+	}
+
+	<-service.started
+	cancel()
+	queue.Wait()
+
+	interrupted, err := db.GetJob(job.ID)
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if interrupted.Status != store.JobStatusRunning {
+		t.Errorf("Expected the interrupted job to stay running so it resumes, got %s", interrupted.Status)
+	}
+}
@@ -0,0 +1,87 @@
+package newsletter
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"youtube-curator-v2/internal/processor"
+	"youtube-curator-v2/internal/store"
+)
+
+// maxConcurrency caps the feeds fetched at once, to avoid triggering YouTube's rate limiting
+const maxConcurrency = 10
+
+// processChannelsConcurrently processes multiple channels concurrently using a worker pool
+// This improves RSS load times by fetching multiple feeds in parallel while respecting rate limits
+func processChannelsConcurrently(ctx context.Context, channels []store.Channel, process func(ctx context.Context, channelID string) processor.ChannelResult, concurrency int) map[string]processor.ChannelResult {
+	if len(channels) == 0 {
+		return make(map[string]processor.ChannelResult)
+	}
+
+	// Limit concurrency to number of channels if there are fewer channels than workers
+	if concurrency > len(channels) {
+		concurrency = len(channels)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// Safety check: Limit maximum concurrency to prevent overwhelming YouTube's servers
+	// which could trigger rate limiting
+	if concurrency > maxConcurrency {
+		log.Printf("Warning: RSS_CONCURRENCY=%d exceeds recommended maximum of %d, limiting to %d",
+			concurrency, maxConcurrency, maxConcurrency)
+		concurrency = maxConcurrency
+	}
+
+	log.Printf("Processing %d channels with %d concurrent workers", len(channels), concurrency)
+
+	// Create channels for job distribution and result collection
+	jobs := make(chan string, len(channels))
+	results := make(map[string]processor.ChannelResult)
+	var resultsMutex sync.Mutex
+
+	// Start worker goroutines
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			log.Printf("Worker %d started", workerID)
+
+			for channelID := range jobs {
+				// Process the channel
+				result := process(ctx, channelID)
+
+				// Store result safely
+				resultsMutex.Lock()
+				results[channelID] = result
+				resultsMutex.Unlock()
+
+				log.Printf("Worker %d processed channel %s", workerID, channelID)
+			}
+
+			log.Printf("Worker %d finished", workerID)
+		}(i)
+	}
+
+	// Send jobs to workers
+	go func() {
+		defer close(jobs)
+		for _, channel := range channels {
+			select {
+			case jobs <- channel.ID:
+			case <-ctx.Done():
+				log.Printf("Context cancelled while sending jobs")
+				return
+			}
+		}
+	}()
+
+	// Wait for all workers to complete
+	wg.Wait()
+
+	log.Printf("Completed processing %d channels", len(channels))
+	return results
+}
@@ -0,0 +1,184 @@
+package newsletter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/http/retry"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/webhooks"
+)
+
+// DefaultDeliveryRetryConfig bounds the attempts to deliver an outbox message before it is dead-lettered
+var DefaultDeliveryRetryConfig = retry.RetryConfig{
+	MaxRetries:      4,
+	InitialBackoff:  5 * time.Second,
+	MaxBackoff:      2 * time.Minute,
+	BackoffFactor:   2.0,
+	MaxTotalTimeout: 10 * time.Minute,
+}
+
+var (
+	// ErrMessageNotFound is returned when resending an unknown outbox message
+	ErrMessageNotFound = errors.New("outbox message not found")
+	// ErrNotDeadLetter is returned when resending a message that hasn't been dead-lettered
+	ErrNotDeadLetter = errors.New("only dead-lettered messages can be resent")
+)
+
+// DeliverPending delivers the messages left pending in the outbox, such as those enqueued
+// just before the process stopped
+func (r *Runner) DeliverPending(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.deliverPending(ctx)
+}
+
+// Resend delivers a dead-lettered outbox message again and returns its updated state. Like Run,
+// it returns ErrRunInProgress rather than wait for a run or delivery already in progress.
+func (r *Runner) Resend(ctx context.Context, messageID string) (*store.OutboxMessage, error) {
+	if !r.mu.TryLock() {
+		return nil, ErrRunInProgress
+	}
+	defer r.mu.Unlock()
+
+	message, err := r.store.GetOutboxMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, ErrMessageNotFound
+	}
+	if message.Status != store.OutboxStatusDead {
+		return nil, ErrNotDeadLetter
+	}
+
+	// The timestamps were committed when the message was dead-lettered
+	if err := r.deliver(ctx, message); err != nil {
+		log.Printf("Error resending outbox message %s to %s: %v", message.ID, message.Recipient, err)
+	}
+	return message, nil
+}
+
+func (r *Runner) deliverPending(ctx context.Context) error {
+	pending, err := r.store.GetOutboxMessages(store.OutboxStatusPending)
+	if err != nil {
+		return err
+	}
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return err // The rest stay pending for the next delivery
+		}
+		message := &pending[i]
+		log.Printf("Delivering pending outbox message %s to %s", message.ID, message.Recipient)
+		if err := r.deliver(ctx, message); err != nil {
+			if ctx.Err() != nil {
+				return err // Still pending, so its channels' timestamps stay where they are
+			}
+			log.Printf("Error sending email to %s: %v", message.Recipient, err)
+		}
+		r.commitTimestamps(message.Channels)
+	}
+	return nil
+}
+
+// deliver sends an outbox message through its notification channel, retrying transient failures
+// with backoff. The message ends up sent, or dead-lettered with the last error; either way its new
+// state is saved. If ctx ends first the delivery was interrupted rather than failed, so the message
+// keeps its status and a later delivery picks it up.
+func (r *Runner) deliver(ctx context.Context, message *store.OutboxMessage) error {
+	var target store.NotifyTarget
+	if message.Target != nil {
+		target = *message.Target
+	}
+	notification := notify.Message{
+		Recipient: message.Recipient,
+		Subject:   message.Subject,
+		Content:   email.Content{HTML: message.HTML, Text: message.Text},
+		Items:     message.Items,
+	}
+	_, err := retry.RetryWithBackoff(ctx, r.retryConfig, func(ctx context.Context) (struct{}, error) {
+		message.Attempts++
+		return struct{}{}, r.notifier.Notify(ctx, target, notification)
+	}, notify.IsTransient)
+
+	now := time.Now()
+	message.UpdatedAt = now
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// Interrupted; only the attempts are recorded
+	case err != nil:
+		message.Status = store.OutboxStatusDead
+		message.Error = err.Error()
+	default:
+		message.Status = store.OutboxStatusSent
+		message.Error = ""
+		message.SentAt = &now
+	}
+	if saveErr := r.store.SaveOutboxMessage(message); saveErr != nil {
+		log.Printf("Warning: Failed to save outbox message %s: %v", message.ID, saveErr)
+	}
+	if err == nil && r.publisher != nil {
+		r.publisher.Publish(webhooks.EventNewsletterSent, newsletterSentData(message, target))
+	}
+	return err
+}
+
+// newsletterSentData describes a sent outbox message for newsletter.sent events
+func newsletterSentData(message *store.OutboxMessage, target store.NotifyTarget) webhooks.NewsletterData {
+	channel := target.Type
+	if channel == "" {
+		channel = notify.TypeEmail
+	}
+	videos := message.Items
+	if videos == nil {
+		videos = []store.DigestItem{}
+	}
+	return webhooks.NewsletterData{
+		RunID:     message.RunID,
+		MessageID: message.ID,
+		Recipient: message.Recipient,
+		Subject:   message.Subject,
+		Channel:   channel,
+		Videos:    videos,
+		SentAt:    *message.SentAt,
+	}
+}
+
+// commitTimestamps advances the channels' last checked timestamps, skipping channels whose videos
+// are still waiting in a pending outbox message; that message commits them once it is resolved
+func (r *Runner) commitTimestamps(timestamps map[string]time.Time) {
+	if len(timestamps) == 0 {
+		return
+	}
+	pending, err := r.store.GetOutboxMessages(store.OutboxStatusPending)
+	if err != nil {
+		log.Printf("Warning: Failed to get pending outbox messages, leaving last checked timestamps alone: %v", err)
+		return
+	}
+	held := make(map[string]bool)
+	for _, message := range pending {
+		for channelID := range message.Channels {
+			held[channelID] = true
+		}
+	}
+
+	for channelID, timestamp := range timestamps {
+		if held[channelID] {
+			continue
+		}
+		lastChecked, err := r.store.GetLastCheckedTimestamp(channelID)
+		if err != nil {
+			log.Printf("Error getting last checked timestamp for channel ID %s: %v", channelID, err)
+		}
+		if !timestamp.After(lastChecked) {
+			continue
+		}
+		if err := r.store.SetLastCheckedTimestamp(channelID, timestamp); err != nil {
+			log.Printf("Error setting last checked timestamp for channel ID %s: %v", channelID, err)
+		}
+	}
+}
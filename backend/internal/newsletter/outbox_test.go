@@ -0,0 +1,442 @@
+package newsletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/config"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/http/retry"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/processor"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/webhooks"
+
+	"go.uber.org/mock/gomock"
+)
+
+// testRetryConfig retries twice without waiting
+var testRetryConfig = retry.RetryConfig{MaxRetries: 2}
+
+// newOutboxRunner returns a runner for one channel with a new video, sending to recipient@example.com
+func newOutboxRunner(t *testing.T, sender *MockEmailSender) (*Runner, *store.Run, *memoryOutbox, time.Time) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockStore := store.NewMockStore(ctrl)
+	run := expectRunHistory(mockStore)
+	outbox := expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "channel-1", Title: "Channel 1"}}, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(&store.SMTPConfig{RecipientEmail: "recipient@example.com"}, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(nil, nil)
+
+	published := time.Now().Add(-time.Hour).Truncate(time.Second)
+	mockProcessor := NewMockChannelProcessor()
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideos: []rss.Entry{{ID: "video-1", Title: "Queued Video", Published: published}},
+	}
+
+	runner := NewRunner(mockStore, mockProcessor, notify.NewDispatcher(sender, nil), nil, &config.Config{RSSConcurrency: 1})
+	runner.retryConfig = testRetryConfig
+	return runner, run, outbox, published
+}
+
+func TestRun_RetriesTransientSendErrors(t *testing.T) {
+	sender := NewMockEmailSender()
+	sender.failures = []error{errors.New("connection reset by peer")}
+	runner, run, outbox, published := newOutboxRunner(t, sender)
+
+	result := runScheduled(t, runner)
+
+	if result.EmailsSent != 1 || sender.attempts != 2 {
+		t.Fatalf("Expected the email to be sent on the second attempt, got %+v after %d attempts", result, sender.attempts)
+	}
+	if len(run.Emails) != 1 || run.Emails[0].Status != store.EmailStatusSent || run.Emails[0].OutboxID == "" {
+		t.Fatalf("Expected one sent email from the outbox in the run, got %+v", run.Emails)
+	}
+	message := outbox.message(run.Emails[0].OutboxID)
+	if message.Status != store.OutboxStatusSent || message.Attempts != 2 || message.SentAt == nil || message.Error != "" {
+		t.Errorf("Expected the outbox message to be sent, got %+v", message)
+	}
+	if !outbox.timestamp("channel-1").Equal(published) {
+		t.Errorf("Expected the last checked timestamp to advance to %v, got %v", published, outbox.timestamp("channel-1"))
+	}
+}
+
+func TestRun_DeadLettersFailedEmails(t *testing.T) {
+	sender := NewMockEmailSender()
+	sender.failures = []error{errors.New("timeout"), errors.New("timeout"), errors.New("timeout")}
+	runner, run, outbox, published := newOutboxRunner(t, sender)
+
+	result, err := runner.Run(context.Background(), Options{Trigger: store.RunTriggerScheduled})
+	if err == nil {
+		t.Fatal("Expected the run to fail when the only email is dead-lettered")
+	}
+	if result.EmailsFailed != 1 || sender.attempts != 3 {
+		t.Fatalf("Expected the email to fail after 3 attempts, got %+v after %d attempts", result, sender.attempts)
+	}
+	message := outbox.message(run.Emails[0].OutboxID)
+	if message.Status != store.OutboxStatusDead || message.Attempts != 3 || message.Error == "" {
+		t.Errorf("Expected the outbox message to be dead-lettered, got %+v", message)
+	}
+	if run.Emails[0].Status != store.EmailStatusFailed {
+		t.Errorf("Expected the email to be recorded as failed, got %+v", run.Emails[0])
+	}
+	// Dead-lettered videos are not sent again by the next run
+	if !outbox.timestamp("channel-1").Equal(published) {
+		t.Errorf("Expected the last checked timestamp to advance to %v, got %v", published, outbox.timestamp("channel-1"))
+	}
+}
+
+func TestRun_PermanentSendErrorsAreNotRetried(t *testing.T) {
+	sender := NewMockEmailSender()
+	sender.failures = []error{fmt.Errorf("failed to authenticate: %w", &textproto.Error{Code: 535, Msg: "authentication failed"})}
+	runner, run, outbox, _ := newOutboxRunner(t, sender)
+
+	if _, err := runner.Run(context.Background(), Options{Trigger: store.RunTriggerScheduled}); err == nil {
+		t.Fatal("Expected the run to fail")
+	}
+	if sender.attempts != 1 {
+		t.Errorf("Expected a single attempt, got %d", sender.attempts)
+	}
+	if message := outbox.message(run.Emails[0].OutboxID); message.Status != store.OutboxStatusDead {
+		t.Errorf("Expected the outbox message to be dead-lettered, got %+v", message)
+	}
+}
+
+func TestRun_MisconfiguredSenderIsNotRetried(t *testing.T) {
+	runner, run, outbox, _ := newOutboxRunner(t, NewMockEmailSender())
+	// Without a username or from address there is no sender address to send from
+	runner.notifier = notify.NewDispatcher(email.NewEmailSender("127.0.0.1", "25", "", ""), nil)
+
+	if _, err := runner.Run(context.Background(), Options{Trigger: store.RunTriggerScheduled}); err == nil {
+		t.Fatal("Expected the run to fail")
+	}
+	if message := outbox.message(run.Emails[0].OutboxID); message.Status != store.OutboxStatusDead || message.Attempts != 1 {
+		t.Errorf("Expected the outbox message to be dead-lettered after one attempt, got %+v", message)
+	}
+}
+
+func TestRun_InterruptedDeliveryStaysPending(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sender := NewMockEmailSender()
+	sender.onSend = cancel // The run is cancelled while the email is being sent
+	sender.failures = []error{errors.New("connection reset by peer")}
+	runner, run, outbox, _ := newOutboxRunner(t, sender)
+
+	if _, err := runner.Run(ctx, Options{Trigger: store.RunTriggerScheduled}); err == nil {
+		t.Fatal("Expected the interrupted run to fail")
+	}
+	if len(run.Emails) != 1 || run.Emails[0].Status != store.EmailStatusPending {
+		t.Fatalf("Expected the email to be recorded as pending, got %+v", run.Emails)
+	}
+	if message := outbox.message(run.Emails[0].OutboxID); message.Status != store.OutboxStatusPending || message.Error != "" {
+		t.Errorf("Expected the outbox message to stay pending, got %+v", message)
+	}
+	if !outbox.timestamp("channel-1").IsZero() {
+		t.Errorf("Expected the last checked timestamp to stay put, got %v", outbox.timestamp("channel-1"))
+	}
+}
+
+// recordingPublisher records the webhook events it is given
+type recordingPublisher struct {
+	events []string
+	data   []any
+}
+
+func (p *recordingPublisher) Publish(event string, data any) {
+	p.events = append(p.events, event)
+	p.data = append(p.data, data)
+}
+
+func TestRun_FailedEnqueueHoldsChannels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	run := expectRunHistory(mockStore)
+	// The outbox rejects the digest for two@example.com
+	mockStore.EXPECT().SaveOutboxMessage(gomock.Cond(func(message *store.OutboxMessage) bool {
+		return message.Recipient == "two@example.com"
+	})).Return(errors.New("disk full"))
+	outbox := expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "channel-1", Title: "Channel 1"}, {ID: "channel-2", Title: "Channel 2"}}, nil)
+	mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{
+		{ID: "1", Email: "one@example.com", ChannelIDs: []string{"channel-1"}, Enabled: true},
+		{ID: "2", Email: "two@example.com", ChannelIDs: []string{"channel-1", "channel-2"}, Enabled: true},
+	}, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(nil, nil)
+
+	mockProcessor := NewMockChannelProcessor()
+	for _, channelID := range []string{"channel-1", "channel-2"} {
+		mockProcessor.results[channelID] = processor.ChannelResult{
+			ChannelID: channelID,
+			NewVideos: []rss.Entry{{ID: "video-" + channelID, Title: "Video", Published: time.Now().Add(-time.Hour)}},
+		}
+	}
+
+	sender := NewMockEmailSender()
+	runner := NewRunner(mockStore, mockProcessor, notify.NewDispatcher(sender, nil), nil, &config.Config{RSSConcurrency: 1})
+	runner.retryConfig = testRetryConfig
+	result := runScheduled(t, runner)
+
+	if result.EmailsSent != 1 || result.EmailsFailed != 1 || len(run.Emails) != 2 || run.Emails[1].Status != store.EmailStatusFailed {
+		t.Fatalf("Expected one email sent and one failed, got %+v", run.Emails)
+	}
+	// Neither channel advances, so two@example.com gets the videos with the next run
+	for _, channelID := range []string{"channel-1", "channel-2"} {
+		if timestamp := outbox.timestamp(channelID); !timestamp.IsZero() {
+			t.Errorf("Expected the last checked timestamp of %s to stay put, got %v", channelID, timestamp)
+		}
+	}
+}
+
+func TestRun_PublishesNewsletterSent(t *testing.T) {
+	sender := NewMockEmailSender()
+	runner, run, _, _ := newOutboxRunner(t, sender)
+	publisher := &recordingPublisher{}
+	runner.SetPublisher(publisher)
+
+	runScheduled(t, runner)
+
+	if len(publisher.events) != 1 || publisher.events[0] != webhooks.EventNewsletterSent {
+		t.Fatalf("Expected one newsletter.sent event, got %v", publisher.events)
+	}
+	data, ok := publisher.data[0].(webhooks.NewsletterData)
+	if !ok {
+		t.Fatalf("Expected newsletter data, got %T", publisher.data[0])
+	}
+	if data.RunID != run.ID || data.MessageID != run.Emails[0].OutboxID || data.Recipient != "recipient@example.com" ||
+		data.Channel != notify.TypeEmail || len(data.Videos) != 1 || data.Videos[0].VideoID != "video-1" {
+		t.Errorf("Unexpected newsletter data: %+v", data)
+	}
+}
+
+func TestRun_DeadLetteredEmailsAreNotPublished(t *testing.T) {
+	sender := NewMockEmailSender()
+	sender.failures = []error{fmt.Errorf("failed to authenticate: %w", &textproto.Error{Code: 535, Msg: "authentication failed"})}
+	runner, _, _, _ := newOutboxRunner(t, sender)
+	publisher := &recordingPublisher{}
+	runner.SetPublisher(publisher)
+
+	if _, err := runner.Run(context.Background(), Options{Trigger: store.RunTriggerScheduled}); err == nil {
+		t.Fatal("Expected the run to fail")
+	}
+	if len(publisher.events) != 0 {
+		t.Errorf("Expected no events for a dead-lettered email, got %v", publisher.events)
+	}
+}
+
+func TestRun_DeliversThroughSubscriberChannel(t *testing.T) {
+	var payload notify.WebhookPayload
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(notify.SignatureHeader)
+		if notify.Sign("s3cret", body) != signature || json.Unmarshal(body, &payload) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	run := expectRunHistory(mockStore)
+	outbox := expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "channel-1", Title: "Channel 1"}}, nil)
+	mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{
+		{ID: "1", Email: "hooked@example.com", Enabled: true, Notify: &store.NotifyTarget{Type: notify.TypeWebhook, URL: server.URL, Secret: "s3cret"}},
+		{ID: "2", Email: "mailed@example.com", Enabled: true},
+	}, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(nil, nil)
+
+	mockProcessor := NewMockChannelProcessor()
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideos: []rss.Entry{{ID: "yt:video:video-1", Title: "Hooked Video", Link: rss.Link{Href: "https://www.youtube.com/watch?v=video-1"}, Published: time.Now().Add(-time.Hour)}},
+	}
+
+	sender := NewMockEmailSender()
+	runner := NewRunner(mockStore, mockProcessor, notify.NewDispatcher(sender, server.Client()), nil, &config.Config{RSSConcurrency: 1})
+	runner.retryConfig = testRetryConfig
+	result := runScheduled(t, runner)
+
+	if result.EmailsSent != 2 {
+		t.Fatalf("Expected both digests to be delivered, got %+v", result)
+	}
+	if len(sender.sentEmails) != 1 || sender.sentEmails[0].Recipient != "mailed@example.com" {
+		t.Errorf("Expected only mailed@example.com to be emailed, got %+v", sender.sentEmails)
+	}
+	if payload.Recipient != "hooked@example.com" || len(payload.Videos) != 1 || payload.Videos[0].VideoID != "video-1" {
+		t.Errorf("Expected the webhook to receive the digest videos, got %+v", payload)
+	}
+	for _, runEmail := range run.Emails {
+		if message := outbox.message(runEmail.OutboxID); runEmail.Recipient == "hooked@example.com" && (message.Target == nil || len(message.Items) != 1) {
+			t.Errorf("Expected the queued message to keep its target and videos, got %+v", message)
+		}
+	}
+}
+
+func TestDeliverPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	outbox := expectOutbox(mockStore)
+
+	published := time.Now().Add(-time.Hour).Truncate(time.Second)
+	pending, err := store.NewOutboxMessage("run-1", "left@example.com", Subject, "<p>hi</p>", "hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending.Channels = map[string]time.Time{"channel-1": published}
+	if err := mockStore.SaveOutboxMessage(pending); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := NewMockEmailSender()
+	runner := NewRunner(mockStore, NewMockChannelProcessor(), notify.NewDispatcher(sender, nil), nil, nil)
+	runner.retryConfig = testRetryConfig
+	if err := runner.DeliverPending(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(sender.sentEmails) != 1 || sender.sentEmails[0].Recipient != "left@example.com" {
+		t.Fatalf("Expected the pending message to be sent, got %+v", sender.sentEmails)
+	}
+	if message := outbox.message(pending.ID); message.Status != store.OutboxStatusSent {
+		t.Errorf("Expected the message to be sent, got %+v", message)
+	}
+	if !outbox.timestamp("channel-1").Equal(published) {
+		t.Errorf("Expected the last checked timestamp to advance to %v, got %v", published, outbox.timestamp("channel-1"))
+	}
+}
+
+func TestDeliverPending_Interrupted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	outbox := expectOutbox(mockStore)
+
+	pending, err := store.NewOutboxMessage("run-1", "left@example.com", Subject, "<p>hi</p>", "hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending.Channels = map[string]time.Time{"channel-1": time.Now().Add(-time.Hour)}
+	if err := mockStore.SaveOutboxMessage(pending); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sender := NewMockEmailSender()
+	sender.onSend = cancel
+	sender.failures = []error{errors.New("connection reset by peer")}
+	runner := NewRunner(mockStore, NewMockChannelProcessor(), notify.NewDispatcher(sender, nil), nil, nil)
+	runner.retryConfig = testRetryConfig
+	if err := runner.DeliverPending(ctx); err == nil {
+		t.Fatal("Expected the interrupted delivery to be reported")
+	}
+
+	if message := outbox.message(pending.ID); message.Status != store.OutboxStatusPending {
+		t.Errorf("Expected the message to stay pending, got %+v", message)
+	}
+	if !outbox.timestamp("channel-1").IsZero() {
+		t.Errorf("Expected the last checked timestamp to stay put, got %v", outbox.timestamp("channel-1"))
+	}
+}
+
+func TestCommitTimestamps_HeldByPendingMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	outbox := expectOutbox(mockStore)
+
+	now := time.Now().Truncate(time.Second)
+	if err := mockStore.SaveOutboxMessage(&store.OutboxMessage{ID: "pending", Status: store.OutboxStatusPending, Channels: map[string]time.Time{"channel-1": now}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mockStore.SetLastCheckedTimestamp("channel-3", now); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(mockStore, NewMockChannelProcessor(), notify.NewDispatcher(NewMockEmailSender(), nil), nil, nil)
+	runner.commitTimestamps(map[string]time.Time{
+		"channel-1": now,
+		"channel-2": now,
+		"channel-3": now.Add(-time.Hour),
+	})
+
+	if !outbox.timestamp("channel-1").IsZero() {
+		t.Error("Expected the channel held by a pending message to keep its timestamp")
+	}
+	if !outbox.timestamp("channel-2").Equal(now) {
+		t.Error("Expected the free channel's timestamp to advance")
+	}
+	if !outbox.timestamp("channel-3").Equal(now) {
+		t.Error("Expected the last checked timestamp never to move back")
+	}
+}
+
+func TestResend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	outbox := expectOutbox(mockStore)
+	for _, message := range []*store.OutboxMessage{
+		{ID: "dead", Recipient: "me@example.com", Subject: Subject, Status: store.OutboxStatusDead, Attempts: 3, Error: "timeout"},
+		{ID: "sent", Recipient: "me@example.com", Subject: Subject, Status: store.OutboxStatusSent, Attempts: 1},
+	} {
+		if err := mockStore.SaveOutboxMessage(message); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sender := NewMockEmailSender()
+	runner := NewRunner(mockStore, NewMockChannelProcessor(), notify.NewDispatcher(sender, nil), nil, nil)
+	runner.retryConfig = testRetryConfig
+
+	message, err := runner.Resend(context.Background(), "dead")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if message.Status != store.OutboxStatusSent || message.Attempts != 4 || message.Error != "" || len(sender.sentEmails) != 1 {
+		t.Errorf("Expected the message to be sent on resend, got %+v", message)
+	}
+	if saved := outbox.message("dead"); saved.Status != store.OutboxStatusSent {
+		t.Errorf("Expected the resent message to be saved, got %+v", saved)
+	}
+
+	if _, err := runner.Resend(context.Background(), "sent"); !errors.Is(err, ErrNotDeadLetter) {
+		t.Errorf("Expected ErrNotDeadLetter, got %v", err)
+	}
+	if _, err := runner.Resend(context.Background(), "missing"); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("Expected ErrMessageNotFound, got %v", err)
+	}
+}
+
+func TestRunAndResend_InProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	runner := NewRunner(store.NewMockStore(ctrl), NewMockChannelProcessor(), notify.NewDispatcher(NewMockEmailSender(), nil), nil, nil)
+
+	// Hold the lock as a run in progress would; neither call may touch the store or wait
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	if result, err := runner.Run(context.Background(), Options{Trigger: store.RunTriggerManual}); !errors.Is(err, ErrRunInProgress) || result != nil {
+		t.Errorf("Expected ErrRunInProgress without a result, got %+v, %v", result, err)
+	}
+	if _, err := runner.Resend(context.Background(), "dead"); !errors.Is(err, ErrRunInProgress) {
+		t.Errorf("Expected ErrRunInProgress, got %v", err)
+	}
+}
@@ -0,0 +1,465 @@
+package newsletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"youtube-curator-v2/internal/config"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/http/retry"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/processor"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/summary"
+	"youtube-curator-v2/internal/webhooks"
+)
+
+// Subject is the subject line of newsletter emails
+const Subject = "New YouTube Videos Update"
+
+var (
+	// ErrNoChannels is returned when there are no channels to check
+	ErrNoChannels = errors.New("no channels configured")
+	// ErrChannelNotFound is returned when Options.ChannelID isn't a configured channel
+	ErrChannelNotFound = errors.New("channel not found")
+	// ErrRunInProgress is returned when a run or outbox delivery is already in progress
+	ErrRunInProgress = errors.New("a newsletter run is already in progress")
+	// ErrRunInterrupted is recorded on runs that were still running when the process stopped
+	ErrRunInterrupted = errors.New("interrupted")
+)
+
+// Options controls a newsletter run
+type Options struct {
+	Trigger           store.RunTrigger // Recorded with the run
+	ChannelID         string           // Only check this channel when set
+	IgnoreLastChecked bool             // Treat every video in the feeds as new and leave the last checked timestamps alone
+	MaxItems          int              // Maximum new videos taken from each feed; 0 means no limit
+	MaxPerChannel     int              // Overrides the configured per-channel cap in the email when set
+	DryRun            bool             // Render the emails instead of sending them; the run isn't recorded and no timestamps advance
+}
+
+// Email is a newsletter email rendered for a recipient
+type Email struct {
+	Recipient string
+	Subject   string
+	Videos    int // Videos listed in the email
+	Content   email.Content
+}
+
+// Result summarises a newsletter run
+type Result struct {
+	RunID             string // Empty for dry runs
+	ChannelsProcessed int
+	ChannelsWithError int
+	NewVideosFound    int
+	EmailsSent        int
+	EmailsFailed      int
+	Emails            []Email // Rendered emails; only set for dry runs
+}
+
+// Runner checks the channels for new videos and sends each recipient their digest. It is
+// shared by the scheduler and the API, so both record runs and behave the same way.
+// Digests go through the outbox: the channels' last checked timestamps only advance once
+// every digest listing their videos has been delivered or dead-lettered.
+type Runner struct {
+	store          store.Store
+	processor      processor.ChannelProcessor
+	notifier       notify.Notifier
+	summaryService summary.SummaryServiceInterface
+	config         *config.Config
+	retryConfig    retry.RetryConfig
+	publisher      webhooks.Publisher // Announces delivered digests; optional
+
+	mu sync.Mutex // Serialises runs and outbox deliveries so two runs never email the same videos
+}
+
+// NewRunner creates a new Runner. Digests are delivered through notifier, which picks each
+// subscriber's notification channel. The summary service may be nil to skip summaries.
+func NewRunner(store store.Store, channelProcessor processor.ChannelProcessor, notifier notify.Notifier, summaryService summary.SummaryServiceInterface, cfg *config.Config) *Runner {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	return &Runner{
+		store:          store,
+		processor:      channelProcessor,
+		notifier:       notifier,
+		summaryService: summaryService,
+		config:         cfg,
+		retryConfig:    DefaultDeliveryRetryConfig,
+	}
+}
+
+// SetPublisher sets where newsletter.sent events are published
+func (r *Runner) SetPublisher(publisher webhooks.Publisher) {
+	r.publisher = publisher
+}
+
+// FailInterruptedRuns marks runs left running by a previous shutdown or crash as failed,
+// returning how many were marked. Call it at startup, before any run can start.
+func (r *Runner) FailInterruptedRuns() (int, error) {
+	runs, err := r.store.GetRuns(0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	failed := 0
+	for i := range runs {
+		if runs[i].Status != store.RunStatusRunning {
+			continue
+		}
+		runs[i].Finish(ErrRunInterrupted)
+		if err := r.store.SaveRun(&runs[i]); err != nil {
+			return failed, fmt.Errorf("failed to mark run %s as interrupted: %w", runs[i].ID, err)
+		}
+		failed++
+	}
+	return failed, nil
+}
+
+// Run checks the channels selected by opts and emails the new videos. The returned result is
+// nil only if the run couldn't start; otherwise it is returned along with any error that
+// failed the run, such as there being no recipients or no email getting through. Rather than
+// wait for a run or outbox delivery already in progress, it returns ErrRunInProgress.
+func (r *Runner) Run(ctx context.Context, opts Options) (*Result, error) {
+	if !opts.DryRun {
+		if !r.mu.TryLock() {
+			return nil, ErrRunInProgress
+		}
+		defer r.mu.Unlock()
+
+		// Deliver what a previous run left behind first, so its videos aren't enqueued twice
+		if err := r.deliverPending(ctx); err != nil {
+			log.Printf("Warning: Failed to deliver pending outbox messages: %v", err)
+		}
+	}
+
+	channels, err := r.channels(opts.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the run so its outcome can be reviewed through the API
+	run, err := store.NewRun(opts.Trigger)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.DryRun {
+		r.saveRun(run)
+	}
+	result := &Result{}
+	if !opts.DryRun {
+		result.RunID = run.ID
+	}
+
+	err = r.run(ctx, opts, channels, run, result)
+	if !opts.DryRun {
+		run.Finish(err)
+		r.saveRun(run)
+	}
+	return result, err
+}
+
+// run does the work of a newsletter run, recording the outcome in run and result
+func (r *Runner) run(ctx context.Context, opts Options, channels []store.Channel, run *store.Run, result *Result) error {
+	// Process channels concurrently using the configured concurrency level. The last checked
+	// timestamps are committed once the emails are resolved, and dry runs leave no trace at all.
+	process := func(ctx context.Context, channelID string) processor.ChannelResult {
+		if opts.DryRun {
+			return r.processor.PreviewChannel(ctx, channelID, opts.IgnoreLastChecked, opts.MaxItems)
+		}
+		return r.processor.CollectChannel(ctx, channelID, opts.IgnoreLastChecked, opts.MaxItems)
+	}
+	results := processChannelsConcurrently(ctx, channels, process, r.config.RSSConcurrency)
+
+	// Group every new video by channel, in the order the channels are configured
+	var digests []email.ChannelDigest
+	timestamps := make(map[string]time.Time) // Last checked timestamp to commit per channel
+	for _, channel := range channels {
+		channelResult, ok := results[channel.ID]
+		if !ok {
+			continue
+		}
+		runChannel := store.RunChannel{ChannelID: channel.ID, ChannelTitle: channel.Title, NewVideos: len(channelResult.NewVideos)}
+		// Skip channels that had errors
+		if channelResult.Error != nil {
+			log.Printf("Error processing channel %s: %v\n", channel.ID, channelResult.Error)
+			runChannel.Error = channelResult.Error.Error()
+			run.Channels = append(run.Channels, runChannel)
+			result.ChannelsWithError++
+			continue
+		}
+		run.Channels = append(run.Channels, runChannel)
+		result.ChannelsProcessed++
+		if len(channelResult.NewVideos) > 0 {
+			digests = append(digests, email.NewChannelDigest(channel.ID, channel.Title, channelResult.NewVideos, 0))
+			result.NewVideosFound += len(channelResult.NewVideos)
+			if !opts.IgnoreLastChecked {
+				timestamps[channel.ID] = latestPublished(channelResult.NewVideos)
+			}
+		}
+	}
+	run.NewVideosFound = result.NewVideosFound
+
+	// Only send email if there are new videos from at least one channel
+	if result.NewVideosFound == 0 {
+		fmt.Println("No new videos found across all channels since last check.")
+		return nil
+	}
+	fmt.Printf("\nFound a total of %d new video(s) to email across %d channel(s).\n", result.NewVideosFound, len(digests))
+
+	newsletterConfig, err := r.store.GetNewsletterConfig()
+	if err != nil {
+		log.Printf("Warning: Failed to get newsletter configuration: %v", err)
+	}
+	if newsletterConfig == nil {
+		newsletterConfig = &store.NewsletterConfig{}
+	}
+	maxPerChannel := opts.MaxPerChannel
+	if maxPerChannel == 0 {
+		maxPerChannel = newsletterConfig.MaxVideosPerChannel
+	}
+
+	recipients, err := r.recipients()
+	if err != nil {
+		return fmt.Errorf("failed to get newsletter recipients: %w", err)
+	}
+	if len(recipients) == 0 {
+		if !opts.DryRun {
+			return errors.New("no recipient email configured")
+		}
+		// Nobody to send to yet, so render the full digest
+		recipients = []store.Subscriber{{Enabled: true}}
+	}
+
+	// Personalise the digest for each recipient
+	personalised := make([][]email.ChannelDigest, len(recipients))
+	var entries []*rss.Entry
+	for i := range recipients {
+		personalised[i] = email.SelectDigests(digests, recipients[i].Wants, maxPerChannel)
+		entries = append(entries, email.DigestEntries(personalised[i])...)
+	}
+
+	// Summarise the listed videos if enabled; videos without a summary show their description.
+	// Dry runs only use stored summaries, so they never call the LLM or announce new summaries.
+	if newsletterConfig.IncludeSummaries && len(entries) > 0 {
+		if opts.DryRun {
+			summarised := summary.AttachStoredSummaries(r.store, entries)
+			fmt.Printf("Found stored summaries for %d video(s) in the newsletter.\n", summarised)
+		} else if r.summaryService == nil {
+			log.Println("Warning: Newsletter summaries are enabled but the summary service is not configured")
+		} else {
+			summarised := summary.SummarizeEntries(ctx, r.summaryService, entries, summary.BatchOptions{
+				Concurrency: r.config.NewsletterSummaryConcurrency,
+				Budget:      r.config.NewsletterSummaryBudget,
+			})
+			fmt.Printf("Summarised %d video(s) for the newsletter.\n", summarised)
+		}
+	}
+
+	// Enqueue each recipient's digest in the outbox, then deliver them
+	var messages []*store.OutboxMessage
+	var sendErr error
+	held := make(map[string]bool)     // Channels listed in a digest, committed once their messages are resolved
+	unqueued := make(map[string]bool) // Channels listed in a digest that never reached the outbox
+	for i, recipient := range recipients {
+		runEmail := store.RunEmail{Recipient: recipient.Email, Videos: email.CountDigestVideos(personalised[i])}
+		if len(personalised[i]) == 0 {
+			fmt.Printf("No new videos for %s, skipping email\n", recipient.Email)
+			runEmail.Status = store.EmailStatusSkipped
+			run.Emails = append(run.Emails, runEmail)
+			continue
+		}
+		// Hold the channels before anything can fail, so a digest that never reaches the outbox keeps its videos new
+		for _, digest := range personalised[i] {
+			held[digest.ChannelID] = true
+		}
+		content, err := email.FormatDigestEmail(personalised[i])
+		if err == nil && opts.DryRun {
+			result.Emails = append(result.Emails, Email{Recipient: recipient.Email, Subject: Subject, Videos: runEmail.Videos, Content: content})
+			continue
+		}
+		var message *store.OutboxMessage
+		if err == nil {
+			message, err = r.enqueue(run.ID, recipient, content, runEmail.Videos, personalised[i], timestamps)
+		}
+		if err != nil {
+			log.Printf("Error preparing email to %s: %v\n", recipient.Email, err)
+			runEmail.Status = store.EmailStatusFailed
+			runEmail.Error = err.Error()
+			sendErr = err
+			result.EmailsFailed++
+			run.Emails = append(run.Emails, runEmail)
+			for _, digest := range personalised[i] {
+				unqueued[digest.ChannelID] = true
+			}
+			continue
+		}
+		messages = append(messages, message)
+		run.Emails = append(run.Emails, runEmail)
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	// Channels nobody wanted are done with straight away. Channels listed in a digest that never
+	// reached the outbox stay held for the whole run, so their videos are found again next time.
+	r.commitTimestamps(withoutChannels(timestamps, held))
+
+	sent := 0
+	for i := range run.Emails {
+		runEmail := &run.Emails[i]
+		if runEmail.Status != "" {
+			continue // Skipped, or never made it to the outbox
+		}
+		message := messages[sent]
+		sent++
+		runEmail.OutboxID = message.ID
+		err := ctx.Err()
+		if err == nil {
+			err = r.deliver(ctx, message)
+		}
+		if err != nil && ctx.Err() != nil {
+			// Interrupted: the message stays in the outbox and holds its channels' timestamps until delivered
+			log.Printf("Delivery of email to %s interrupted, leaving it in the outbox: %v\n", message.Recipient, err)
+			runEmail.Status = store.EmailStatusPending
+			sendErr = err
+			continue
+		}
+		if err != nil {
+			log.Printf("Error sending email to %s: %v\n", message.Recipient, err)
+			runEmail.Status = store.EmailStatusFailed
+			runEmail.Error = err.Error()
+			sendErr = err
+			result.EmailsFailed++
+		} else {
+			fmt.Printf("Email with %d video(s) sent successfully to %s\n", message.Videos, message.Recipient)
+			runEmail.Status = store.EmailStatusSent
+			result.EmailsSent++
+		}
+		r.commitTimestamps(withoutChannels(message.Channels, unqueued))
+	}
+	if _, err := r.store.PruneOutbox(store.OutboxHistoryLimit); err != nil {
+		log.Printf("Warning: Failed to prune newsletter outbox: %v", err)
+	}
+
+	// Only fail the run when nobody could be reached
+	if result.EmailsSent == 0 && sendErr != nil {
+		return fmt.Errorf("failed to send email: %w", sendErr)
+	}
+	return nil
+}
+
+// enqueue saves a rendered digest to the outbox as a pending message. The message holds back the
+// last checked timestamps of the channels it lists until it is resolved.
+func (r *Runner) enqueue(runID string, recipient store.Subscriber, content email.Content, videos int, digests []email.ChannelDigest, timestamps map[string]time.Time) (*store.OutboxMessage, error) {
+	message, err := store.NewOutboxMessage(runID, recipient.Email, Subject, content.HTML, content.Text)
+	if err != nil {
+		return nil, err
+	}
+	message.Videos = videos
+	message.Items = notify.DigestItems(digests)
+	message.Target = recipient.Notify
+	for _, digest := range digests {
+		if timestamp, ok := timestamps[digest.ChannelID]; ok {
+			if message.Channels == nil {
+				message.Channels = make(map[string]time.Time)
+			}
+			message.Channels[digest.ChannelID] = timestamp
+		}
+	}
+	if err := r.store.SaveOutboxMessage(message); err != nil {
+		return nil, fmt.Errorf("failed to enqueue email: %w", err)
+	}
+	return message, nil
+}
+
+// withoutChannels returns the timestamps of the channels not in excluded
+func withoutChannels(timestamps map[string]time.Time, excluded map[string]bool) map[string]time.Time {
+	kept := make(map[string]time.Time, len(timestamps))
+	for channelID, timestamp := range timestamps {
+		if !excluded[channelID] {
+			kept[channelID] = timestamp
+		}
+	}
+	return kept
+}
+
+// latestPublished returns the publication time of the most recent video
+func latestPublished(videos []rss.Entry) time.Time {
+	var latest time.Time
+	for _, video := range videos {
+		if video.Published.After(latest) {
+			latest = video.Published
+		}
+	}
+	return latest
+}
+
+// channels returns the channels to check, or just the one with channelID when it is set
+func (r *Runner) channels(channelID string) ([]store.Channel, error) {
+	channels, err := r.store.GetChannels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channels: %w", err)
+	}
+	if channelID != "" {
+		for _, channel := range channels {
+			if channel.ID == channelID {
+				return []store.Channel{channel}, nil
+			}
+		}
+		return nil, ErrChannelNotFound
+	}
+	if len(channels) == 0 {
+		return nil, ErrNoChannels
+	}
+	return channels, nil
+}
+
+// recipients returns the enabled subscribers, or when none are configured, the single
+// recipient from the SMTP configuration (falling back to RECIPIENT_EMAIL) with no channel filter
+func (r *Runner) recipients() ([]store.Subscriber, error) {
+	subscribers, err := r.store.GetSubscribers()
+	if err != nil {
+		return nil, err
+	}
+	if len(subscribers) > 0 {
+		var enabled []store.Subscriber
+		for _, subscriber := range subscribers {
+			if subscriber.Enabled {
+				enabled = append(enabled, subscriber)
+			}
+		}
+		return enabled, nil
+	}
+
+	// Get SMTP config from database for recipient email
+	recipientEmail := r.config.RecipientEmail
+	smtpConfig, err := r.store.GetSMTPConfig()
+	if err != nil {
+		log.Printf("Warning: Failed to get SMTP configuration: %v", err)
+	} else if smtpConfig != nil && smtpConfig.RecipientEmail != "" {
+		recipientEmail = smtpConfig.RecipientEmail
+	}
+	if recipientEmail == "" {
+		return nil, nil
+	}
+	return []store.Subscriber{{Email: recipientEmail, Enabled: true}}, nil
+}
+
+// saveRun persists a newsletter run, trimming the run history once it has finished
+func (r *Runner) saveRun(run *store.Run) {
+	if err := r.store.SaveRun(run); err != nil {
+		log.Printf("Warning: Failed to save newsletter run %s: %v", run.ID, err)
+		return
+	}
+	if run.FinishedAt != nil {
+		if _, err := r.store.PruneRuns(store.RunHistoryLimit); err != nil {
+			log.Printf("Warning: Failed to prune newsletter runs: %v", err)
+		}
+	}
+}
@@ -0,0 +1,809 @@
+package newsletter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/config"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/processor"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/summary"
+
+	"go.uber.org/mock/gomock"
+)
+
+// MockChannelProcessor is a mock implementation of processor.ChannelProcessor for testing
+type MockChannelProcessor struct {
+	results map[string]processor.ChannelResult
+
+	mu      sync.Mutex
+	options []processOptions // Options each channel was processed with
+}
+
+// processOptions records the options a channel was processed with
+type processOptions struct {
+	channelID         string
+	ignoreLastChecked bool
+	maxItems          int
+	preview           bool // Processed with PreviewChannel
+	collect           bool // Processed with CollectChannel
+}
+
+func NewMockChannelProcessor() *MockChannelProcessor {
+	return &MockChannelProcessor{
+		results: make(map[string]processor.ChannelResult),
+	}
+}
+
+func (m *MockChannelProcessor) ProcessChannel(ctx context.Context, channelID string) processor.ChannelResult {
+	if result, ok := m.results[channelID]; ok {
+		return result
+	}
+	return processor.ChannelResult{
+		ChannelID: channelID,
+		NewVideo:  nil,
+		Error:     nil,
+	}
+}
+
+func (m *MockChannelProcessor) ProcessChannelWithOptions(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) processor.ChannelResult {
+	m.mu.Lock()
+	m.options = append(m.options, processOptions{channelID: channelID, ignoreLastChecked: ignoreLastChecked, maxItems: maxItems})
+	m.mu.Unlock()
+	return m.ProcessChannel(ctx, channelID)
+}
+
+func (m *MockChannelProcessor) CollectChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) processor.ChannelResult {
+	m.mu.Lock()
+	m.options = append(m.options, processOptions{channelID: channelID, ignoreLastChecked: ignoreLastChecked, maxItems: maxItems, collect: true})
+	m.mu.Unlock()
+	return m.ProcessChannel(ctx, channelID)
+}
+
+func (m *MockChannelProcessor) PreviewChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) processor.ChannelResult {
+	m.mu.Lock()
+	m.options = append(m.options, processOptions{channelID: channelID, ignoreLastChecked: ignoreLastChecked, maxItems: maxItems, preview: true})
+	m.mu.Unlock()
+	return m.ProcessChannel(ctx, channelID)
+}
+
+// MockEmailSender is a mock implementation of email.Sender for testing
+type MockEmailSender struct {
+	sentEmails []SentEmail
+	failures   []error // Errors returned by the next sends, in order
+	onSend     func()  // Called before each send when set
+	attempts   int
+}
+
+type SentEmail struct {
+	Recipient string
+	Subject   string
+	Content   string
+}
+
+func NewMockEmailSender() *MockEmailSender {
+	return &MockEmailSender{
+		sentEmails: make([]SentEmail, 0),
+	}
+}
+
+func (m *MockEmailSender) Send(recipient string, subject string, content email.Content) error {
+	m.attempts++
+	if m.onSend != nil {
+		m.onSend()
+	}
+	if len(m.failures) > 0 {
+		err := m.failures[0]
+		m.failures = m.failures[1:]
+		if err != nil {
+			return err
+		}
+	}
+	m.sentEmails = append(m.sentEmails, SentEmail{
+		Recipient: recipient,
+		Subject:   subject,
+		Content:   content.HTML,
+	})
+	return nil
+}
+
+func TestRun_NoNewVideos(t *testing.T) {
+	// Setup
+	cfg := &config.Config{
+		RecipientEmail: "test@example.com",
+		RSSConcurrency: 3,
+	}
+
+	mockEmailSender := NewMockEmailSender()
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+
+	// Set up expectations for the mock store
+	channels := []store.Channel{
+		{ID: "channel-1", Title: "Channel 1"},
+		{ID: "channel-2", Title: "Channel 2"},
+		{ID: "channel-3", Title: "Channel 3"},
+	}
+	mockStore.EXPECT().GetChannels().Return(channels, nil)
+
+	// All channels return no new videos
+	for _, channelID := range []string{"channel-1", "channel-2", "channel-3"} {
+		mockProcessor.results[channelID] = processor.ChannelResult{
+			ChannelID: channelID,
+			NewVideo:  nil,
+			Error:     nil,
+		}
+	}
+
+	// Execute
+	runScheduled(t, NewRunner(mockStore, mockProcessor, notify.NewDispatcher(mockEmailSender, nil), nil, cfg))
+
+	// Verify - no emails should be sent
+	if len(mockEmailSender.sentEmails) != 0 {
+		t.Errorf("Expected no emails to be sent, but got %d", len(mockEmailSender.sentEmails))
+	}
+}
+
+func TestRun_WithNewVideos(t *testing.T) {
+	// Setup
+	cfg := &config.Config{
+		RecipientEmail: "test@example.com",
+		RSSConcurrency: 3,
+	}
+
+	mockEmailSender := NewMockEmailSender()
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+
+	// Set up expectations for the mock store
+	channels := []store.Channel{
+		{ID: "channel-1", Title: "Channel 1"},
+		{ID: "channel-2", Title: "Channel 2"},
+		{ID: "channel-3", Title: "Channel 3"},
+	}
+	mockStore.EXPECT().GetChannels().Return(channels, nil)
+
+	// Mock SMTP config retrieval - return a valid config
+	smtpConfig := &store.SMTPConfig{
+		Server:         "smtp.example.com",
+		Port:           "587",
+		Username:       "test@example.com",
+		Password:       "password",
+		RecipientEmail: "recipient@example.com",
+	}
+	mockStore.EXPECT().GetSMTPConfig().Return(smtpConfig, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil)
+
+	// Channel 1 has a new video
+	video1 := &rss.Entry{
+		Title:     "New Video from Channel 1",
+		Published: time.Now().Add(-2 * time.Hour),
+		ID:        "video-1",
+		Link:      rss.Link{Href: "https://youtube.com/watch?v=1"},
+	}
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideo:  video1,
+		NewVideos: []rss.Entry{*video1},
+		Error:     nil,
+	}
+
+	// Channel 2 has no new videos
+	mockProcessor.results["channel-2"] = processor.ChannelResult{
+		ChannelID: "channel-2",
+		NewVideo:  nil,
+		Error:     nil,
+	}
+
+	// Channel 3 has a new video
+	video3 := &rss.Entry{
+		Title:     "New Video from Channel 3",
+		Published: time.Now().Add(-1 * time.Hour),
+		ID:        "video-3",
+		Link:      rss.Link{Href: "https://youtube.com/watch?v=3"},
+	}
+	mockProcessor.results["channel-3"] = processor.ChannelResult{
+		ChannelID: "channel-3",
+		NewVideo:  video3,
+		NewVideos: []rss.Entry{*video3},
+		Error:     nil,
+	}
+
+	// Execute
+	runScheduled(t, NewRunner(mockStore, mockProcessor, notify.NewDispatcher(mockEmailSender, nil), nil, cfg))
+
+	// Verify
+	if len(mockEmailSender.sentEmails) != 1 {
+		t.Fatalf("Expected 1 email to be sent, but got %d", len(mockEmailSender.sentEmails))
+	}
+
+	sentEmail := mockEmailSender.sentEmails[0]
+	if sentEmail.Recipient != smtpConfig.RecipientEmail {
+		t.Errorf("Expected email recipient to be %s, but got %s", smtpConfig.RecipientEmail, sentEmail.Recipient)
+	}
+	if sentEmail.Subject != "New YouTube Videos Update" {
+		t.Errorf("Expected email subject to be 'New YouTube Videos Update', but got '%s'", sentEmail.Subject)
+	}
+
+	// Verify the email contains both videos
+	if !contains(sentEmail.Content, video1.Title) {
+		t.Errorf("Expected email to contain video 1 title '%s'", video1.Title)
+	}
+	if !contains(sentEmail.Content, video3.Title) {
+		t.Errorf("Expected email to contain video 3 title '%s'", video3.Title)
+	}
+}
+
+func TestRun_AllNewVideosPerChannel(t *testing.T) {
+	// Setup
+	cfg := &config.Config{
+		RecipientEmail: "test@example.com",
+		RSSConcurrency: 2,
+	}
+
+	mockEmailSender := NewMockEmailSender()
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+
+	channels := []store.Channel{
+		{ID: "channel-1", Title: "Busy Channel"},
+		{ID: "channel-2", Title: "Quiet Channel"},
+	}
+	mockStore.EXPECT().GetChannels().Return(channels, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(&store.SMTPConfig{RecipientEmail: "recipient@example.com"}, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	// Cap the digest at two videos per channel
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true, MaxVideosPerChannel: 2}, nil)
+
+	// Channel 1 uploaded three videos since the last check
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideos: []rss.Entry{
+			{ID: "video-1", Title: "Busy Upload 1", Published: time.Now().Add(-1 * time.Hour)},
+			{ID: "video-2", Title: "Busy Upload 2", Published: time.Now().Add(-2 * time.Hour)},
+			{ID: "video-3", Title: "Busy Upload 3", Published: time.Now().Add(-3 * time.Hour)},
+		},
+	}
+	mockProcessor.results["channel-2"] = processor.ChannelResult{
+		ChannelID: "channel-2",
+		NewVideos: []rss.Entry{
+			{ID: "video-4", Title: "Quiet Upload", Published: time.Now().Add(-5 * time.Hour)},
+		},
+	}
+
+	// Execute
+	runScheduled(t, NewRunner(mockStore, mockProcessor, notify.NewDispatcher(mockEmailSender, nil), nil, cfg))
+
+	// Verify
+	if len(mockEmailSender.sentEmails) != 1 {
+		t.Fatalf("Expected 1 email to be sent, but got %d", len(mockEmailSender.sentEmails))
+	}
+	content := mockEmailSender.sentEmails[0].Content
+	for _, want := range []string{"Busy Channel", "Busy Upload 1", "Busy Upload 2", "+1 more new video(s)", "Quiet Channel", "Quiet Upload"} {
+		if !contains(content, want) {
+			t.Errorf("Expected email to contain '%s'", want)
+		}
+	}
+	if contains(content, "Busy Upload 3") {
+		t.Error("Expected the per-channel cap to leave out 'Busy Upload 3'")
+	}
+}
+
+func TestRun_PersonalisedPerSubscriber(t *testing.T) {
+	// Setup
+	cfg := &config.Config{
+		RecipientEmail: "fallback@example.com",
+		RSSConcurrency: 2,
+	}
+
+	mockEmailSender := NewMockEmailSender()
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+
+	channels := []store.Channel{
+		{ID: "channel-1", Title: "Go Channel"},
+		{ID: "channel-2", Title: "Cooking Channel"},
+	}
+	mockStore.EXPECT().GetChannels().Return(channels, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil)
+	// With subscribers configured, the SMTP recipient is not used
+	mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{
+		{ID: "1", Email: "gopher@example.com", ChannelIDs: []string{"channel-1"}, Enabled: true},
+		{ID: "2", Email: "chef@example.com", ChannelIDs: []string{"channel-2"}, Enabled: true},
+		{ID: "3", Email: "everything@example.com", Enabled: true},
+		{ID: "4", Email: "paused@example.com", Enabled: false},
+		{ID: "5", Email: "rust@example.com", Tags: []string{"rust"}, Enabled: true},
+	}, nil)
+
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideos: []rss.Entry{{ID: "video-1", Title: "Go Generics", Published: time.Now().Add(-1 * time.Hour)}},
+	}
+	mockProcessor.results["channel-2"] = processor.ChannelResult{
+		ChannelID: "channel-2",
+		NewVideos: []rss.Entry{{ID: "video-2", Title: "Sourdough Basics", Published: time.Now().Add(-2 * time.Hour)}},
+	}
+
+	// Execute
+	runScheduled(t, NewRunner(mockStore, mockProcessor, notify.NewDispatcher(mockEmailSender, nil), nil, cfg))
+
+	// Verify - one email per interested, enabled subscriber
+	sent := make(map[string]string)
+	for _, email := range mockEmailSender.sentEmails {
+		sent[email.Recipient] = email.Content
+	}
+	if len(sent) != 3 {
+		t.Fatalf("Expected 3 emails to be sent, but got %d: %v", len(mockEmailSender.sentEmails), mockEmailSender.sentEmails)
+	}
+	if content := sent["gopher@example.com"]; !contains(content, "Go Generics") || contains(content, "Sourdough Basics") {
+		t.Error("Expected gopher@example.com to receive only the Go Channel video")
+	}
+	if content := sent["chef@example.com"]; !contains(content, "Sourdough Basics") || contains(content, "Go Generics") {
+		t.Error("Expected chef@example.com to receive only the Cooking Channel video")
+	}
+	if content := sent["everything@example.com"]; !contains(content, "Go Generics") || !contains(content, "Sourdough Basics") {
+		t.Error("Expected everything@example.com to receive both videos")
+	}
+}
+
+func TestRun_MixedResults(t *testing.T) {
+	// Setup
+	cfg := &config.Config{
+		RecipientEmail: "test@example.com",
+		RSSConcurrency: 3,
+	}
+
+	mockEmailSender := NewMockEmailSender()
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	run := expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+
+	// Set up expectations for the mock store
+	channels := []store.Channel{
+		{ID: "channel-1", Title: "Channel 1"},
+		{ID: "channel-2", Title: "Channel 2"},
+		{ID: "channel-3", Title: "Channel 3"},
+	}
+	mockStore.EXPECT().GetChannels().Return(channels, nil)
+
+	// Mock SMTP config retrieval - return a valid config
+	smtpConfig := &store.SMTPConfig{
+		Server:         "smtp.example.com",
+		Port:           "587",
+		Username:       "test@example.com",
+		Password:       "password",
+		RecipientEmail: "recipient@example.com",
+	}
+	mockStore.EXPECT().GetSMTPConfig().Return(smtpConfig, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil)
+
+	// Channel 1 has an error
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideo:  nil,
+		Error:     context.DeadlineExceeded,
+	}
+
+	// Channel 2 has a new video
+	video2 := &rss.Entry{
+		Title:     "New Video from Channel 2",
+		Published: time.Now().Add(-30 * time.Minute),
+		ID:        "video-2",
+		Link:      rss.Link{Href: "https://youtube.com/watch?v=2"},
+	}
+	mockProcessor.results["channel-2"] = processor.ChannelResult{
+		ChannelID: "channel-2",
+		NewVideo:  video2,
+		NewVideos: []rss.Entry{*video2},
+		Error:     nil,
+	}
+
+	// Channel 3 has no new videos
+	mockProcessor.results["channel-3"] = processor.ChannelResult{
+		ChannelID: "channel-3",
+		NewVideo:  nil,
+		Error:     nil,
+	}
+
+	// Execute
+	runScheduled(t, NewRunner(mockStore, mockProcessor, notify.NewDispatcher(mockEmailSender, nil), nil, cfg))
+
+	// Verify - should still send email with the one successful video
+	if len(mockEmailSender.sentEmails) != 1 {
+		t.Fatalf("Expected 1 email to be sent despite error, but got %d", len(mockEmailSender.sentEmails))
+	}
+
+	sentEmail := mockEmailSender.sentEmails[0]
+	if !contains(sentEmail.Content, video2.Title) {
+		t.Errorf("Expected email to contain video 2 title '%s'", video2.Title)
+	}
+
+	// Verify the run is recorded with the channel error and the delivered email
+	if run.Status != store.RunStatusSucceeded || run.Trigger != store.RunTriggerScheduled || run.FinishedAt == nil {
+		t.Errorf("Expected a finished, successful scheduled run, got %+v", run)
+	}
+	if run.NewVideosFound != 1 || len(run.Channels) != 3 {
+		t.Fatalf("Expected 3 channels and 1 new video in the run, got %+v", run)
+	}
+	if run.Channels[0].ChannelID != "channel-1" || run.Channels[0].Error == "" {
+		t.Errorf("Expected the channel 1 error to be recorded, got %+v", run.Channels[0])
+	}
+	if len(run.Emails) != 1 || run.Emails[0].Status != store.EmailStatusSent || run.Emails[0].Videos != 1 {
+		t.Errorf("Expected one sent email in the run, got %+v", run.Emails)
+	}
+}
+
+// runScheduled runs the newsletter as the scheduler does
+func runScheduled(t *testing.T, runner *Runner) *Result {
+	t.Helper()
+	result, err := runner.Run(context.Background(), Options{Trigger: store.RunTriggerScheduled})
+	if err != nil {
+		t.Fatalf("Unexpected run error: %v", err)
+	}
+	return result
+}
+
+// expectRunHistory accepts the run history writes made by Run and returns the last saved run
+func expectRunHistory(mockStore *store.MockStore) *store.Run {
+	saved := &store.Run{}
+	mockStore.EXPECT().SaveRun(gomock.Any()).DoAndReturn(func(run *store.Run) error {
+		*saved = *run
+		return nil
+	}).AnyTimes()
+	mockStore.EXPECT().PruneRuns(store.RunHistoryLimit).Return(0, nil).AnyTimes()
+	return saved
+}
+
+// memoryOutbox backs the outbox and last checked timestamps of a mock store
+type memoryOutbox struct {
+	mu         sync.Mutex
+	messages   map[string]store.OutboxMessage
+	timestamps map[string]time.Time
+}
+
+// expectOutbox accepts the outbox and last checked timestamp calls made by Run, keeping their state in memory
+func expectOutbox(mockStore *store.MockStore) *memoryOutbox {
+	outbox := &memoryOutbox{messages: make(map[string]store.OutboxMessage), timestamps: make(map[string]time.Time)}
+	mockStore.EXPECT().SaveOutboxMessage(gomock.Any()).DoAndReturn(func(message *store.OutboxMessage) error {
+		outbox.mu.Lock()
+		defer outbox.mu.Unlock()
+		outbox.messages[message.ID] = *message
+		return nil
+	}).AnyTimes()
+	mockStore.EXPECT().GetOutboxMessage(gomock.Any()).DoAndReturn(func(id string) (*store.OutboxMessage, error) {
+		outbox.mu.Lock()
+		defer outbox.mu.Unlock()
+		if message, ok := outbox.messages[id]; ok {
+			return &message, nil
+		}
+		return nil, nil
+	}).AnyTimes()
+	mockStore.EXPECT().GetOutboxMessages(gomock.Any()).DoAndReturn(func(statuses ...store.OutboxStatus) ([]store.OutboxMessage, error) {
+		outbox.mu.Lock()
+		defer outbox.mu.Unlock()
+		var messages []store.OutboxMessage
+		for _, message := range outbox.messages {
+			for _, status := range statuses {
+				if message.Status == status {
+					messages = append(messages, message)
+				}
+			}
+		}
+		return messages, nil
+	}).AnyTimes()
+	mockStore.EXPECT().PruneOutbox(store.OutboxHistoryLimit).Return(0, nil).AnyTimes()
+	mockStore.EXPECT().GetLastCheckedTimestamp(gomock.Any()).DoAndReturn(func(channelID string) (time.Time, error) {
+		outbox.mu.Lock()
+		defer outbox.mu.Unlock()
+		return outbox.timestamps[channelID], nil
+	}).AnyTimes()
+	mockStore.EXPECT().SetLastCheckedTimestamp(gomock.Any(), gomock.Any()).DoAndReturn(func(channelID string, timestamp time.Time) error {
+		outbox.mu.Lock()
+		defer outbox.mu.Unlock()
+		outbox.timestamps[channelID] = timestamp
+		return nil
+	}).AnyTimes()
+	return outbox
+}
+
+// message returns the outbox message with the given ID
+func (o *memoryOutbox) message(id string) store.OutboxMessage {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.messages[id]
+}
+
+// timestamp returns a channel's last checked timestamp
+func (o *memoryOutbox) timestamp(channelID string) time.Time {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.timestamps[channelID]
+}
+
+// Helper function to check if a string contains a substring
+func contains(s, substr string) bool {
+	return len(s) > 0 && len(substr) > 0 && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || contains(s[1:], substr)))
+}
+
+func TestRun_FallbackToConfigEmail(t *testing.T) {
+	// Setup
+	cfg := &config.Config{
+		RecipientEmail: "fallback@example.com",
+		RSSConcurrency: 1,
+	}
+
+	mockEmailSender := NewMockEmailSender()
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+
+	// Set up expectations for the mock store
+	channels := []store.Channel{
+		{ID: "channel-1", Title: "Channel 1"},
+	}
+	mockStore.EXPECT().GetChannels().Return(channels, nil)
+
+	// Mock SMTP config retrieval - return nil (no config in database)
+	mockStore.EXPECT().GetSMTPConfig().Return(nil, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil)
+
+	// Channel 1 has a new video
+	video1 := &rss.Entry{
+		Title:     "New Video from Channel 1",
+		Published: time.Now().Add(-2 * time.Hour),
+		ID:        "video-1",
+		Link:      rss.Link{Href: "https://youtube.com/watch?v=1"},
+	}
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideo:  video1,
+		NewVideos: []rss.Entry{*video1},
+		Error:     nil,
+	}
+
+	// Execute
+	runScheduled(t, NewRunner(mockStore, mockProcessor, notify.NewDispatcher(mockEmailSender, nil), nil, cfg))
+
+	// Verify - should fallback to config.RecipientEmail
+	if len(mockEmailSender.sentEmails) != 1 {
+		t.Fatalf("Expected 1 email to be sent, but got %d", len(mockEmailSender.sentEmails))
+	}
+
+	sentEmail := mockEmailSender.sentEmails[0]
+	if sentEmail.Recipient != cfg.RecipientEmail {
+		t.Errorf("Expected email recipient to be %s (fallback), but got %s", cfg.RecipientEmail, sentEmail.Recipient)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	cfg := &config.Config{RecipientEmail: "me@example.com", RSSConcurrency: 1}
+
+	mockEmailSender := NewMockEmailSender()
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	// A dry run saves no run history, so SaveRun must not be called
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "channel-1", Title: "Channel 1"}, {ID: "channel-2", Title: "Channel 2"}}, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(nil, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(nil, nil)
+
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideos: []rss.Entry{{ID: "video-1", Title: "Preview Me", Published: time.Now()}},
+	}
+
+	result, err := NewRunner(mockStore, mockProcessor, notify.NewDispatcher(mockEmailSender, nil), nil, cfg).Run(context.Background(), Options{
+		Trigger:           store.RunTriggerManual,
+		ChannelID:         "channel-1",
+		IgnoreLastChecked: true,
+		MaxItems:          5,
+		DryRun:            true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected run error: %v", err)
+	}
+
+	if len(mockEmailSender.sentEmails) != 0 {
+		t.Errorf("Expected a dry run not to send email, but %d were sent", len(mockEmailSender.sentEmails))
+	}
+	if result.RunID != "" || result.EmailsSent != 0 || result.ChannelsProcessed != 1 || result.NewVideosFound != 1 {
+		t.Errorf("Unexpected dry run result %+v", result)
+	}
+	if len(result.Emails) != 1 || result.Emails[0].Recipient != "me@example.com" || result.Emails[0].Subject != Subject || result.Emails[0].Videos != 1 {
+		t.Fatalf("Expected one rendered email, got %+v", result.Emails)
+	}
+	if !contains(result.Emails[0].Content.HTML, "Preview Me") || !contains(result.Emails[0].Content.Text, "Preview Me") {
+		t.Error("Expected the rendered email to list the new video")
+	}
+	// Only the requested channel is checked, with the requested options
+	if len(mockProcessor.options) != 1 || mockProcessor.options[0] != (processOptions{channelID: "channel-1", ignoreLastChecked: true, maxItems: 5, preview: true}) {
+		t.Errorf("Unexpected processor calls %+v", mockProcessor.options)
+	}
+}
+
+// generatingSummaryService counts the summaries asked of it, as if each were generated
+type generatingSummaryService struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *generatingSummaryService) GetOrGenerateSummary(ctx context.Context, videoID string) *summary.SummaryResult {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	return &summary.SummaryResult{VideoID: videoID, Summary: "Generated summary"}
+}
+
+func (s *generatingSummaryService) RegenerateSummary(ctx context.Context, videoID string) *summary.SummaryResult {
+	return s.GetOrGenerateSummary(ctx, videoID)
+}
+
+func (s *generatingSummaryService) StreamSummary(ctx context.Context, videoID string, regenerate bool, events chan<- summary.StreamEvent) {
+	close(events)
+}
+
+func TestRun_DryRunUsesStoredSummaries(t *testing.T) {
+	cfg := &config.Config{RecipientEmail: "me@example.com", RSSConcurrency: 1}
+
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "channel-1", Title: "Channel 1"}}, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(nil, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{IncludeSummaries: true}, nil)
+	mockStore.EXPECT().GetSummary("stored").Return(&store.StoredSummary{VideoID: "stored", Text: "Stored summary"}, nil)
+	mockStore.EXPECT().GetSummary("unsummarised").Return(nil, nil)
+
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideos: []rss.Entry{
+			{ID: "stored", Title: "Summarised Video", Published: time.Now()},
+			{ID: "unsummarised", Title: "Unsummarised Video", Published: time.Now().Add(-time.Hour)},
+		},
+	}
+	summaries := &generatingSummaryService{}
+
+	result, err := NewRunner(mockStore, mockProcessor, notify.NewDispatcher(NewMockEmailSender(), nil), summaries, cfg).Run(context.Background(), Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Unexpected run error: %v", err)
+	}
+
+	// A dry run never generates summaries, which would call the LLM and announce them
+	if summaries.calls != 0 {
+		t.Errorf("Expected a dry run not to generate summaries, but %d were requested", summaries.calls)
+	}
+	if len(result.Emails) != 1 {
+		t.Fatalf("Expected one rendered email, got %+v", result.Emails)
+	}
+	if !contains(result.Emails[0].Content.HTML, "Stored summary") || contains(result.Emails[0].Content.HTML, "Generated summary") {
+		t.Error("Expected the rendered email to show the stored summary only")
+	}
+}
+
+func TestRun_PassesOptions(t *testing.T) {
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "channel-1"}, {ID: "channel-2"}}, nil)
+
+	result, err := NewRunner(mockStore, mockProcessor, notify.NewDispatcher(NewMockEmailSender(), nil), nil, nil).Run(context.Background(), Options{IgnoreLastChecked: true, MaxItems: 3})
+	if err != nil {
+		t.Fatalf("Unexpected run error: %v", err)
+	}
+	if result.ChannelsProcessed != 2 || len(mockProcessor.options) != 2 {
+		t.Fatalf("Expected both channels to be processed, got %+v", mockProcessor.options)
+	}
+	for _, options := range mockProcessor.options {
+		// The runner commits the last checked timestamps itself once the emails are resolved
+		if !options.ignoreLastChecked || options.maxItems != 3 || !options.collect {
+			t.Errorf("Expected the run options to reach the processor, got %+v", options)
+		}
+	}
+}
+
+func TestRun_ChannelSelection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return(nil, nil)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "channel-1"}}, nil)
+	runner := NewRunner(mockStore, NewMockChannelProcessor(), notify.NewDispatcher(NewMockEmailSender(), nil), nil, nil)
+
+	if _, err := runner.Run(context.Background(), Options{}); err != ErrNoChannels {
+		t.Errorf("Expected ErrNoChannels, got %v", err)
+	}
+	if _, err := runner.Run(context.Background(), Options{ChannelID: "channel-2"}); err != ErrChannelNotFound {
+		t.Errorf("Expected ErrChannelNotFound, got %v", err)
+	}
+}
+
+func TestRun_NoRecipients(t *testing.T) {
+	mockProcessor := NewMockChannelProcessor()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	run := expectRunHistory(mockStore)
+	expectOutbox(mockStore)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "channel-1"}}, nil)
+	mockStore.EXPECT().GetSMTPConfig().Return(nil, nil)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil)
+	mockStore.EXPECT().GetNewsletterConfig().Return(nil, nil)
+	mockProcessor.results["channel-1"] = processor.ChannelResult{
+		ChannelID: "channel-1",
+		NewVideos: []rss.Entry{{ID: "video-1", Title: "Nobody Hears This", Published: time.Now()}},
+	}
+
+	result, err := NewRunner(mockStore, mockProcessor, notify.NewDispatcher(NewMockEmailSender(), nil), nil, &config.Config{}).Run(context.Background(), Options{Trigger: store.RunTriggerScheduled})
+	if err == nil || !contains(err.Error(), "no recipient email configured") {
+		t.Fatalf("Expected a missing recipient error, got %v", err)
+	}
+	if result == nil || result.RunID != run.ID {
+		t.Fatalf("Expected the result of the recorded run, got %+v", result)
+	}
+	if run.Status != store.RunStatusFailed || run.Error != err.Error() {
+		t.Errorf("Expected the run to be recorded as failed, got %+v", run)
+	}
+}
+
+func TestFailInterruptedRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+
+	finished := time.Now().Add(-time.Hour)
+	mockStore.EXPECT().GetRuns(0).Return([]store.Run{
+		{ID: "run-2", Status: store.RunStatusRunning, StartedAt: time.Now().Add(-time.Minute)},
+		{ID: "run-1", Status: store.RunStatusSucceeded, StartedAt: finished.Add(-time.Minute), FinishedAt: &finished},
+	}, nil)
+	var saved *store.Run
+	mockStore.EXPECT().SaveRun(gomock.Any()).DoAndReturn(func(run *store.Run) error {
+		saved = run
+		return nil
+	})
+
+	failed, err := NewRunner(mockStore, NewMockChannelProcessor(), notify.NewDispatcher(NewMockEmailSender(), nil), nil, nil).FailInterruptedRuns()
+	if err != nil || failed != 1 {
+		t.Fatalf("Expected one interrupted run, got %d (err %v)", failed, err)
+	}
+	if saved == nil || saved.ID != "run-2" || saved.Status != store.RunStatusFailed || saved.Error != ErrRunInterrupted.Error() || saved.FinishedAt == nil {
+		t.Errorf("Expected the running run to be recorded as interrupted, got %+v", saved)
+	}
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"youtube-curator-v2/internal/store"
+)
+
+// discordContentLimit is the most characters Discord accepts in a message
+const discordContentLimit = 2000
+
+var (
+	slackEscaper   = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	discordEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "[", `\[`, "]", `\]`, "|", `\|`)
+)
+
+// SlackNotifier posts the digest to a Slack incoming webhook, or to anything that accepts
+// the same payload such as Mattermost
+type SlackNotifier struct {
+	client *http.Client
+}
+
+// NewSlackNotifier creates a notifier that posts through client
+func NewSlackNotifier(client *http.Client) *SlackNotifier {
+	return &SlackNotifier{client: client}
+}
+
+// Notify posts the digest to target.URL as Slack mrkdwn
+func (n *SlackNotifier) Notify(ctx context.Context, target store.NotifyTarget, message Message) error {
+	text := listing(message, 0, listingFormat{
+		heading: func(s string) string { return "*" + slackEscaper.Replace(s) + "*" },
+		channel: func(s string) string { return "*" + slackEscaper.Replace(s) + "*" },
+		video: func(item store.DigestItem) string {
+			return fmt.Sprintf("• <%s|%s>", item.URL, slackEscaper.Replace(item.Title))
+		},
+	})
+	body, err := json.Marshal(map[string]any{"text": text, "unfurl_links": false})
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("failed to marshal slack payload: %w", err)}
+	}
+	return Post(ctx, n.client, target.URL, "application/json", body, nil)
+}
+
+// DiscordNotifier posts the digest to a Discord webhook. Discord caps messages at 2000
+// characters, so long digests end with a count of the videos left out.
+type DiscordNotifier struct {
+	client *http.Client
+}
+
+// NewDiscordNotifier creates a notifier that posts through client
+func NewDiscordNotifier(client *http.Client) *DiscordNotifier {
+	return &DiscordNotifier{client: client}
+}
+
+// Notify posts the digest to target.URL as Discord markdown
+func (n *DiscordNotifier) Notify(ctx context.Context, target store.NotifyTarget, message Message) error {
+	content := listing(message, discordContentLimit, listingFormat{
+		heading: func(s string) string { return "**" + discordEscaper.Replace(s) + "**" },
+		channel: func(s string) string { return "__" + discordEscaper.Replace(s) + "__" },
+		video: func(item store.DigestItem) string {
+			// Angle brackets stop Discord from embedding a preview of every video
+			return fmt.Sprintf("- [%s](<%s>)", discordEscaper.Replace(item.Title), item.URL)
+		},
+	})
+	body, err := json.Marshal(map[string]string{"content": content})
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("failed to marshal discord payload: %w", err)}
+	}
+	return Post(ctx, n.client, target.URL, "application/json", body, nil)
+}
+
+// listingFormat renders the lines of a digest listing
+type listingFormat struct {
+	heading func(subject string) string
+	channel func(title string) string
+	video   func(item store.DigestItem) string
+}
+
+// listing renders the digest's videos grouped by channel, in the order they were listed. With a
+// limit, lines that would take it past limit characters are dropped and replaced by a count of
+// the videos left out. Messages without items fall back to the plain-text digest.
+func listing(message Message, limit int, format listingFormat) string {
+	heading := format.heading(message.Subject)
+	if len(message.Items) == 0 {
+		return truncate(heading+"\n"+message.Content.Text, limit)
+	}
+
+	var b strings.Builder
+	b.WriteString(heading)
+	channelID := ""
+	for i, item := range message.Items {
+		next := ""
+		if i == 0 || item.ChannelID != channelID {
+			channelID = item.ChannelID
+			next = "\n\n" + format.channel(item.ChannelTitle)
+		}
+		next += "\n" + format.video(item)
+
+		if limit > 0 {
+			// Keep room for a note about the videos left out, unless this is the last one
+			more := fmt.Sprintf("\n\n…and %d more video(s)", len(message.Items)-i)
+			size := utf8.RuneCountInString(b.String()) + utf8.RuneCountInString(next)
+			last := i == len(message.Items)-1
+			if (last && size > limit) || (!last && size+utf8.RuneCountInString(more) > limit) {
+				b.WriteString(more)
+				break
+			}
+		}
+		b.WriteString(next)
+	}
+	return b.String()
+}
+
+// truncate cuts s to at most limit characters; 0 means no limit
+func truncate(s string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit-1]) + "…"
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"youtube-curator-v2/internal/store"
+)
+
+func TestSlackNotifier(t *testing.T) {
+	server := newTestServer(t, http.StatusOK)
+	if err := NewSlackNotifier(server.Client()).Notify(context.Background(), store.NotifyTarget{Type: TypeSlack, URL: server.URL}, testMessage()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var payload struct {
+		Text        string `json:"text"`
+		UnfurlLinks bool   `json:"unfurl_links"`
+	}
+	if err := json.Unmarshal(server.only(t).Body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	expected := "*New videos from your channels*\n\n" +
+		"*Go Talks*\n" +
+		"• <https://www.youtube.com/watch?v=a1|Generics &lt;in&gt; practice>\n" +
+		"• <https://www.youtube.com/watch?v=a2|Fuzzing_101>\n\n" +
+		"*Rust &amp; Friends*\n" +
+		"• <https://www.youtube.com/watch?v=b1|Borrowing>"
+	if payload.Text != expected {
+		t.Errorf("Expected text:\n%s\ngot:\n%s", expected, payload.Text)
+	}
+	if payload.UnfurlLinks {
+		t.Error("Expected link previews to be turned off")
+	}
+}
+
+func TestDiscordNotifier(t *testing.T) {
+	server := newTestServer(t, http.StatusNoContent)
+	if err := NewDiscordNotifier(server.Client()).Notify(context.Background(), store.NotifyTarget{Type: TypeDiscord, URL: server.URL}, testMessage()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var payload struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(server.only(t).Body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	expected := "**New videos from your channels**\n\n" +
+		"__Go Talks__\n" +
+		"- [Generics <in> practice](<https://www.youtube.com/watch?v=a1>)\n" +
+		"- [Fuzzing\\_101](<https://www.youtube.com/watch?v=a2>)\n\n" +
+		"__Rust & Friends__\n" +
+		"- [Borrowing](<https://www.youtube.com/watch?v=b1>)"
+	if payload.Content != expected {
+		t.Errorf("Expected content:\n%s\ngot:\n%s", expected, payload.Content)
+	}
+}
+
+func TestDiscordNotifier_LongDigest(t *testing.T) {
+	message := testMessage()
+	message.Items = nil
+	for i := 0; i < 100; i++ {
+		message.Items = append(message.Items, store.DigestItem{
+			ChannelID:    "UC1",
+			ChannelTitle: "Go Talks",
+			Title:        fmt.Sprintf("Episode %d", i),
+			URL:          fmt.Sprintf("https://www.youtube.com/watch?v=episode%d", i),
+		})
+	}
+
+	server := newTestServer(t, http.StatusNoContent)
+	if err := NewDiscordNotifier(server.Client()).Notify(context.Background(), store.NotifyTarget{URL: server.URL}, message); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var payload struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(server.only(t).Body, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := utf8.RuneCountInString(payload.Content); n > discordContentLimit {
+		t.Errorf("Expected at most %d characters, got %d", discordContentLimit, n)
+	}
+	listed := strings.Count(payload.Content, "\n- ")
+	if !strings.HasSuffix(payload.Content, fmt.Sprintf("…and %d more video(s)", 100-listed)) {
+		t.Errorf("Expected a count of the %d videos left out, got %q", 100-listed, payload.Content[len(payload.Content)-40:])
+	}
+}
+
+func TestListing_WithoutItems(t *testing.T) {
+	message := testMessage()
+	message.Items = nil
+	format := listingFormat{
+		heading: func(s string) string { return "# " + s },
+		channel: func(s string) string { return s },
+		video:   func(item store.DigestItem) string { return item.Title },
+	}
+
+	if got := listing(message, 0, format); got != "# New videos from your channels\nNew videos" {
+		t.Errorf("Expected the plain-text digest, got %q", got)
+	}
+	if got := listing(message, 10, format); got != "# New vid…" {
+		t.Errorf("Expected the digest cut to 10 characters, got %q", got)
+	}
+}
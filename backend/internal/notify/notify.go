@@ -0,0 +1,185 @@
+// Package notify delivers newsletter digests through the notification channel each subscriber
+// has chosen: email, a signed generic webhook, Slack or Discord incoming webhooks, ntfy or Gotify.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/store"
+)
+
+// Notification channel types, as set in store.NotifyTarget.Type
+const (
+	TypeEmail   = "email"
+	TypeWebhook = "webhook"
+	TypeSlack   = "slack"
+	TypeDiscord = "discord"
+	TypeNtfy    = "ntfy"
+	TypeGotify  = "gotify"
+)
+
+// defaultTimeout bounds a single delivery over HTTP
+const defaultTimeout = 30 * time.Second
+
+// Message is a rendered newsletter digest addressed to one subscriber
+type Message struct {
+	Recipient string        // Subscriber email address, where email digests are sent
+	Subject   string        // Email subject, and the title of push notifications
+	Content   email.Content // HTML and plain-text renderings of the digest
+	Items     []store.DigestItem
+}
+
+// DigestItems lists the videos of a digest, in the order they appear in it
+func DigestItems(digests []email.ChannelDigest) []store.DigestItem {
+	var items []store.DigestItem
+	for _, digest := range digests {
+		for _, video := range digest.Videos {
+			item := store.DigestItem{
+				ChannelID:    digest.ChannelID,
+				ChannelTitle: digest.ChannelTitle,
+				VideoID:      strings.TrimPrefix(video.ID, "yt:video:"),
+				Title:        video.Title,
+				URL:          video.Link.Href,
+				Published:    video.Published,
+			}
+			if video.Summary != nil {
+				item.Summary = video.Summary.Text
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Notifier delivers a message through a notification channel
+type Notifier interface {
+	Notify(ctx context.Context, target store.NotifyTarget, message Message) error
+}
+
+// Dispatcher delivers each message through the notifier for its target's type. Targets without
+// a type are delivered by email.
+type Dispatcher struct {
+	notifiers map[string]Notifier
+}
+
+// NewDispatcher creates a dispatcher for every built-in channel. Email goes through sender, the
+// others through client, which defaults to a client with a 30 second timeout when nil.
+func NewDispatcher(sender email.Sender, client *http.Client) *Dispatcher {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Dispatcher{notifiers: map[string]Notifier{
+		TypeEmail:   NewEmailNotifier(sender),
+		TypeWebhook: NewWebhookNotifier(client),
+		TypeSlack:   NewSlackNotifier(client),
+		TypeDiscord: NewDiscordNotifier(client),
+		TypeNtfy:    NewNtfyNotifier(client),
+		TypeGotify:  NewGotifyNotifier(client),
+	}}
+}
+
+// Notify delivers message through the notifier for target's type
+func (d *Dispatcher) Notify(ctx context.Context, target store.NotifyTarget, message Message) error {
+	targetType := target.Type
+	if targetType == "" {
+		targetType = TypeEmail
+	}
+	notifier, ok := d.notifiers[targetType]
+	if !ok {
+		return &PermanentError{Err: fmt.Errorf("unknown notification channel type %q", target.Type)}
+	}
+	return notifier.Notify(ctx, target, message)
+}
+
+// ValidateTarget checks that a notification target has what its type needs to deliver
+func ValidateTarget(target store.NotifyTarget) error {
+	switch target.Type {
+	case "", TypeEmail:
+		return nil
+	case TypeWebhook, TypeSlack, TypeDiscord, TypeNtfy, TypeGotify:
+	default:
+		return fmt.Errorf("unknown notification channel type %q, must be one of email, webhook, slack, discord, ntfy or gotify", target.Type)
+	}
+
+	if strings.TrimSpace(target.URL) == "" {
+		return fmt.Errorf("a URL is required for %s notifications", target.Type)
+	}
+	parsed, err := url.Parse(target.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid %s URL: must be an absolute http or https URL", target.Type)
+	}
+	if target.Type == TypeGotify && target.Token == "" {
+		return errors.New("an application token is required for gotify notifications")
+	}
+	return nil
+}
+
+// EmailNotifier delivers messages by email to the subscriber's address
+type EmailNotifier struct {
+	sender email.Sender
+}
+
+// NewEmailNotifier creates a notifier that sends through sender
+func NewEmailNotifier(sender email.Sender) *EmailNotifier {
+	return &EmailNotifier{sender: sender}
+}
+
+// Notify emails the rendered digest to message.Recipient
+func (n *EmailNotifier) Notify(ctx context.Context, target store.NotifyTarget, message Message) error {
+	if n.sender == nil {
+		return &PermanentError{Err: errors.New("email is not configured")}
+	}
+	return n.sender.Send(message.Recipient, message.Subject, message.Content)
+}
+
+// PermanentError wraps a delivery failure that retrying won't fix
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// HTTPError is returned when a notification endpoint answers with a non-2xx status
+type HTTPError struct {
+	StatusCode int
+	Body       string // Start of the response body, to help diagnose the failure
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("notification endpoint returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("notification endpoint returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsTransient reports whether a failed delivery is worth retrying. Permanent SMTP errors (5xx
+// replies such as failed authentication), email configuration errors and HTTP client errors
+// other than 408 and 429 are not.
+func IsTransient(err error) bool {
+	var permanentErr *PermanentError
+	if errors.As(err, &permanentErr) {
+		return false
+	}
+	var configErr *email.ConfigError
+	if errors.As(err, &configErr) {
+		return false
+	}
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return smtpErr.Code < 500
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == http.StatusRequestTimeout || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"sync"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+)
+
+// capturedRequest is a request received by a testServer, with its body read
+type capturedRequest struct {
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// testServer stands in for a notification endpoint, answering each request with status
+type testServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []capturedRequest
+	status   int
+}
+
+func newTestServer(t *testing.T, status int) *testServer {
+	server := &testServer{status: status}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		server.mu.Lock()
+		server.requests = append(server.requests, capturedRequest{Path: r.URL.Path, Header: r.Header.Clone(), Body: body})
+		server.mu.Unlock()
+		w.WriteHeader(server.status)
+		fmt.Fprint(w, http.StatusText(server.status))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// only returns the single request the server received
+func (s *testServer) only(t *testing.T) capturedRequest {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(s.requests))
+	}
+	return s.requests[0]
+}
+
+// testMessage is a digest of two videos from one channel and one from another
+func testMessage() Message {
+	published := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	return Message{
+		Recipient: "alice@example.com",
+		Subject:   "New videos from your channels",
+		Content:   email.Content{HTML: "<p>New videos</p>", Text: "New videos"},
+		Items: []store.DigestItem{
+			{ChannelID: "UC1", ChannelTitle: "Go Talks", VideoID: "a1", Title: "Generics <in> practice", URL: "https://www.youtube.com/watch?v=a1", Published: published},
+			{ChannelID: "UC1", ChannelTitle: "Go Talks", VideoID: "a2", Title: "Fuzzing_101", URL: "https://www.youtube.com/watch?v=a2", Published: published},
+			{ChannelID: "UC2", ChannelTitle: "Rust & Friends", VideoID: "b1", Title: "Borrowing", URL: "https://www.youtube.com/watch?v=b1", Published: published},
+		},
+	}
+}
+
+func TestDigestItems(t *testing.T) {
+	published := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	items := DigestItems([]email.ChannelDigest{{
+		ChannelID:    "UC1",
+		ChannelTitle: "Go Talks",
+		Videos: []rss.Entry{{
+			ID:        "yt:video:a1",
+			Title:     "Generics",
+			Link:      rss.Link{Href: "https://www.youtube.com/watch?v=a1"},
+			Published: published,
+			Summary:   &rss.Summary{Text: "A look at generics"},
+		}},
+	}})
+
+	expected := store.DigestItem{ChannelID: "UC1", ChannelTitle: "Go Talks", VideoID: "a1", Title: "Generics",
+		URL: "https://www.youtube.com/watch?v=a1", Published: published, Summary: "A look at generics"}
+	if len(items) != 1 || items[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, items)
+	}
+}
+
+func TestDispatcher_Notify(t *testing.T) {
+	sender := &email.MockSender{}
+	server := newTestServer(t, http.StatusOK)
+	dispatcher := NewDispatcher(sender, server.Client())
+
+	// Targets without a type go by email
+	if err := dispatcher.Notify(context.Background(), store.NotifyTarget{}, testMessage()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sender.SentEmails) != 1 || sender.SentEmails[0].Recipient != "alice@example.com" {
+		t.Errorf("Expected the digest to be emailed to alice@example.com, got %+v", sender.SentEmails)
+	}
+
+	if err := dispatcher.Notify(context.Background(), store.NotifyTarget{Type: TypeWebhook, URL: server.URL}, testMessage()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server.only(t)
+	if len(sender.SentEmails) != 1 {
+		t.Error("Expected webhook targets not to be emailed")
+	}
+
+	err := dispatcher.Notify(context.Background(), store.NotifyTarget{Type: "pager"}, testMessage())
+	if err == nil || IsTransient(err) {
+		t.Errorf("Expected a permanent error for an unknown type, got %v", err)
+	}
+}
+
+func TestEmailNotifier_NotConfigured(t *testing.T) {
+	err := NewEmailNotifier(nil).Notify(context.Background(), store.NotifyTarget{}, testMessage())
+	if err == nil || IsTransient(err) {
+		t.Errorf("Expected a permanent error without a sender, got %v", err)
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target store.NotifyTarget
+		valid  bool
+	}{
+		{"empty type is email", store.NotifyTarget{}, true},
+		{"email", store.NotifyTarget{Type: TypeEmail}, true},
+		{"webhook", store.NotifyTarget{Type: TypeWebhook, URL: "https://example.com/hook"}, true},
+		{"slack", store.NotifyTarget{Type: TypeSlack, URL: "https://hooks.slack.com/services/T/B/X"}, true},
+		{"ntfy without token", store.NotifyTarget{Type: TypeNtfy, URL: "http://ntfy.local/videos"}, true},
+		{"gotify", store.NotifyTarget{Type: TypeGotify, URL: "https://gotify.local", Token: "app-token"}, true},
+		{"unknown type", store.NotifyTarget{Type: "pager", URL: "https://example.com"}, false},
+		{"missing URL", store.NotifyTarget{Type: TypeDiscord}, false},
+		{"relative URL", store.NotifyTarget{Type: TypeWebhook, URL: "/hook"}, false},
+		{"unsupported scheme", store.NotifyTarget{Type: TypeWebhook, URL: "ftp://example.com/hook"}, false},
+		{"gotify without token", store.NotifyTarget{Type: TypeGotify, URL: "https://gotify.local"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTarget(tt.target)
+			if tt.valid && err != nil {
+				t.Errorf("Expected %+v to be valid, got %v", tt.target, err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("Expected %+v to be invalid", tt.target)
+			}
+		})
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{errors.New("dial tcp: connection refused"), true},
+		{&textproto.Error{Code: 421, Msg: "service not available"}, true},
+		{fmt.Errorf("failed to send: %w", &textproto.Error{Code: 451, Msg: "try again later"}), true},
+		{fmt.Errorf("failed to authenticate: %w", &textproto.Error{Code: 535, Msg: "authentication failed"}), false},
+		{&textproto.Error{Code: 550, Msg: "mailbox unavailable"}, false},
+		{&HTTPError{StatusCode: http.StatusBadGateway}, true},
+		{&HTTPError{StatusCode: http.StatusTooManyRequests}, true},
+		{&HTTPError{StatusCode: http.StatusRequestTimeout}, true},
+		{&HTTPError{StatusCode: http.StatusNotFound}, false},
+		{&PermanentError{Err: errors.New("bad URL")}, false},
+		{&email.ConfigError{Err: errors.New("sender address is not configured")}, false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.transient {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.transient)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"youtube-curator-v2/internal/store"
+)
+
+// ntfyMessageLimit is the largest message body ntfy shows as text rather than as an attachment
+const ntfyMessageLimit = 4096
+
+// gotifyPriority is the priority Gotify messages are sent with; 4 to 7 raise a notification
+const gotifyPriority = 5
+
+// NtfyNotifier publishes the digest to an ntfy topic
+type NtfyNotifier struct {
+	client *http.Client
+}
+
+// NewNtfyNotifier creates a notifier that publishes through client
+func NewNtfyNotifier(client *http.Client) *NtfyNotifier {
+	return &NtfyNotifier{client: client}
+}
+
+// Notify publishes the digest to the topic at target.URL, e.g. https://ntfy.sh/my-videos,
+// authenticating with target.Token when set
+func (n *NtfyNotifier) Notify(ctx context.Context, target store.NotifyTarget, message Message) error {
+	text := listing(message, ntfyMessageLimit, listingFormat{
+		heading: func(string) string { return "" }, // The subject is the notification title
+		channel: func(title string) string { return title },
+		video: func(item store.DigestItem) string {
+			return fmt.Sprintf("- %s\n  %s", item.Title, item.URL)
+		},
+	})
+
+	headers := map[string]string{
+		"Title": mime.QEncoding.Encode("utf-8", message.Subject),
+		"Tags":  "tv",
+	}
+	if target.Token != "" {
+		headers["Authorization"] = "Bearer " + target.Token
+	}
+	return Post(ctx, n.client, target.URL, "text/plain; charset=utf-8", []byte(strings.TrimSpace(text)), headers)
+}
+
+// GotifyNotifier pushes the digest to a Gotify server
+type GotifyNotifier struct {
+	client *http.Client
+}
+
+// NewGotifyNotifier creates a notifier that pushes through client
+func NewGotifyNotifier(client *http.Client) *GotifyNotifier {
+	return &GotifyNotifier{client: client}
+}
+
+// gotifyMessage is the body of Gotify's create message endpoint
+type gotifyMessage struct {
+	Title    string         `json:"title"`
+	Message  string         `json:"message"`
+	Priority int            `json:"priority"`
+	Extras   map[string]any `json:"extras"`
+}
+
+// Notify pushes the digest as markdown to the Gotify server at target.URL, using the
+// application token target.Token
+func (n *GotifyNotifier) Notify(ctx context.Context, target store.NotifyTarget, message Message) error {
+	text := listing(message, 0, listingFormat{
+		heading: func(string) string { return "" }, // The subject is the message title
+		channel: func(title string) string { return "**" + title + "**" },
+		video: func(item store.DigestItem) string {
+			return fmt.Sprintf("- [%s](%s)", item.Title, item.URL)
+		},
+	})
+	body, err := json.Marshal(gotifyMessage{
+		Title:    message.Subject,
+		Message:  strings.TrimSpace(text),
+		Priority: gotifyPriority,
+		Extras: map[string]any{
+			"client::display": map[string]string{"contentType": "text/markdown"},
+		},
+	})
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("failed to marshal gotify message: %w", err)}
+	}
+
+	url := strings.TrimRight(target.URL, "/")
+	if !strings.HasSuffix(url, "/message") {
+		url += "/message"
+	}
+	return Post(ctx, n.client, url, "application/json", body, map[string]string{"X-Gotify-Key": target.Token})
+}
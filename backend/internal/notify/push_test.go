@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"testing"
+
+	"youtube-curator-v2/internal/store"
+)
+
+func TestNtfyNotifier(t *testing.T) {
+	server := newTestServer(t, http.StatusOK)
+	target := store.NotifyTarget{Type: TypeNtfy, URL: server.URL + "/my-videos", Token: "tk_abc"}
+	message := testMessage()
+	message.Subject = "Nouvelles vidéos"
+
+	if err := NewNtfyNotifier(server.Client()).Notify(context.Background(), target, message); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := server.only(t)
+	if req.Path != "/my-videos" {
+		t.Errorf("Expected a POST to the topic, got %s", req.Path)
+	}
+	if title, err := new(mime.WordDecoder).DecodeHeader(req.Header.Get("Title")); err != nil || title != "Nouvelles vidéos" {
+		t.Errorf("Expected the subject as the title, got %q (%v)", req.Header.Get("Title"), err)
+	}
+	if req.Header.Get("Authorization") != "Bearer tk_abc" {
+		t.Errorf("Expected the access token, got %q", req.Header.Get("Authorization"))
+	}
+	body := string(req.Body)
+	if !strings.HasPrefix(body, "Go Talks\n- Generics <in> practice\n  https://www.youtube.com/watch?v=a1") {
+		t.Errorf("Expected the videos listed by channel, got %q", body)
+	}
+}
+
+func TestNtfyNotifier_WithoutToken(t *testing.T) {
+	server := newTestServer(t, http.StatusOK)
+	if err := NewNtfyNotifier(server.Client()).Notify(context.Background(), store.NotifyTarget{URL: server.URL}, testMessage()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if auth := server.only(t).Header.Get("Authorization"); auth != "" {
+		t.Errorf("Expected no Authorization header, got %q", auth)
+	}
+}
+
+func TestGotifyNotifier(t *testing.T) {
+	for _, suffix := range []string{"", "/", "/message"} {
+		server := newTestServer(t, http.StatusOK)
+		target := store.NotifyTarget{Type: TypeGotify, URL: server.URL + suffix, Token: "app-token"}
+		if err := NewGotifyNotifier(server.Client()).Notify(context.Background(), target, testMessage()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		req := server.only(t)
+		if req.Path != "/message" {
+			t.Errorf("Expected a POST to /message for URL suffix %q, got %s", suffix, req.Path)
+		}
+		if req.Header.Get("X-Gotify-Key") != "app-token" {
+			t.Errorf("Expected the application token, got %q", req.Header.Get("X-Gotify-Key"))
+		}
+
+		var body gotifyMessage
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Title != "New videos from your channels" || body.Priority != gotifyPriority {
+			t.Errorf("Unexpected message: %+v", body)
+		}
+		if !strings.HasPrefix(body.Message, "**Go Talks**\n- [Generics <in> practice](https://www.youtube.com/watch?v=a1)") {
+			t.Errorf("Expected a markdown listing, got %q", body.Message)
+		}
+	}
+}
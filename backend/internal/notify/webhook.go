@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"youtube-curator-v2/internal/store"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of a webhook body, as "sha256=<hex>"
+	SignatureHeader = "X-Curator-Signature"
+	// EventHeader names the event a webhook body describes
+	EventHeader = "X-Curator-Event"
+
+	// EventNewsletterDigest is the event sent to generic webhook subscribers
+	EventNewsletterDigest = "newsletter.digest"
+)
+
+// maxErrorBody caps how much of an error response is kept in HTTPError
+const maxErrorBody = 512
+
+// WebhookPayload is the JSON body posted to generic webhooks
+type WebhookPayload struct {
+	Event     string             `json:"event"`
+	Recipient string             `json:"recipient"`
+	Subject   string             `json:"subject"`
+	Text      string             `json:"text"`
+	HTML      string             `json:"html"`
+	Videos    []store.DigestItem `json:"videos"`
+	SentAt    time.Time          `json:"sentAt"`
+}
+
+// WebhookNotifier posts the digest as JSON, signed with the target's secret when it has one
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that posts through client
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{client: client}
+}
+
+// Notify posts the digest to target.URL
+func (n *WebhookNotifier) Notify(ctx context.Context, target store.NotifyTarget, message Message) error {
+	videos := message.Items
+	if videos == nil {
+		videos = []store.DigestItem{}
+	}
+	body, err := json.Marshal(WebhookPayload{
+		Event:     EventNewsletterDigest,
+		Recipient: message.Recipient,
+		Subject:   message.Subject,
+		Text:      message.Content.Text,
+		HTML:      message.Content.HTML,
+		Videos:    videos,
+		SentAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("failed to marshal webhook payload: %w", err)}
+	}
+
+	headers := map[string]string{EventHeader: EventNewsletterDigest}
+	if target.Secret != "" {
+		headers[SignatureHeader] = Sign(target.Secret, body)
+	}
+	return Post(ctx, n.client, target.URL, "application/json", body, headers)
+}
+
+// Sign returns the signature of body under secret, as sent in SignatureHeader. Receivers should
+// compute it over the raw request body and compare in constant time.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Post sends body to url, returning an HTTPError for non-2xx responses. Requests that can't be built
+// fail with a PermanentError.
+func Post(ctx context.Context, client *http.Client, url, contentType string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("failed to create notification request: %w", err)}
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "youtube-curator")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &HTTPError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(snippet))}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body) // Drain so the connection can be reused
+	return nil
+}
@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"youtube-curator-v2/internal/store"
+)
+
+func TestWebhookNotifier_SignsPayload(t *testing.T) {
+	server := newTestServer(t, http.StatusNoContent)
+	target := store.NotifyTarget{Type: TypeWebhook, URL: server.URL + "/hooks/videos", Secret: "s3cret"}
+
+	if err := NewWebhookNotifier(server.Client()).Notify(context.Background(), target, testMessage()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := server.only(t)
+	if req.Path != "/hooks/videos" {
+		t.Errorf("Expected a POST to /hooks/videos, got %s", req.Path)
+	}
+	if got, want := req.Header.Get(SignatureHeader), Sign("s3cret", req.Body); got != want {
+		t.Errorf("Expected signature %s, got %s", want, got)
+	}
+	if req.Header.Get(EventHeader) != EventNewsletterDigest {
+		t.Errorf("Expected event %s, got %s", EventNewsletterDigest, req.Header.Get(EventHeader))
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		t.Fatalf("Expected a JSON body: %v", err)
+	}
+	if payload.Recipient != "alice@example.com" || payload.Text != "New videos" || len(payload.Videos) != 3 {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+	if payload.Videos[2].VideoID != "b1" || payload.Videos[2].ChannelTitle != "Rust & Friends" {
+		t.Errorf("Expected the videos in digest order, got %+v", payload.Videos)
+	}
+}
+
+func TestWebhookNotifier_Unsigned(t *testing.T) {
+	server := newTestServer(t, http.StatusOK)
+	message := testMessage()
+	message.Items = nil
+
+	if err := NewWebhookNotifier(server.Client()).Notify(context.Background(), store.NotifyTarget{URL: server.URL}, message); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	req := server.only(t)
+	if req.Header.Get(SignatureHeader) != "" {
+		t.Error("Expected no signature without a secret")
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if videos, ok := payload["videos"].([]any); !ok || len(videos) != 0 {
+		t.Errorf("Expected an empty videos array, got %v", payload["videos"])
+	}
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	tests := []struct {
+		status    int
+		transient bool
+	}{
+		{http.StatusInternalServerError, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusGone, false},
+	}
+	for _, tt := range tests {
+		server := newTestServer(t, tt.status)
+		err := NewWebhookNotifier(server.Client()).Notify(context.Background(), store.NotifyTarget{URL: server.URL}, testMessage())
+
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.StatusCode != tt.status {
+			t.Fatalf("Expected an HTTPError with status %d, got %v", tt.status, err)
+		}
+		if httpErr.Body != http.StatusText(tt.status) {
+			t.Errorf("Expected the response body in the error, got %q", httpErr.Body)
+		}
+		if IsTransient(err) != tt.transient {
+			t.Errorf("Expected status %d to be transient=%v", tt.status, tt.transient)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"event":"ping"}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=4f4bb3a54e99c4a20e243485229f9b08c66e09104ba6f79c23ce647242a4ce84"
+	if got := Sign("secret", []byte(`{"event":"ping"}`)); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
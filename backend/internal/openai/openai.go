@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	"github.com/openai/openai-go/packages/param"
+	"github.com/openai/openai-go/packages/ssestream"
 )
 
 // SchemaParameters contains the schema-related parameters for chat completion
@@ -56,6 +60,26 @@ type OpenAIClient interface {
 	GetModelName() string
 }
 
+// StreamingOpenAIClient extends OpenAIClient with token streaming
+type StreamingOpenAIClient interface {
+	OpenAIClient
+
+	// ChatCompletionStream performs a chat completion request and streams the response
+	// Takes the same parameters as ChatCompletion, but each value sent on deltas is a
+	// piece of the response as it is generated. A failure is sent as a final value with
+	// Err set. The deltas channel is closed once the response is complete.
+	ChatCompletionStream(
+		ctx context.Context,
+		systemPrompt string,
+		userPrompts []string,
+		imageURLs []string,
+		schemaParams *SchemaParameters,
+		temperature float64,
+		maxTokens int,
+		deltas chan customerrors.ErrorString,
+	)
+}
+
 // DefaultOpenAIRetryConfig provides sensible default values for OpenAI retry behavior
 var DefaultOpenAIRetryConfig = retry.RetryConfig{
 	MaxRetries:      5,
@@ -65,6 +89,9 @@ var DefaultOpenAIRetryConfig = retry.RetryConfig{
 	MaxTotalTimeout: 30 * time.Minute, // LLM calls can take a while
 }
 
+// ErrEmptyResponse is returned when the API responds without any content
+var ErrEmptyResponse = errors.New("empty response from llm")
+
 type Client struct {
 	client *openai.Client
 	model  string
@@ -96,85 +123,83 @@ func isModelLoadingError(err error) bool {
 		strings.Contains(errStr, "Model does not exist")
 }
 
-// ChatCompletion sends a request to the OpenAI API with the given prompts, optional images, and schema
-func (c *Client) ChatCompletion(
-	ctx context.Context,
-	systemPrompt string,
-	userPrompts []string,
-	imageURLs []string,
-	schemaParams *SchemaParameters,
-	temperature float64,
-	maxTokens int,
-	results chan customerrors.ErrorString,
-) {
-	// Prepare messages array
-	messages := []openai.ChatCompletionMessageParamUnion{
-		openai.SystemMessage(systemPrompt),
+// wrapAPIError describes a failed API call, keeping the underlying error available to errors.As
+func wrapAPIError(err error) error {
+	if isModelLoadingError(err) {
+		return fmt.Errorf("model failed to load after retries: %w", err)
 	}
+	return fmt.Errorf("error during API call: %w", err)
+}
 
-	// If we have image URLs, create a message with multi-modal content
-	if len(imageURLs) > 0 {
-		// Build image content parts
-		contentParts := []openai.ChatCompletionContentPartUnionParam{}
-
-		// First, add a text part if we have userPrompts
-		if len(userPrompts) > 0 {
-			textPart := openai.TextContentPart(userPrompts[0]) // First prompt as the text part
-			contentParts = append(contentParts, textPart)
-		}
-
-		// Then add all the image parts
-		for _, imgURL := range imageURLs {
-			if imgURL != "" { // Basic validation
-				imageParam := openai.ChatCompletionContentPartImageImageURLParam{
-					URL: imgURL,
-					// Optional: Detail: openai.String("auto"), // Can be "low", "high", or "auto"
-				}
-				imagePart := openai.ImageContentPart(imageParam)
-				contentParts = append(contentParts, imagePart)
-			}
-		}
-
-		// Create a user message with the multi-modal content parts
-		messages = append(messages, openai.UserMessage(contentParts))
+// Error categories reported by ErrorCategory
+const (
+	ErrorCategoryTimeout        = "timeout"
+	ErrorCategoryConnection     = "connection"
+	ErrorCategoryAuthentication = "authentication"
+	ErrorCategoryModelNotFound  = "model_not_found"
+	ErrorCategoryRateLimited    = "rate_limited"
+	ErrorCategoryBadRequest     = "bad_request"
+	ErrorCategoryServer         = "server_error"
+	ErrorCategoryEmptyResponse  = "empty_response"
+	ErrorCategoryUnknown        = "unknown"
+)
 
-		// If there are additional prompts (beyond the first one), add them separately
-		if len(userPrompts) > 1 {
-			// Join the remaining prompts and add as a separate message
-			messages = append(messages, openai.UserMessage(strings.Join(userPrompts[1:], "\n")))
-		}
-	} else {
-		// No images, just add text prompts as usual
-		messages = append(messages, openai.UserMessage(strings.Join(userPrompts, "\n")))
+// ErrorCategory classifies an error returned by ChatCompletion or ChatCompletionStream,
+// so callers can tell configuration mistakes from transient failures. It returns "" for nil.
+func ErrorCategory(err error) string {
+	if err == nil {
+		return ""
 	}
-
-	currentTemperature := 1.0
-	if temperature != 0.0 {
-		currentTemperature = temperature
+	if errors.Is(err, ErrEmptyResponse) {
+		return ErrorCategoryEmptyResponse
 	}
-
-	params := openai.ChatCompletionNewParams{
-		Model:       c.model,
-		Messages:    messages,
-		Temperature: param.NewOpt(currentTemperature),
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCategoryTimeout
 	}
 
-	// Add max tokens parameter if it's greater than 0
-	if maxTokens > 0 {
-		params.MaxTokens = openai.Int(int64(maxTokens))
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden:
+			return ErrorCategoryAuthentication
+		case apiErr.StatusCode == http.StatusNotFound:
+			return ErrorCategoryModelNotFound
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return ErrorCategoryRateLimited
+		case apiErr.StatusCode >= 500:
+			return ErrorCategoryServer
+		case apiErr.StatusCode >= 400:
+			return ErrorCategoryBadRequest
+		}
+		return ErrorCategoryUnknown
 	}
 
-	if schemaParams != nil {
-		schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
-			Name:        schemaParams.Name,
-			Description: openai.String(schemaParams.Description),
-			Schema:      schemaParams.Schema,
-			Strict:      openai.Bool(true),
-		}
-		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
-			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{JSONSchema: schemaParam},
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorCategoryTimeout
 		}
+		return ErrorCategoryConnection
 	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return ErrorCategoryConnection
+	}
+	return ErrorCategoryUnknown
+}
+
+// ChatCompletion sends a request to the OpenAI API with the given prompts, optional images, and schema
+func (c *Client) ChatCompletion(
+	ctx context.Context,
+	systemPrompt string,
+	userPrompts []string,
+	imageURLs []string,
+	schemaParams *SchemaParameters,
+	temperature float64,
+	maxTokens int,
+	results chan customerrors.ErrorString,
+) {
+	params := buildChatParams(c.model, systemPrompt, userPrompts, imageURLs, schemaParams, temperature, maxTokens)
 
 	shouldRetry := func(err error) bool {
 		return isModelLoadingError(err)
@@ -187,16 +212,9 @@ func (c *Client) ChatCompletion(
 	resp, err := retry.RetryWithBackoff(ctx, c.retry, ChatCompletionFn, shouldRetry)
 
 	if err != nil {
-		var errMsg string
-		if isModelLoadingError(err) {
-			errMsg = fmt.Sprintf("model failed to load after retries: %v", err)
-		} else {
-			errMsg = fmt.Sprintf("error during API call: %v", err)
-		}
-
 		results <- customerrors.ErrorString{
 			Value: "",
-			Err:   errors.New(errMsg),
+			Err:   wrapAPIError(err),
 		}
 		return
 	}
@@ -204,7 +222,7 @@ func (c *Client) ChatCompletion(
 	if len(resp.Choices) == 0 {
 		results <- customerrors.ErrorString{
 			Value: "",
-			Err:   fmt.Errorf("empty response from llm"),
+			Err:   ErrEmptyResponse,
 		}
 		return
 	}
@@ -215,6 +233,74 @@ func (c *Client) ChatCompletion(
 	}
 }
 
+// ChatCompletionStream sends a request to the OpenAI API and streams the response deltas
+func (c *Client) ChatCompletionStream(
+	ctx context.Context,
+	systemPrompt string,
+	userPrompts []string,
+	imageURLs []string,
+	schemaParams *SchemaParameters,
+	temperature float64,
+	maxTokens int,
+	deltas chan customerrors.ErrorString,
+) {
+	defer close(deltas)
+
+	params := buildChatParams(c.model, systemPrompt, userPrompts, imageURLs, schemaParams, temperature, maxTokens)
+
+	shouldRetry := func(err error) bool {
+		return isModelLoadingError(err)
+	}
+
+	// Only opening the stream is retried; once deltas have been delivered a retry would duplicate output
+	openStreamFn := func(ctx context.Context) (*ssestream.Stream[openai.ChatCompletionChunk], error) {
+		stream := c.client.Chat.Completions.NewStreaming(ctx, params)
+		if err := stream.Err(); err != nil {
+			stream.Close()
+			return nil, err
+		}
+		return stream, nil
+	}
+
+	stream, err := retry.RetryWithBackoff(ctx, c.retry, openStreamFn, shouldRetry)
+	if err != nil {
+		sendDelta(ctx, deltas, customerrors.ErrorString{Err: wrapAPIError(err)})
+		return
+	}
+	defer stream.Close()
+
+	received := false
+	for stream.Next() {
+		chunk := stream.Current()
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		received = true
+		if !sendDelta(ctx, deltas, customerrors.ErrorString{Value: chunk.Choices[0].Delta.Content}) {
+			return
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		sendDelta(ctx, deltas, customerrors.ErrorString{Err: fmt.Errorf("error during API stream: %w", err)})
+		return
+	}
+
+	if !received {
+		sendDelta(ctx, deltas, customerrors.ErrorString{Err: ErrEmptyResponse})
+	}
+}
+
+// sendDelta delivers a streamed value unless ctx is cancelled, reporting whether it was sent
+func sendDelta(ctx context.Context, deltas chan customerrors.ErrorString, delta customerrors.ErrorString) bool {
+	select {
+	case deltas <- delta:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // PreprocessYAML extracts YAML content from the API response
 func (c *Client) PreprocessYAML(response string) string {
 	return preprocess(response, "yaml")
@@ -297,3 +383,85 @@ func preprocess(response, format string) string {
 func (c *Client) SetRetryConfig(config retry.RetryConfig) {
 	c.retry = config
 }
+
+// buildChatParams prepares the request parameters shared by ChatCompletion and ChatCompletionStream
+func buildChatParams(
+	model string,
+	systemPrompt string,
+	userPrompts []string,
+	imageURLs []string,
+	schemaParams *SchemaParameters,
+	temperature float64,
+	maxTokens int,
+) openai.ChatCompletionNewParams {
+	// Prepare messages array
+	messages := []openai.ChatCompletionMessageParamUnion{
+		openai.SystemMessage(systemPrompt),
+	}
+
+	// If we have image URLs, create a message with multi-modal content
+	if len(imageURLs) > 0 {
+		// Build image content parts
+		contentParts := []openai.ChatCompletionContentPartUnionParam{}
+
+		// First, add a text part if we have userPrompts
+		if len(userPrompts) > 0 {
+			textPart := openai.TextContentPart(userPrompts[0]) // First prompt as the text part
+			contentParts = append(contentParts, textPart)
+		}
+
+		// Then add all the image parts
+		for _, imgURL := range imageURLs {
+			if imgURL != "" { // Basic validation
+				imageParam := openai.ChatCompletionContentPartImageImageURLParam{
+					URL: imgURL,
+					// Optional: Detail: openai.String("auto"), // Can be "low", "high", or "auto"
+				}
+				imagePart := openai.ImageContentPart(imageParam)
+				contentParts = append(contentParts, imagePart)
+			}
+		}
+
+		// Create a user message with the multi-modal content parts
+		messages = append(messages, openai.UserMessage(contentParts))
+
+		// If there are additional prompts (beyond the first one), add them separately
+		if len(userPrompts) > 1 {
+			// Join the remaining prompts and add as a separate message
+			messages = append(messages, openai.UserMessage(strings.Join(userPrompts[1:], "\n")))
+		}
+	} else {
+		// No images, just add text prompts as usual
+		messages = append(messages, openai.UserMessage(strings.Join(userPrompts, "\n")))
+	}
+
+	currentTemperature := 1.0
+	if temperature != 0.0 {
+		currentTemperature = temperature
+	}
+
+	params := openai.ChatCompletionNewParams{
+		Model:       model,
+		Messages:    messages,
+		Temperature: param.NewOpt(currentTemperature),
+	}
+
+	// Add max tokens parameter if it's greater than 0
+	if maxTokens > 0 {
+		params.MaxTokens = openai.Int(int64(maxTokens))
+	}
+
+	if schemaParams != nil {
+		schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
+			Name:        schemaParams.Name,
+			Description: openai.String(schemaParams.Description),
+			Schema:      schemaParams.Schema,
+			Strict:      openai.Bool(true),
+		}
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{JSONSchema: schemaParam},
+		}
+	}
+
+	return params
+}
@@ -1,6 +1,13 @@
 package openai
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/openai/openai-go"
+)
 
 func TestPreprocessJSON(t *testing.T) {
 	client := &Client{}
@@ -186,3 +193,31 @@ func TestPreprocessYAML(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "no error", err: nil, expected: ""},
+		{name: "empty response", err: ErrEmptyResponse, expected: ErrorCategoryEmptyResponse},
+		{name: "deadline", err: wrapAPIError(context.DeadlineExceeded), expected: ErrorCategoryTimeout},
+		{name: "unauthorized", err: &openai.Error{StatusCode: 401}, expected: ErrorCategoryAuthentication},
+		{name: "unknown model", err: &openai.Error{StatusCode: 404}, expected: ErrorCategoryModelNotFound},
+		{name: "rate limited", err: &openai.Error{StatusCode: 429}, expected: ErrorCategoryRateLimited},
+		{name: "bad request", err: &openai.Error{StatusCode: 400}, expected: ErrorCategoryBadRequest},
+		{name: "server error", err: &openai.Error{StatusCode: 503}, expected: ErrorCategoryServer},
+		{name: "connection refused", err: wrapAPIError(&url.Error{Op: "Post", URL: "http://localhost:1", Err: errors.New("connection refused")}), expected: ErrorCategoryConnection},
+		{name: "other", err: errors.New("something else"), expected: ErrorCategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// API errors are built without a request or response, so they can't be printed
+			if got := ErrorCategory(tt.err); got != tt.expected {
+				t.Errorf("ErrorCategory() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package opml reads and writes OPML subscription lists, the format feed readers use to move
+// subscriptions from one to another.
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ContentType is the content type OPML documents are served with
+const ContentType = "text/x-opml; charset=utf-8"
+
+// Document is an OPML document
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+// Head holds the document's metadata
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"` // RFC 822 date
+}
+
+// Body holds the document's outlines
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is a subscription, or a folder of nested outlines
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`  // Feed URL
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"` // Web page of the feed
+	Outlines []Outline `xml:"outline"`
+}
+
+// Name returns the outline's title, falling back to its text
+func (o Outline) Name() string {
+	if title := strings.TrimSpace(o.Title); title != "" {
+		return title
+	}
+	return strings.TrimSpace(o.Text)
+}
+
+// New creates an OPML 2.0 document holding outlines, created now
+func New(title string, outlines []Outline) *Document {
+	return &Document{
+		Version: "2.0",
+		Head:    Head{Title: title, DateCreated: time.Now().UTC().Format(time.RFC1123Z)},
+		Body:    Body{Outlines: outlines},
+	}
+}
+
+// Parse reads an OPML document
+func Parse(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OPML: %w", err)
+	}
+	return &doc, nil
+}
+
+// Feeds returns the outlines that carry a feed URL, in document order, looking inside folders
+func (d *Document) Feeds() []Outline {
+	var feeds []Outline
+	var walk func(outlines []Outline)
+	walk = func(outlines []Outline) {
+		for _, outline := range outlines {
+			if strings.TrimSpace(outline.XMLURL) != "" {
+				feeds = append(feeds, outline)
+			}
+			walk(outline.Outlines)
+		}
+	}
+	walk(d.Body.Outlines)
+	return feeds
+}
+
+// Marshal renders the document with an XML header
+func (d *Document) Marshal() ([]byte, error) {
+	body, err := xml.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OPML: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
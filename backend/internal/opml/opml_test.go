@@ -0,0 +1,70 @@
+package opml
+
+import (
+	"strings"
+	"testing"
+)
+
+const readerExport = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head><title>Reader subscriptions</title></head>
+  <body>
+    <outline text="YouTube" title="YouTube">
+      <outline text="Go Time" title="Go Time" type="rss" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UCAYF6ZY9gWBR1GW3R7PX7yw" htmlUrl="https://www.youtube.com/channel/UCAYF6ZY9gWBR1GW3R7PX7yw"/>
+      <outline text="  Untitled  " xmlUrl=" https://www.youtube.com/feeds/videos.xml?channel_id=UCrAhw9Z8NI6GzO2WnvhYzCg "/>
+    </outline>
+    <outline text="Blog" type="rss" xmlUrl="https://blog.example.com/feed.xml"/>
+    <outline text="Empty folder"/>
+  </body>
+</opml>`
+
+func TestParse_Feeds(t *testing.T) {
+	doc, err := Parse(strings.NewReader(readerExport))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if doc.Head.Title != "Reader subscriptions" {
+		t.Errorf("Expected the head title, got %q", doc.Head.Title)
+	}
+
+	feeds := doc.Feeds()
+	if len(feeds) != 3 {
+		t.Fatalf("Expected 3 feeds, including those in folders, got %+v", feeds)
+	}
+	if feeds[0].Name() != "Go Time" || feeds[1].Name() != "Untitled" || feeds[2].Name() != "Blog" {
+		t.Errorf("Unexpected feeds in document order: %+v", feeds)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, input := range []string{"", "not xml", `<rss version="2.0"><channel/></rss>`} {
+		if _, err := Parse(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected an error parsing %q", input)
+		}
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	doc := New("Exported", []Outline{
+		{Text: "Fish & Chips", Title: "Fish & Chips", Type: "rss", XMLURL: "https://www.youtube.com/feeds/videos.xml?channel_id=UCAYF6ZY9gWBR1GW3R7PX7yw"},
+	})
+	body, err := doc.Marshal()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(string(body), `<?xml version="1.0" encoding="UTF-8"?>`) || !strings.Contains(string(body), `<opml version="2.0">`) {
+		t.Errorf("Expected an OPML 2.0 document, got %s", body)
+	}
+	if doc.Head.DateCreated == "" {
+		t.Error("Expected a creation date")
+	}
+
+	parsed, err := Parse(strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatalf("Failed to parse the exported document: %v", err)
+	}
+	feeds := parsed.Feeds()
+	if len(feeds) != 1 || feeds[0].Name() != "Fish & Chips" || feeds[0].XMLURL != doc.Body.Outlines[0].XMLURL {
+		t.Errorf("Expected the outline to survive a round trip, got %+v", feeds)
+	}
+}
@@ -4,34 +4,53 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"youtube-curator-v2/internal/rss"
 	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/webhooks"
 	"youtube-curator-v2/internal/ytdlp"
 )
 
 // ChannelResult represents the result of processing a single channel
 type ChannelResult struct {
 	ChannelID string
-	NewVideo  *rss.Entry // nil if no new video found
+	NewVideo  *rss.Entry  // Latest new video; nil if no new video found
+	NewVideos []rss.Entry // Every new video since the last check, newest first
 	Error     error
 }
 
 // ChannelProcessor defines the interface for processing YouTube channels
 type ChannelProcessor interface {
-	// ProcessChannel processes a single channel and returns the latest new video (if any)
+	// ProcessChannel processes a single channel and returns the videos published since the last check
 	ProcessChannel(ctx context.Context, channelID string) ChannelResult
 	// ProcessChannelWithOptions processes a single channel with additional options
 	ProcessChannelWithOptions(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) ChannelResult
+	// CollectChannel returns the same videos as ProcessChannelWithOptions and adds them to the
+	// catalogue, but leaves the last checked timestamp for the caller to advance
+	CollectChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) ChannelResult
+	// PreviewChannel returns the same videos as ProcessChannelWithOptions without any side effects:
+	// the catalogue and the last checked timestamp are left alone and no events are published
+	PreviewChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) ChannelResult
 }
 
+// processMode controls the side effects of processing a channel
+type processMode int
+
+const (
+	modeProcess processMode = iota // Catalogue the videos and advance the last checked timestamp
+	modeCollect                    // Catalogue the videos only
+	modePreview                    // Neither
+)
+
 // DefaultChannelProcessor implements the ChannelProcessor interface
 type DefaultChannelProcessor struct {
 	db           store.Store
 	feedProvider rss.FeedProvider
 	videoStore   *store.VideoStore
 	enricher     ytdlp.Enricher
+	publisher    webhooks.Publisher // Announces videos new to the catalogue; optional
 }
 
 // NewDefaultChannelProcessor creates a new instance of DefaultChannelProcessor
@@ -44,6 +63,11 @@ func NewDefaultChannelProcessor(db store.Store, feedProvider rss.FeedProvider, v
 	}
 }
 
+// SetPublisher sets where video.discovered events are published
+func (p *DefaultChannelProcessor) SetPublisher(publisher webhooks.Publisher) {
+	p.publisher = publisher
+}
+
 // ProcessChannel implements ChannelProcessor.ProcessChannel
 func (p *DefaultChannelProcessor) ProcessChannel(ctx context.Context, channelID string) ChannelResult {
 	return p.ProcessChannelWithOptions(ctx, channelID, false, 0)
@@ -51,6 +75,23 @@ func (p *DefaultChannelProcessor) ProcessChannel(ctx context.Context, channelID
 
 // ProcessChannelWithOptions implements ChannelProcessor.ProcessChannelWithOptions
 func (p *DefaultChannelProcessor) ProcessChannelWithOptions(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) ChannelResult {
+	return p.processChannel(ctx, channelID, ignoreLastChecked, maxItems, modeProcess)
+}
+
+// CollectChannel implements ChannelProcessor.CollectChannel
+func (p *DefaultChannelProcessor) CollectChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) ChannelResult {
+	return p.processChannel(ctx, channelID, ignoreLastChecked, maxItems, modeCollect)
+}
+
+// PreviewChannel implements ChannelProcessor.PreviewChannel
+func (p *DefaultChannelProcessor) PreviewChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int) ChannelResult {
+	return p.processChannel(ctx, channelID, ignoreLastChecked, maxItems, modePreview)
+}
+
+// processChannel fetches the channel's feed and collects the videos published since the last
+// check. The videos are catalogued unless mode is modePreview, and the last checked timestamp
+// is only advanced in modeProcess when ignoreLastChecked isn't set.
+func (p *DefaultChannelProcessor) processChannel(ctx context.Context, channelID string, ignoreLastChecked bool, maxItems int, mode processMode) ChannelResult {
 	fmt.Printf("\nFetching RSS feed for channel ID: %s\n", channelID)
 
 	feed, err := p.feedProvider.FetchFeed(ctx, channelID)
@@ -68,6 +109,9 @@ func (p *DefaultChannelProcessor) ProcessChannelWithOptions(ctx context.Context,
 		// If there's an error getting the timestamp, treat it as if no previous check occurred.
 		lastCheckedTimestamp = time.Time{}
 	}
+	// Videos are only announced as discovered when they are new to the catalogue and published
+	// since the last check, so videos pruned from the catalogue aren't announced again
+	discoveredAfter := lastCheckedTimestamp
 
 	// If ignoreLastChecked is true, treat as if no previous check occurred
 	if ignoreLastChecked {
@@ -75,34 +119,47 @@ func (p *DefaultChannelProcessor) ProcessChannelWithOptions(ctx context.Context,
 		lastCheckedTimestamp = time.Time{}
 	}
 
-	var latestVideoThisChannel *rss.Entry          // Pointer to keep track of the latest new video for the current channel
+	var newVideos []rss.Entry                      // Every video published since the last check
 	latestTimestampThisRun := lastCheckedTimestamp // Keep track of the latest timestamp for DB update
 	processedCount := 0
 
+	// Tags come from yt-dlp, which is slow, so new videos are only enriched while a subscriber filters on tags
+	var tagsChecked, enrichNew bool
+	wantsTags := func() bool {
+		if !tagsChecked {
+			enrichNew = p.subscribersFilterOnTags()
+			tagsChecked = true
+		}
+		return enrichNew
+	}
+
 	for _, entry := range feed.Entries {
 		entryCopy := entry // Make a copy to avoid pointer issues
+		isNew := entryCopy.Published.After(lastCheckedTimestamp)
+
+		// Enrich new videos with yt-dlp data before they are stored, so the catalogue keeps the tags too
+		if isNew && p.enricher != nil && wantsTags() {
+			if err := p.enricher.EnrichEntry(ctx, &entryCopy); err != nil {
+				log.Printf("Warning: Failed to enrich video %s with yt-dlp: %v", entryCopy.ID, err)
+				// Continue with RSS data only
+			}
+		}
 
-		// Store all videos in the video store (not just new ones)
-		if p.videoStore != nil {
+		// Store all videos in the video store (not just new ones), unless this is only a preview
+		if p.videoStore != nil && mode != modePreview {
+			discovered := p.publisher != nil && entryCopy.Published.After(discoveredAfter) && !p.videoStore.HasVideo(entryCopy.ID)
 			if err := p.videoStore.AddVideo(channelID, entryCopy); err != nil {
 				log.Printf("Warning: Failed to add video %s to store: %v", entryCopy.ID, err)
 				// Continue processing other videos despite this error
+			} else if discovered {
+				p.publisher.Publish(webhooks.EventVideoDiscovered, webhooks.NewVideoData(channelID, entryCopy))
 			}
 		}
 
 		// Check if the video is newer than the last checked timestamp
-		if entryCopy.Published.After(lastCheckedTimestamp) {
-			// Enrich new videos with yt-dlp data
-			// if err := p.enricher.EnrichEntry(ctx, &entryCopy); err != nil {
-			// 	log.Printf("Warning: Failed to enrich video %s with yt-dlp: %v", entryCopy.ID, err)
-			// 	// Continue with RSS data only
-			// }
-
-			// If this is the first new video found for this channel, or it's newer than the current latest
-			if latestVideoThisChannel == nil || entryCopy.Published.After(latestVideoThisChannel.Published) {
-				latestVideoThisChannel = &entryCopy
-			}
-			// Always update latest timestamp for DB, regardless of whether it's the video we email
+		if isNew {
+			newVideos = append(newVideos, entryCopy)
+			// Track the latest timestamp for the DB update
 			if entryCopy.Published.After(latestTimestampThisRun) {
 				latestTimestampThisRun = entryCopy.Published
 			}
@@ -116,14 +173,22 @@ func (p *DefaultChannelProcessor) ProcessChannelWithOptions(ctx context.Context,
 		}
 	}
 
-	// If a new video was found for this channel, log it
-	if latestVideoThisChannel != nil {
-		fmt.Printf("Found 1 new video to potentially email from channel ID %s (latest: %s)\n", channelID, latestVideoThisChannel.Title)
+	// Order new videos newest first, so the latest one leads the channel's digest
+	sort.SliceStable(newVideos, func(i, j int) bool {
+		return newVideos[i].Published.After(newVideos[j].Published)
+	})
+
+	var latestVideoThisChannel *rss.Entry
+	if len(newVideos) > 0 {
+		latestVideoThisChannel = &newVideos[0]
+		fmt.Printf("Found %d new video(s) to potentially email from channel ID %s (latest: %s)\n", len(newVideos), channelID, latestVideoThisChannel.Title)
 	}
 
 	// Always update the last checked timestamp for the channel in the database
 	// unless we're ignoring the last checked timestamp (debug mode)
-	if !ignoreLastChecked && !latestTimestampThisRun.Equal(lastCheckedTimestamp) {
+	if mode != modeProcess {
+		fmt.Printf("Skipping timestamp update for channel ID %s (left to the caller)\n", channelID)
+	} else if !ignoreLastChecked && !latestTimestampThisRun.Equal(lastCheckedTimestamp) {
 		if err := p.db.SetLastCheckedTimestamp(channelID, latestTimestampThisRun); err != nil {
 			log.Printf("Error setting last checked timestamp for channel ID %s: %v\n", channelID, err)
 		}
@@ -137,6 +202,22 @@ func (p *DefaultChannelProcessor) ProcessChannelWithOptions(ctx context.Context,
 	return ChannelResult{
 		ChannelID: channelID,
 		NewVideo:  latestVideoThisChannel,
+		NewVideos: newVideos,
 		Error:     nil,
 	}
 }
+
+// subscribersFilterOnTags reports whether any enabled subscriber picks videos by tag
+func (p *DefaultChannelProcessor) subscribersFilterOnTags() bool {
+	subscribers, err := p.db.GetSubscribers()
+	if err != nil {
+		log.Printf("Warning: Failed to get subscribers, skipping video tags: %v", err)
+		return false
+	}
+	for _, subscriber := range subscribers {
+		if subscriber.Enabled && len(subscriber.Tags) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -8,6 +8,7 @@ import (
 
 	"youtube-curator-v2/internal/rss"
 	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/webhooks"
 	"youtube-curator-v2/internal/ytdlp"
 
 	"go.uber.org/mock/gomock"
@@ -43,6 +44,7 @@ func TestProcessChannel_NoNewVideos(t *testing.T) {
 	mockFeedProvider := NewMockFeedProvider()
 	videoStore := store.NewVideoStore(1 * time.Hour)
 	processor := NewDefaultChannelProcessor(mockStore, mockFeedProvider, videoStore)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil).AnyTimes() // No tag filters, so yt-dlp isn't run
 
 	channelID := "test-channel-1"
 	lastChecked := time.Now().Add(-24 * time.Hour)
@@ -80,6 +82,51 @@ func TestProcessChannel_NoNewVideos(t *testing.T) {
 	}
 }
 
+// recordingPublisher records the events published to it
+type recordingPublisher struct {
+	events []string
+	data   []any
+}
+
+func (p *recordingPublisher) Publish(event string, data any) {
+	p.events = append(p.events, event)
+	p.data = append(p.data, data)
+}
+
+func TestProcessChannel_PublishesDiscoveredVideos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	mockFeedProvider := NewMockFeedProvider()
+	videoStore := store.NewVideoStore(1 * time.Hour)
+	processor := NewDefaultChannelProcessor(mockStore, mockFeedProvider, videoStore)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil).AnyTimes() // No tag filters, so yt-dlp isn't run
+	publisher := &recordingPublisher{}
+	processor.SetPublisher(publisher)
+
+	channelID := "test-channel-discovered"
+	lastChecked := time.Now().Add(-24 * time.Hour)
+	mockFeedProvider.feeds[channelID] = &rss.Feed{
+		Entries: []rss.Entry{
+			{Title: "New Video", Published: time.Now().Add(-time.Hour), ID: "yt:video:new-id", Link: rss.Link{Href: "https://www.youtube.com/watch?v=new-id"}},
+			{Title: "Old Video", Published: lastChecked.Add(-time.Hour), ID: "yt:video:old-id"},
+		},
+	}
+	mockStore.EXPECT().GetLastCheckedTimestamp(channelID).Return(lastChecked, nil).Times(2)
+
+	// Collecting discovers videos too, but only once
+	processor.CollectChannel(context.Background(), channelID, false, 0)
+	processor.CollectChannel(context.Background(), channelID, false, 0)
+
+	if len(publisher.events) != 1 || publisher.events[0] != webhooks.EventVideoDiscovered {
+		t.Fatalf("Expected one video.discovered event, got %v", publisher.events)
+	}
+	video, ok := publisher.data[0].(webhooks.VideoData)
+	if !ok || video.VideoID != "new-id" || video.ChannelID != channelID || video.URL != "https://www.youtube.com/watch?v=new-id" {
+		t.Errorf("Expected the new video in the event, got %+v", publisher.data[0])
+	}
+}
+
 func TestProcessChannel_WithNewVideos(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -87,6 +134,7 @@ func TestProcessChannel_WithNewVideos(t *testing.T) {
 	mockFeedProvider := NewMockFeedProvider()
 	videoStore := store.NewVideoStore(1 * time.Hour)
 	processor := NewDefaultChannelProcessor(mockStore, mockFeedProvider, videoStore)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil).AnyTimes() // No tag filters, so yt-dlp isn't run
 
 	channelID := "test-channel-2"
 	lastChecked := time.Now().Add(-24 * time.Hour)
@@ -133,6 +181,12 @@ func TestProcessChannel_WithNewVideos(t *testing.T) {
 	if result.NewVideo.Title != "Newest Video" {
 		t.Errorf("Expected newest video title 'Newest Video', but got '%s'", result.NewVideo.Title)
 	}
+	if len(result.NewVideos) != 2 {
+		t.Fatalf("Expected 2 new videos, but got %d", len(result.NewVideos))
+	}
+	if result.NewVideos[0].ID != "newest-id" || result.NewVideos[1].ID != "older-new-id" {
+		t.Errorf("Expected new videos newest first, but got %s, %s", result.NewVideos[0].ID, result.NewVideos[1].ID)
+	}
 
 	// Verify timestamp was updated to the newest video time
 	if !capturedTimestamp.Equal(newestVideoTime) {
@@ -140,6 +194,46 @@ func TestProcessChannel_WithNewVideos(t *testing.T) {
 	}
 }
 
+func TestPreviewChannel_LeavesLastCheckedTimestamp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	mockFeedProvider := NewMockFeedProvider()
+	videoStore := store.NewVideoStore(1 * time.Hour)
+	processor := NewDefaultChannelProcessor(mockStore, mockFeedProvider, videoStore)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil).AnyTimes() // No tag filters, so yt-dlp isn't run
+	publisher := &recordingPublisher{}
+	processor.SetPublisher(publisher)
+
+	channelID := "test-channel-preview"
+	lastChecked := time.Now().Add(-24 * time.Hour)
+	mockFeedProvider.feeds[channelID] = &rss.Feed{
+		Entries: []rss.Entry{
+			{Title: "New Video", Published: time.Now().Add(-1 * time.Hour), ID: "new-id"},
+			{Title: "Old Video", Published: lastChecked.Add(-1 * time.Hour), ID: "old-id"},
+		},
+	}
+
+	// SetLastCheckedTimestamp must not be called for a preview
+	mockStore.EXPECT().GetLastCheckedTimestamp(channelID).Return(lastChecked, nil)
+
+	result := processor.PreviewChannel(context.Background(), channelID, false, 0)
+
+	if result.Error != nil {
+		t.Fatalf("Unexpected error: %v", result.Error)
+	}
+	if len(result.NewVideos) != 1 || result.NewVideos[0].ID != "new-id" {
+		t.Errorf("Expected only the video published since the last check, got %+v", result.NewVideos)
+	}
+	// Nor is the catalogue touched or the video announced
+	if videoStore.HasVideo("new-id") {
+		t.Error("Expected a preview not to add videos to the catalogue")
+	}
+	if len(publisher.events) != 0 {
+		t.Errorf("Expected a preview not to publish events, got %v", publisher.events)
+	}
+}
+
 func TestProcessChannel_FeedProviderError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -148,6 +242,7 @@ func TestProcessChannel_FeedProviderError(t *testing.T) {
 	mockFeedProvider.err = errors.New("network error")
 	videoStore := store.NewVideoStore(1 * time.Hour)
 	processor := NewDefaultChannelProcessor(mockStore, mockFeedProvider, videoStore)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil).AnyTimes() // No tag filters, so yt-dlp isn't run
 
 	channelID := "test-channel-3"
 
@@ -173,6 +268,7 @@ func TestProcessChannel_FirstTimeCheck(t *testing.T) {
 	mockFeedProvider := NewMockFeedProvider()
 	videoStore := store.NewVideoStore(1 * time.Hour)
 	processor := NewDefaultChannelProcessor(mockStore, mockFeedProvider, videoStore)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil).AnyTimes() // No tag filters, so yt-dlp isn't run
 
 	channelID := "new-channel"
 	// Don't set any last checked timestamp - simulating first time
@@ -221,12 +317,14 @@ func TestProcessChannel_FirstTimeCheck(t *testing.T) {
 	}
 }
 
-func testProcessChannelWithEnrichment(t *testing.T) {
+func TestProcessChannel_EnrichesNewVideosForTagSubscribers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	// Create mocks
 	mockStore := store.NewMockStore(ctrl)
+	subscriber := store.Subscriber{ID: "sub-1", Email: "tags@example.com", Tags: []string{"Tutorial"}, Enabled: true}
+	mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{subscriber}, nil)
 	mockFeedProvider := NewMockFeedProvider()
 	mockVideoStore := store.NewVideoStore(1 * time.Hour)
 
@@ -302,8 +400,51 @@ func testProcessChannelWithEnrichment(t *testing.T) {
 	if len(result.NewVideo.Tags) != len(expectedTags) {
 		t.Errorf("Expected %d tags, got %d", len(expectedTags), len(result.NewVideo.Tags))
 	}
+
+	// The subscriber's tag filter picks the video, and the catalogue keeps its tags
+	if !subscriber.Wants(channelID, *result.NewVideo) {
+		t.Error("Expected the tag subscriber to want the new video")
+	}
+	stored := mockVideoStore.GetAllVideos()
+	if len(stored) != 1 || len(stored[0].Entry.Tags) != len(expectedTags) {
+		t.Errorf("Expected the catalogued video to keep its tags, got %+v", stored)
+	}
+}
+
+func TestProcessChannel_SkipsEnrichmentWithoutTagSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockFeedProvider := NewMockFeedProvider()
+	processor := &DefaultChannelProcessor{
+		db:           mockStore,
+		feedProvider: mockFeedProvider,
+		videoStore:   store.NewVideoStore(1 * time.Hour),
+		enricher:     ytdlp.NewMockEnricher(),
+	}
+
+	channelID := "UC123456789012345678901"
+	mockFeedProvider.feeds[channelID] = &rss.Feed{
+		Entries: []rss.Entry{{Title: "Test Video", ID: "yt:video:dQw4w9WgXcQ", Published: time.Now()}},
+	}
+	mockStore.EXPECT().GetSubscribers().Return([]store.Subscriber{
+		{ID: "sub-1", Email: "all@example.com", Enabled: true},
+		{ID: "sub-2", Email: "disabled@example.com", Tags: []string{"tutorial"}, Enabled: false},
+	}, nil)
+	mockStore.EXPECT().GetLastCheckedTimestamp(channelID).Return(time.Now().Add(-time.Hour), nil)
+	mockStore.EXPECT().SetLastCheckedTimestamp(channelID, gomock.Any())
+
+	result := processor.ProcessChannel(context.Background(), channelID)
+	if result.NewVideo == nil {
+		t.Fatal("Expected new video but got nil")
+	}
+	if len(result.NewVideo.Tags) != 0 || result.NewVideo.Duration != 0 {
+		t.Errorf("Expected no yt-dlp enrichment without tag subscribers, got %+v", result.NewVideo)
+	}
 }
 
+
 func TestProcessChannelWithOptions_IgnoreLastChecked(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -311,6 +452,7 @@ func TestProcessChannelWithOptions_IgnoreLastChecked(t *testing.T) {
 	mockFeedProvider := NewMockFeedProvider()
 	videoStore := store.NewVideoStore(1 * time.Hour)
 	processor := NewDefaultChannelProcessor(mockStore, mockFeedProvider, videoStore)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil).AnyTimes() // No tag filters, so yt-dlp isn't run
 
 	channelID := "test-channel-ignore"
 	lastChecked := time.Now().Add(-24 * time.Hour)
@@ -357,6 +499,7 @@ func TestProcessChannelWithOptions_MaxItems(t *testing.T) {
 	mockFeedProvider := NewMockFeedProvider()
 	videoStore := store.NewVideoStore(1 * time.Hour)
 	processor := NewDefaultChannelProcessor(mockStore, mockFeedProvider, videoStore)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil).AnyTimes() // No tag filters, so yt-dlp isn't run
 
 	channelID := "test-channel-maxitems"
 	lastChecked := time.Now().Add(-24 * time.Hour)
@@ -405,6 +548,9 @@ func TestProcessChannelWithOptions_MaxItems(t *testing.T) {
 	if result.NewVideo.Title != "Video 1" {
 		t.Errorf("Expected newest video title 'Video 1', but got '%s'", result.NewVideo.Title)
 	}
+	if len(result.NewVideos) != 2 {
+		t.Errorf("Expected 2 new videos with maxItems = 2, but got %d", len(result.NewVideos))
+	}
 	if result.ChannelID != channelID {
 		t.Errorf("Expected channel ID %s, but got %s", channelID, result.ChannelID)
 	}
@@ -417,6 +563,7 @@ func TestProcessChannelWithOptions_MaxItemsZero(t *testing.T) {
 	mockFeedProvider := NewMockFeedProvider()
 	videoStore := store.NewVideoStore(1 * time.Hour)
 	processor := NewDefaultChannelProcessor(mockStore, mockFeedProvider, videoStore)
+	mockStore.EXPECT().GetSubscribers().Return(nil, nil).AnyTimes() // No tag filters, so yt-dlp isn't run
 
 	channelID := "test-channel-maxitems-zero"
 	lastChecked := time.Now().Add(-24 * time.Hour)
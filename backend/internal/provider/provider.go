@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"youtube-curator-v2/internal/config"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/openai"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/summary"
+	"youtube-curator-v2/internal/webhooks"
+	"youtube-curator-v2/internal/ytdlp"
+)
+
+// Provider builds the email sender and summary service from the stored SMTP and LLM
+// configuration, rebuilding them whenever that configuration changes so updates made
+// through the API take effect without a restart.
+type Provider struct {
+	store         store.Store
+	config        *config.Config
+	ytdlpEnricher ytdlp.Enricher
+	publisher     webhooks.Publisher // Passed on to the summary services built
+
+	mu             sync.Mutex
+	smtpConfig     *store.SMTPConfig // Configuration the current sender was built from; nil for the environment fallback
+	sender         email.Sender
+	llmConfig      *store.LLMConfig // Configuration the current summary service was built from
+	summaryService summary.SummaryServiceInterface
+	storedOnly     *summary.Service // Serves stored summaries while no LLM is configured
+}
+
+// New creates a new Provider
+func New(store store.Store, cfg *config.Config, ytdlpEnricher ytdlp.Enricher) *Provider {
+	return &Provider{
+		store:         store,
+		config:        cfg,
+		ytdlpEnricher: ytdlpEnricher,
+		storedOnly:    summary.NewService(store, ytdlpEnricher, nil),
+	}
+}
+
+// SetPublisher sets where the summary services publish summary.generated events, including
+// a service already built. Call it before the services are used.
+func (p *Provider) SetPublisher(publisher webhooks.Publisher) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.publisher = publisher
+	p.storedOnly.SetPublisher(publisher)
+	if service, ok := p.summaryService.(*summary.Service); ok {
+		service.SetPublisher(publisher)
+	}
+}
+
+// CurrentEmailSender returns the sender for the current SMTP configuration. Without a stored
+// configuration it falls back to the SMTP settings from the environment.
+func (p *Provider) CurrentEmailSender() email.Sender {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	smtpConfig, err := p.store.GetSMTPConfig()
+	if err != nil {
+		log.Printf("Warning: Failed to get SMTP configuration: %v", err)
+		if p.sender != nil {
+			return p.sender // Keep the last known sender until the store can be read again
+		}
+	}
+	if smtpConfig != nil && smtpConfig.Server == "" {
+		smtpConfig = nil
+	}
+
+	if p.sender != nil && sameConfig(p.smtpConfig, smtpConfig) {
+		return p.sender
+	}
+
+	if smtpConfig != nil {
+		p.sender = email.NewEmailSenderFromConfig(smtpConfig)
+	} else {
+		// Fall back to environment variables for backward compatibility
+		p.sender = email.NewEmailSender(p.config.SMTPServer, p.config.SMTPPort, p.config.SMTPUsername, p.config.SMTPPassword)
+	}
+	p.smtpConfig = smtpConfig
+	return p.sender
+}
+
+// CurrentSummaryService returns the summary service for the current LLM configuration,
+// or nil when no LLM is configured
+func (p *Provider) CurrentSummaryService() summary.SummaryServiceInterface {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.config.DebugSkipSummary {
+		if p.summaryService == nil {
+			p.summaryService = summary.NewMockService(p.store)
+		}
+		return p.summaryService
+	}
+
+	llmConfig, err := p.store.GetLLMConfig()
+	if err != nil {
+		log.Printf("Warning: Failed to get LLM configuration: %v", err)
+		return p.summaryService // Keep the last known service until the store can be read again
+	}
+	if llmConfig == nil || llmConfig.EndpointURL == "" {
+		p.llmConfig = nil
+		p.summaryService = nil
+		return nil
+	}
+
+	if p.summaryService == nil || !sameConfig(p.llmConfig, llmConfig) {
+		openAIClient := openai.New(llmConfig.EndpointURL, llmConfig.APIKey, llmConfig.Model)
+		service := summary.NewService(p.store, p.ytdlpEnricher, openAIClient)
+		service.SetPublisher(p.publisher)
+		p.summaryService = service
+		p.llmConfig = llmConfig
+	}
+	return p.summaryService
+}
+
+// EmailSender returns a sender that resolves the current sender on every send, for
+// long-lived consumers such as the API handlers
+func (p *Provider) EmailSender() email.Sender {
+	return providedSender{provider: p}
+}
+
+// SummaryService returns a summary service that resolves the current service on every call,
+// for long-lived consumers such as the API handlers and the job queue. Until an LLM is
+// configured it serves stored summaries, and reports summary.ErrLLMNotConfigured for the rest
+// so callers can tell a missing configuration apart from a failed generation. It is never nil.
+func (p *Provider) SummaryService() summary.SummaryServiceInterface {
+	return providedSummaryService{provider: p}
+}
+
+// sameConfig reports whether two optional configurations are equal
+func sameConfig[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// providedSender delegates to the provider's current email sender
+type providedSender struct {
+	provider *Provider
+}
+
+func (s providedSender) Send(recipient string, subject string, content email.Content) error {
+	return s.provider.CurrentEmailSender().Send(recipient, subject, content)
+}
+
+// providedSummaryService delegates to the provider's current summary service
+type providedSummaryService struct {
+	provider *Provider
+}
+
+// current returns the provider's current summary service, or the stored-only service without an LLM
+func (s providedSummaryService) current() summary.SummaryServiceInterface {
+	if service := s.provider.CurrentSummaryService(); service != nil {
+		return service
+	}
+	return s.provider.storedOnly
+}
+
+func (s providedSummaryService) GetOrGenerateSummary(ctx context.Context, videoID string) *summary.SummaryResult {
+	return s.current().GetOrGenerateSummary(ctx, videoID)
+}
+
+func (s providedSummaryService) RegenerateSummary(ctx context.Context, videoID string) *summary.SummaryResult {
+	return s.current().RegenerateSummary(ctx, videoID)
+}
+
+func (s providedSummaryService) StreamSummary(ctx context.Context, videoID string, regenerate bool, events chan<- summary.StreamEvent) {
+	s.current().StreamSummary(ctx, videoID, regenerate, events)
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"youtube-curator-v2/internal/config"
+	"youtube-curator-v2/internal/email"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/summary"
+	"youtube-curator-v2/internal/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{SMTPServer: "env.example.com", SMTPPort: "587", SMTPUsername: "env-user", SMTPPassword: "env-pass"}
+}
+
+func TestCurrentEmailSender_EnvironmentFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetSMTPConfig().Return(nil, nil).Times(2)
+	p := New(mockStore, testConfig(), nil)
+
+	sender, ok := p.CurrentEmailSender().(*email.EmailSender)
+	require.True(t, ok)
+	assert.Equal(t, "env.example.com", sender.SMTPServer)
+	assert.Same(t, sender, p.CurrentEmailSender(), "An unchanged configuration should reuse the sender")
+}
+
+func TestCurrentEmailSender_RebuildsOnChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first := &store.SMTPConfig{Server: "smtp.example.com", Port: "587", Username: "user", Password: "secret"}
+	second := *first
+	second.Port = "465"
+
+	mockStore := store.NewMockStore(ctrl)
+	gomock.InOrder(
+		mockStore.EXPECT().GetSMTPConfig().Return(first, nil),
+		mockStore.EXPECT().GetSMTPConfig().Return(&store.SMTPConfig{Server: "smtp.example.com", Port: "587", Username: "user", Password: "secret"}, nil),
+		mockStore.EXPECT().GetSMTPConfig().Return(&second, nil),
+		mockStore.EXPECT().GetSMTPConfig().Return(nil, errors.New("db closed")),
+	)
+	p := New(mockStore, testConfig(), nil)
+
+	initial := p.CurrentEmailSender()
+	assert.Equal(t, "smtp.example.com", initial.(*email.EmailSender).SMTPServer)
+	assert.Same(t, initial, p.CurrentEmailSender(), "An equal configuration should reuse the sender")
+
+	updated := p.CurrentEmailSender()
+	assert.NotSame(t, initial, updated)
+	assert.Equal(t, "465", updated.(*email.EmailSender).SMTPPort)
+
+	assert.Same(t, updated, p.CurrentEmailSender(), "A store error should keep the last sender")
+}
+
+func TestCurrentSummaryService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	llmConfig := &store.LLMConfig{EndpointURL: "http://localhost:1234/v1", APIKey: "key", Model: "model"}
+	mockStore := store.NewMockStore(ctrl)
+	gomock.InOrder(
+		mockStore.EXPECT().GetLLMConfig().Return(nil, nil),
+		mockStore.EXPECT().GetLLMConfig().Return(llmConfig, nil),
+		mockStore.EXPECT().GetLLMConfig().Return(&store.LLMConfig{EndpointURL: "http://localhost:1234/v1", APIKey: "key", Model: "model"}, nil),
+		mockStore.EXPECT().GetLLMConfig().Return(&store.LLMConfig{EndpointURL: "http://localhost:1234/v1", APIKey: "key", Model: "other"}, nil),
+		mockStore.EXPECT().GetLLMConfig().Return(&store.LLMConfig{}, nil),
+	)
+	p := New(mockStore, testConfig(), nil)
+
+	assert.Nil(t, p.CurrentSummaryService(), "No service until an LLM is configured")
+
+	configured := p.CurrentSummaryService()
+	require.NotNil(t, configured)
+	assert.Same(t, configured, p.CurrentSummaryService(), "An equal configuration should reuse the service")
+	assert.NotSame(t, configured, p.CurrentSummaryService(), "A model change should rebuild the service")
+	assert.Nil(t, p.CurrentSummaryService(), "Clearing the endpoint should drop the service")
+}
+
+func TestCurrentSummaryService_DebugSkipSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := testConfig()
+	cfg.DebugSkipSummary = true
+	p := New(store.NewMockStore(ctrl), cfg, nil)
+
+	_, ok := p.CurrentSummaryService().(*summary.MockService)
+	assert.True(t, ok, "Debug mode should use the mock service without reading the LLM configuration")
+}
+
+func TestSummaryService_NotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetLLMConfig().Return(nil, nil).AnyTimes()
+	mockStore.EXPECT().GetSummary("abc").Return(nil, nil).Times(2)
+	service := New(mockStore, testConfig(), nil).SummaryService()
+
+	result := service.GetOrGenerateSummary(context.Background(), "abc")
+	assert.Equal(t, "abc", result.VideoID)
+	assert.ErrorIs(t, result.Error, summary.ErrLLMNotConfigured)
+	assert.ErrorIs(t, service.RegenerateSummary(context.Background(), "abc").Error, summary.ErrLLMNotConfigured)
+
+	events := make(chan summary.StreamEvent, 1)
+	service.StreamSummary(context.Background(), "abc", false, events)
+	event, ok := <-events
+	require.True(t, ok)
+	assert.Equal(t, summary.StreamEventError, event.Type)
+	assert.ErrorIs(t, event.Err, summary.ErrLLMNotConfigured)
+	_, ok = <-events
+	assert.False(t, ok, "The events channel should be closed")
+}
+
+func TestSummaryService_StoredSummaryWithoutLLM(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetLLMConfig().Return(nil, nil)
+	mockStore.EXPECT().GetSummary("yt:video:dQw4w9WgXcQ").Return(&store.StoredSummary{VideoID: "yt:video:dQw4w9WgXcQ", Text: "Stored summary"}, nil)
+	service := New(mockStore, testConfig(), nil).SummaryService()
+
+	result := service.GetOrGenerateSummary(context.Background(), "yt:video:dQw4w9WgXcQ")
+	require.NoError(t, result.Error)
+	assert.Equal(t, "Stored summary", result.Summary)
+}
+
+// subtitleEnricher points every video at a test subtitle track
+type subtitleEnricher struct {
+	url string
+}
+
+func (e *subtitleEnricher) EnrichEntry(ctx context.Context, entry *rss.Entry) error {
+	entry.ChannelID = "UCtracked"
+	entry.AutoSubtitles = e.url
+	return nil
+}
+
+func (e *subtitleEnricher) ResolveChannelID(ctx context.Context, url string) (string, error) {
+	return "", nil
+}
+
+// recordingPublisher records the events published to it
+type recordingPublisher struct {
+	events []string
+}
+
+func (p *recordingPublisher) Publish(event string, data any) {
+	p.events = append(p.events, event)
+}
+
+func TestSetPublisher_ReachesBuiltService(t *testing.T) {
+	subtitles := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("WEBVTT\n\n00:00:01.000 --> 00:00:04.000\nThis is a test subtitle.\n"))
+	}))
+	defer subtitles.Close()
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"1","object":"chat.completion","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"The summary."}}]}`)
+	}))
+	defer llm.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetLLMConfig().Return(&store.LLMConfig{EndpointURL: llm.URL, Model: "test-model"}, nil).AnyTimes()
+	mockStore.EXPECT().SetTranscript(gomock.Any()).Return(nil)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCtracked"}}, nil)
+	mockStore.EXPECT().SetSummary(gomock.Any()).Return(nil)
+
+	// Startup builds the service before the webhook dispatcher is wired up
+	p := New(mockStore, testConfig(), &subtitleEnricher{url: subtitles.URL})
+	built := p.CurrentSummaryService()
+	require.NotNil(t, built)
+	publisher := &recordingPublisher{}
+	p.SetPublisher(publisher)
+	require.Same(t, built, p.CurrentSummaryService(), "Setting the publisher should keep the built service")
+
+	result := p.SummaryService().RegenerateSummary(context.Background(), "yt:video:dQw4w9WgXcQ")
+	require.NoError(t, result.Error)
+	assert.Equal(t, []string{webhooks.EventSummaryGenerated}, publisher.events)
+}
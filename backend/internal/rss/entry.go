@@ -31,6 +31,7 @@ type Entry struct {
 	Title      string     `xml:"title" json:"title"`
 	Link       Link       `xml:"link" json:"link"`
 	ID         string     `xml:"id" json:"id"`
+	ChannelID  string     `xml:"http://www.youtube.com/xml/schemas/2015 channelId" json:"channelId,omitempty"`
 	Published  time.Time  `xml:"published" json:"published"`
 	Content    string     `xml:"content" json:"content"`
 	Author     Author     `xml:"author" json:"author"`
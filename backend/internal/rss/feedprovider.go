@@ -21,9 +21,14 @@ func NewFeedProvider() *DefaultFeedProvider {
 	return &DefaultFeedProvider{}
 }
 
+// ChannelFeedURL returns the URL of a channel's video feed
+func ChannelFeedURL(channelID string) string {
+	return fmt.Sprintf("https://www.youtube.com/feeds/videos.xml?channel_id=%s", channelID)
+}
+
 // FetchFeed implements FeedProvider.FetchFeed
 func (p *DefaultFeedProvider) FetchFeed(ctx context.Context, channelID string) (*Feed, error) {
-	url := fmt.Sprintf("https://www.youtube.com/feeds/videos.xml?channel_id=%s", channelID)
+	url := ChannelFeedURL(channelID)
 	rssString, err := fetchRSS(url)
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch RSS for channel ID %s: %w", channelID, err)
@@ -21,6 +21,7 @@ type ChannelIDResolver interface {
 // - https://www.youtube.com/@USERNAME
 // - https://www.youtube.com/c/CUSTOM_NAME
 // - https://www.youtube.com/user/USERNAME
+// - https://www.youtube.com/feeds/videos.xml?channel_id=CHANNEL_ID
 // - Direct channel ID input
 func ExtractChannelID(input string) (string, error) {
 	// First, check if input is already a valid channel ID (starts with UC and is 24 characters)
@@ -50,6 +51,9 @@ func ExtractChannelID(input string) (string, error) {
 			return "", NewInvalidChannelIDError(channelID)
 		}
 		return channelID, nil
+	case "feeds":
+		// Channel feed URL: https://www.youtube.com/feeds/videos.xml?channel_id=CHANNEL_ID
+		return feedChannelID(parsedURL)
 	case "c", "user":
 		// Custom URL or user URL - these cannot be resolved without yt-dlp
 		return "", NewResolverRequiredError("custom URLs (/c/, /user/)")
@@ -62,6 +66,22 @@ func ExtractChannelID(input string) (string, error) {
 	}
 }
 
+// feedChannelID reads the channel ID from the channel_id parameter of a channel feed URL.
+// Playlist and user feeds are not supported.
+func feedChannelID(feedURL *url.URL) (string, error) {
+	if strings.Trim(feedURL.Path, "/") != "feeds/videos.xml" {
+		return "", ErrUnsupportedURLFormat
+	}
+	channelID := feedURL.Query().Get("channel_id")
+	if channelID == "" {
+		return "", ErrUnsupportedURLFormat
+	}
+	if !isValidChannelID(channelID) {
+		return "", NewInvalidChannelIDError(channelID)
+	}
+	return channelID, nil
+}
+
 // isValidChannelID checks if a string is a valid YouTube channel ID
 // YouTube channel IDs are typically 24 characters long and start with 'UC'
 func isValidChannelID(id string) bool {
@@ -131,6 +151,9 @@ func ExtractChannelIDWithResolver(ctx context.Context, input string, resolver Ch
 			return "", NewInvalidChannelIDError(channelID)
 		}
 		return channelID, nil
+	case "feeds":
+		// Channel feed URL: https://www.youtube.com/feeds/videos.xml?channel_id=CHANNEL_ID
+		return feedChannelID(parsedURL)
 	case "c", "user":
 		// Custom URL or user URL - use resolver if provided
 		if resolver == nil {
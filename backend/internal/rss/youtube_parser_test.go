@@ -107,6 +107,27 @@ func TestExtractChannelIDWithResolver_Refactored(t *testing.T) {
 			expectedID: "UCrAhw9Z8NI6GzO2WnvhYzCg",
 			expectErr:  false,
 		},
+		{
+			name:       "Channel feed URL - no resolver needed",
+			input:      "https://www.youtube.com/feeds/videos.xml?channel_id=UCrAhw9Z8NI6GzO2WnvhYzCg",
+			resolver:   nil,
+			expectedID: "UCrAhw9Z8NI6GzO2WnvhYzCg",
+			expectErr:  false,
+		},
+		{
+			name:      "Channel feed URL with invalid channel ID",
+			input:     "https://www.youtube.com/feeds/videos.xml?channel_id=invalid",
+			resolver:  nil,
+			expectErr: true,
+			errType:   NewInvalidChannelIDError("invalid"),
+		},
+		{
+			name:      "Playlist feed URL",
+			input:     "https://www.youtube.com/feeds/videos.xml?playlist_id=PLrAXtmErZgOeiKm4sgNOknGvNjby9efdf",
+			resolver:  &MockResolver{},
+			expectErr: true,
+			errType:   ErrUnsupportedURLFormat,
+		},
 
 		// Test cases that require resolver - using specific error types
 		{
@@ -256,3 +277,17 @@ func TestValidateChannelID_Refactored(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractChannelID_FeedURL(t *testing.T) {
+	channelID, err := ExtractChannelID("https://www.youtube.com/feeds/videos.xml?channel_id=UCrAhw9Z8NI6GzO2WnvhYzCg")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if channelID != "UCrAhw9Z8NI6GzO2WnvhYzCg" {
+		t.Errorf("Expected channel ID UCrAhw9Z8NI6GzO2WnvhYzCg, got %q", channelID)
+	}
+
+	if _, err := ExtractChannelID("https://www.youtube.com/feeds/videos.xml?user=someuser"); !errors.Is(err, ErrUnsupportedURLFormat) {
+		t.Errorf("Expected ErrUnsupportedURLFormat for a user feed, got %v", err)
+	}
+}
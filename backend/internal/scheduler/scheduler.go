@@ -0,0 +1,197 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"youtube-curator-v2/internal/store"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Source identifies where the schedule in effect comes from
+type Source string
+
+const (
+	SourceCron        Source = "cron"        // Cron expression from the newsletter configuration
+	SourceInterval    Source = "interval"    // Check interval stored through the API
+	SourceEnvironment Source = "environment" // CRON_SCHEDULE from the environment
+)
+
+// Status describes the schedule in effect and the scheduled runs
+type Status struct {
+	Enabled         bool
+	Schedule        string // Cron expression, or "@every <interval>" for interval schedules
+	Source          Source
+	NextRun         *time.Time // Nil when nothing is scheduled
+	LastRun         *time.Time // When the last scheduled run started
+	LastRunFinished *time.Time // When the last scheduled run finished
+	Running         bool
+	Error           string // Why the stored schedule couldn't be applied
+}
+
+// Scheduler runs the newsletter job on the schedule from the store. The newsletter
+// configuration's cron expression takes precedence over the check interval (see
+// store.GetScheduleInterval), which in turn takes precedence over the default schedule
+// from the environment.
+// Call Reload after changing the configuration to reschedule without a restart.
+type Scheduler struct {
+	store           store.Store
+	defaultSchedule string
+	run             func()
+	cron            *cron.Cron
+
+	mu              sync.Mutex
+	entryID         cron.EntryID // Zero when nothing is scheduled
+	enabled         bool
+	schedule        string
+	source          Source
+	err             error
+	lastRun         *time.Time
+	lastRunFinished *time.Time
+	running         bool
+}
+
+// New creates a new Scheduler that calls run on the stored schedule, falling back to
+// defaultSchedule (a cron expression) when none is stored
+func New(store store.Store, defaultSchedule string, run func()) *Scheduler {
+	return &Scheduler{
+		store:           store,
+		defaultSchedule: defaultSchedule,
+		run:             run,
+		cron:            cron.New(),
+	}
+}
+
+// ValidateCronSchedule checks that spec is a standard five-field cron expression or descriptor
+func ValidateCronSchedule(spec string) error {
+	if _, err := cron.ParseStandard(spec); err != nil {
+		return fmt.Errorf("invalid cron schedule: %w", err)
+	}
+	return nil
+}
+
+// Start applies the stored schedule and starts the scheduler. The scheduler keeps running
+// if the stored schedule is invalid, so a later Reload can fix it.
+func (s *Scheduler) Start() error {
+	s.cron.Start()
+	return s.Reload()
+}
+
+// Stop stops the scheduler and waits for a running job to finish
+func (s *Scheduler) Stop() {
+	<-s.cron.Stop().Done()
+}
+
+// Reload re-reads the schedule and enabled flag from the store and reschedules the job
+func (s *Scheduler) Reload() error {
+	enabled, spec, source, schedule, err := s.resolve()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entryID != 0 {
+		s.cron.Remove(s.entryID)
+		s.entryID = 0
+	}
+	s.enabled = enabled
+	s.schedule = spec
+	s.source = source
+	s.err = err
+	if err != nil {
+		return err
+	}
+
+	if enabled && schedule != nil {
+		s.entryID = s.cron.Schedule(schedule, cron.FuncJob(s.runJob))
+		log.Printf("Newsletter scheduled with %s schedule %s", source, spec)
+	} else {
+		log.Println("Newsletter schedule disabled")
+	}
+	return nil
+}
+
+// Status reports the schedule in effect along with the next and last run times
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{
+		Enabled:         s.enabled,
+		Schedule:        s.schedule,
+		Source:          s.source,
+		LastRun:         s.lastRun,
+		LastRunFinished: s.lastRunFinished,
+		Running:         s.running,
+	}
+	if s.err != nil {
+		status.Error = s.err.Error()
+	}
+	if s.entryID != 0 {
+		if next := s.cron.Entry(s.entryID).Next; !next.IsZero() {
+			status.NextRun = &next
+		}
+	}
+	return status
+}
+
+// resolve works out the schedule in effect from the store. The schedule is nil when
+// no schedule is configured at all.
+func (s *Scheduler) resolve() (bool, string, Source, cron.Schedule, error) {
+	newsletterConfig, err := s.store.GetNewsletterConfig()
+	if err != nil {
+		return false, "", "", nil, fmt.Errorf("failed to get newsletter configuration: %w", err)
+	}
+	enabled := newsletterConfig == nil || newsletterConfig.Enabled
+
+	if newsletterConfig != nil && newsletterConfig.CronSchedule != "" {
+		schedule, err := cron.ParseStandard(newsletterConfig.CronSchedule)
+		if err != nil {
+			return enabled, newsletterConfig.CronSchedule, SourceCron, nil, fmt.Errorf("invalid cron schedule %q: %w", newsletterConfig.CronSchedule, err)
+		}
+		return enabled, newsletterConfig.CronSchedule, SourceCron, schedule, nil
+	}
+
+	interval, err := s.store.GetScheduleInterval()
+	if err != nil {
+		return enabled, "", "", nil, fmt.Errorf("failed to get check interval: %w", err)
+	}
+	if interval > 0 {
+		return enabled, "@every " + interval.String(), SourceInterval, cron.Every(interval), nil
+	}
+
+	if s.defaultSchedule == "" {
+		return enabled, "", SourceEnvironment, nil, nil
+	}
+	schedule, err := cron.ParseStandard(s.defaultSchedule)
+	if err != nil {
+		return enabled, s.defaultSchedule, SourceEnvironment, nil, fmt.Errorf("invalid cron schedule %q: %w", s.defaultSchedule, err)
+	}
+	return enabled, s.defaultSchedule, SourceEnvironment, schedule, nil
+}
+
+// runJob runs the job, skipping the run if the previous one is still going
+func (s *Scheduler) runJob() {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		log.Println("Skipping scheduled newsletter run: the previous run is still in progress")
+		return
+	}
+	started := time.Now()
+	s.running = true
+	s.lastRun = &started
+	s.mu.Unlock()
+
+	defer func() {
+		finished := time.Now()
+		s.mu.Lock()
+		s.running = false
+		s.lastRunFinished = &finished
+		s.mu.Unlock()
+	}()
+
+	s.run()
+}
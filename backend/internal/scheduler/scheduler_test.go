@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReload_SchedulePrecedence(t *testing.T) {
+	tests := []struct {
+		name             string
+		newsletterConfig *store.NewsletterConfig
+		interval         time.Duration
+		expectedSource   Source
+		expectedSchedule string
+	}{
+		{
+			name:             "cron expression from the newsletter configuration",
+			newsletterConfig: &store.NewsletterConfig{Enabled: true, CronSchedule: "30 7 * * *"},
+			expectedSource:   SourceCron,
+			expectedSchedule: "30 7 * * *",
+		},
+		{
+			name:             "stored check interval",
+			newsletterConfig: &store.NewsletterConfig{Enabled: true},
+			interval:         2 * time.Hour,
+			expectedSource:   SourceInterval,
+			expectedSchedule: "@every 2h0m0s",
+		},
+		{
+			name:             "environment default",
+			newsletterConfig: &store.NewsletterConfig{Enabled: true},
+			expectedSource:   SourceEnvironment,
+			expectedSchedule: "0 0 * * *",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := store.NewMockStore(ctrl)
+			mockStore.EXPECT().GetNewsletterConfig().Return(tt.newsletterConfig, nil)
+			mockStore.EXPECT().GetScheduleInterval().Return(tt.interval, nil).AnyTimes()
+
+			s := New(mockStore, "0 0 * * *", func() {})
+			require.NoError(t, s.Start())
+			defer s.Stop()
+
+			status := s.Status()
+			assert.True(t, status.Enabled)
+			assert.Equal(t, tt.expectedSource, status.Source)
+			assert.Equal(t, tt.expectedSchedule, status.Schedule)
+			if assert.NotNil(t, status.NextRun) {
+				assert.True(t, status.NextRun.After(time.Now()))
+			}
+			assert.Nil(t, status.LastRun)
+		})
+	}
+}
+
+func TestReload_DisableAndEnable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	gomock.InOrder(
+		mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true}, nil),
+		mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: false}, nil),
+		mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true, CronSchedule: "@daily"}, nil),
+	)
+	mockStore.EXPECT().GetScheduleInterval().Return(time.Hour, nil).Times(2)
+
+	s := New(mockStore, "", func() {})
+	require.NoError(t, s.Start())
+	defer s.Stop()
+	assert.NotNil(t, s.Status().NextRun)
+
+	require.NoError(t, s.Reload())
+	status := s.Status()
+	assert.False(t, status.Enabled)
+	assert.Nil(t, status.NextRun, "Nothing should be scheduled while the newsletter is disabled")
+
+	require.NoError(t, s.Reload())
+	status = s.Status()
+	assert.True(t, status.Enabled)
+	assert.Equal(t, "@daily", status.Schedule)
+	assert.NotNil(t, status.NextRun)
+}
+
+func TestReload_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockStore(ctrl)
+	gomock.InOrder(
+		mockStore.EXPECT().GetNewsletterConfig().Return(nil, errors.New("db closed")),
+		mockStore.EXPECT().GetNewsletterConfig().Return(&store.NewsletterConfig{Enabled: true, CronSchedule: "not a schedule"}, nil),
+	)
+
+	s := New(mockStore, "0 0 * * *", func() {})
+	assert.Error(t, s.Start())
+	defer s.Stop()
+	assert.Contains(t, s.Status().Error, "db closed")
+
+	assert.Error(t, s.Reload())
+	status := s.Status()
+	assert.Contains(t, status.Error, "invalid cron schedule")
+	assert.Nil(t, status.NextRun)
+}
+
+func TestRunJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	release := make(chan struct{})
+	var runs int
+	var mu sync.Mutex
+	s := New(store.NewMockStore(ctrl), "", func() {
+		mu.Lock()
+		runs++
+		mu.Unlock()
+		<-release
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.runJob()
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return s.Status().Running }, time.Second, time.Millisecond)
+
+	// A run that starts while the previous one is still going is skipped
+	s.runJob()
+	close(release)
+	<-done
+
+	status := s.Status()
+	assert.False(t, status.Running)
+	require.NotNil(t, status.LastRun)
+	require.NotNil(t, status.LastRunFinished)
+	assert.False(t, status.LastRunFinished.Before(*status.LastRun))
+	mu.Lock()
+	assert.Equal(t, 1, runs)
+	mu.Unlock()
+}
+
+func TestValidateCronSchedule(t *testing.T) {
+	for _, spec := range []string{"0 0 * * *", "*/15 * * * 1-5", "@hourly", "@every 90m"} {
+		assert.NoError(t, ValidateCronSchedule(spec), spec)
+	}
+	for _, spec := range []string{"", "every morning", "0 0 * *", "61 * * * *"} {
+		assert.Error(t, ValidateCronSchedule(spec), spec)
+	}
+}
@@ -0,0 +1,437 @@
+// Package search provides a local full-text index over catalogued videos, their
+// transcripts and stored summaries.
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"youtube-curator-v2/internal/store"
+)
+
+// Field identifies the part of a video a match was found in
+type Field string
+
+const (
+	FieldTitle       Field = "title"
+	FieldDescription Field = "description"
+	FieldSummary     Field = "summary"
+	FieldTranscript  Field = "transcript"
+)
+
+// fieldWeights rank matches in short, descriptive fields above matches deep in a transcript
+var fieldWeights = map[Field]float64{
+	FieldTitle:       3,
+	FieldSummary:     2,
+	FieldDescription: 1.5,
+	FieldTranscript:  1,
+}
+
+// fieldOrder lists fields in the order matches are reported
+var fieldOrder = []Field{FieldTitle, FieldSummary, FieldDescription, FieldTranscript}
+
+const (
+	// maxOffsetsPerField bounds the occurrence offsets kept per term and field; only the
+	// first one is needed for a snippet, the count is kept separately for scoring
+	maxOffsetsPerField = 4
+	snippetRadius      = 80 // Bytes of context on each side of the first match
+	highlightOpen      = "<mark>"
+	highlightClose     = "</mark>"
+)
+
+// Match is a highlighted snippet of a field that matched the query
+type Match struct {
+	Field   Field
+	Snippet string // HTML-escaped text with matched terms wrapped in <mark></mark>
+	StartMs int    // Offset into the video of the matching transcript cue; -1 for other fields
+}
+
+// Hit is a video matching a query
+type Hit struct {
+	VideoID string
+	Title   string
+	Score   float64
+	Matches []Match
+}
+
+// fieldText is the indexed text of one field of a document
+type fieldText struct {
+	text       string
+	cueOffsets []int // For transcripts, the byte offset in text where each cue starts
+	cueStartMs []int // For transcripts, the start time of each cue
+}
+
+// occurrences records where a term appears in one field of a document
+type occurrences struct {
+	count   int
+	offsets []int // Byte offsets of the first maxOffsetsPerField occurrences
+}
+
+// Index is an in-memory inverted index, safe for concurrent use
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]map[Field]*fieldText            // video ID -> field -> text
+	postings map[string]map[string]map[Field]*occurrences // term -> video ID -> field -> occurrences
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]map[Field]*fieldText),
+		postings: make(map[string]map[string]map[Field]*occurrences),
+	}
+}
+
+// Rebuild replaces the index contents with every catalogued video, including its
+// stored summary and transcript
+func (idx *Index) Rebuild(db store.Store) error {
+	videos, err := db.GetVideos()
+	if err != nil {
+		return err
+	}
+
+	fresh := NewIndex()
+	for _, video := range videos {
+		fresh.IndexVideo(video)
+		transcript, err := db.GetTranscript(video.Entry.ID)
+		if err != nil {
+			return err
+		}
+		if transcript != nil {
+			fresh.IndexTranscript(transcript)
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs = fresh.docs
+	idx.postings = fresh.postings
+	return nil
+}
+
+// IndexVideo indexes the title, description and (if joined) summary of a video
+func (idx *Index) IndexVideo(video store.VideoEntry) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.setField(video.Entry.ID, FieldTitle, &fieldText{text: video.Entry.Title})
+	idx.setField(video.Entry.ID, FieldDescription, &fieldText{text: video.Entry.MediaGroup.MediaDescription})
+	if video.Entry.Summary != nil {
+		idx.setField(video.Entry.ID, FieldSummary, &fieldText{text: video.Entry.Summary.Text})
+	}
+}
+
+// IndexSummary indexes the summary text of a video
+func (idx *Index) IndexSummary(videoID, text string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.setField(videoID, FieldSummary, &fieldText{text: text})
+}
+
+// IndexTranscript indexes a transcript, remembering cue times for match timestamps
+func (idx *Index) IndexTranscript(transcript *store.Transcript) {
+	field := &fieldText{}
+	var text strings.Builder
+	for _, cue := range transcript.Cues {
+		if text.Len() > 0 {
+			text.WriteByte(' ')
+		}
+		field.cueOffsets = append(field.cueOffsets, text.Len())
+		field.cueStartMs = append(field.cueStartMs, cue.StartMs)
+		text.WriteString(cue.Text)
+	}
+	field.text = text.String()
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.setField(transcript.VideoID, FieldTranscript, field)
+}
+
+// Retain removes every video whose ID is not in videoIDs
+func (idx *Index) Retain(videoIDs []string) {
+	keep := make(map[string]bool, len(videoIDs))
+	for _, videoID := range videoIDs {
+		keep[videoID] = true
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for videoID, fields := range idx.docs {
+		if keep[videoID] {
+			continue
+		}
+		for field := range fields {
+			idx.setField(videoID, field, nil)
+		}
+	}
+}
+
+// hasField reports whether a field of a video is indexed
+func (idx *Index) hasField(videoID string, field Field) bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.docs[videoID][field] != nil
+}
+
+// setField replaces the indexed text of one field of a document; nil removes it.
+// The caller must hold the write lock.
+func (idx *Index) setField(videoID string, field Field, value *fieldText) {
+	fields := idx.docs[videoID]
+	if old := fields[field]; old != nil {
+		for _, tok := range tokenize(old.text) {
+			docs := idx.postings[tok.term]
+			delete(docs[videoID], field)
+			if len(docs[videoID]) == 0 {
+				delete(docs, videoID)
+			}
+			if len(docs) == 0 {
+				delete(idx.postings, tok.term)
+			}
+		}
+		delete(fields, field)
+	}
+
+	if value == nil || strings.TrimSpace(value.text) == "" {
+		if len(fields) == 0 {
+			delete(idx.docs, videoID)
+		}
+		return
+	}
+
+	if fields == nil {
+		fields = make(map[Field]*fieldText)
+		idx.docs[videoID] = fields
+	}
+	fields[field] = value
+
+	for _, tok := range tokenize(value.text) {
+		docs := idx.postings[tok.term]
+		if docs == nil {
+			docs = make(map[string]map[Field]*occurrences)
+			idx.postings[tok.term] = docs
+		}
+		if docs[videoID] == nil {
+			docs[videoID] = make(map[Field]*occurrences)
+		}
+		occ := docs[videoID][field]
+		if occ == nil {
+			occ = &occurrences{}
+			docs[videoID][field] = occ
+		}
+		occ.count++
+		if len(occ.offsets) < maxOffsetsPerField {
+			occ.offsets = append(occ.offsets, tok.start)
+		}
+	}
+}
+
+// Search returns videos containing every term of query, best matches first.
+// A limit of 0 returns all hits.
+func (idx *Index) Search(query string, limit int) []Hit {
+	hits, _ := idx.SearchWithTotal(query, limit)
+	return hits
+}
+
+// SearchWithTotal is Search, also returning how many videos matched before the limit was applied
+func (idx *Index) SearchWithTotal(query string, limit int) ([]Hit, int) {
+	terms := uniqueTerms(query)
+	if len(terms) == 0 {
+		return nil, 0
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// Start from the rarest term so the candidate set is as small as possible
+	sort.Slice(terms, func(i, j int) bool {
+		return len(idx.postings[terms[i]]) < len(idx.postings[terms[j]])
+	})
+
+	var hits []Hit
+	totalDocs := float64(len(idx.docs))
+	for videoID := range idx.postings[terms[0]] {
+		score := 0.0
+		matched := true
+		for _, term := range terms {
+			fields, ok := idx.postings[term][videoID]
+			if !ok {
+				matched = false
+				break
+			}
+			idf := math.Log(1 + totalDocs/float64(len(idx.postings[term])))
+			for field, occ := range fields {
+				score += fieldWeights[field] * (1 + math.Log(float64(occ.count))) * idf
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		hit := Hit{VideoID: videoID, Score: score}
+		if title := idx.docs[videoID][FieldTitle]; title != nil {
+			hit.Title = title.text
+		}
+		hits = append(hits, hit)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].VideoID < hits[j].VideoID
+	})
+	total := len(hits)
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	// Snippets are only built for the hits returned
+	for i := range hits {
+		hits[i].Matches = idx.matches(hits[i].VideoID, terms)
+	}
+	return hits, total
+}
+
+// matches builds one highlighted snippet per field containing any of terms.
+// The caller must hold the read lock.
+func (idx *Index) matches(videoID string, terms []string) []Match {
+	var matches []Match
+	for _, field := range fieldOrder {
+		value := idx.docs[videoID][field]
+		if value == nil {
+			continue
+		}
+
+		// The earliest occurrence of any query term anchors the snippet
+		first := -1
+		for _, term := range terms {
+			if occ := idx.postings[term][videoID][field]; occ != nil && len(occ.offsets) > 0 {
+				if first < 0 || occ.offsets[0] < first {
+					first = occ.offsets[0]
+				}
+			}
+		}
+		if first < 0 {
+			continue
+		}
+
+		match := Match{Field: field, Snippet: snippet(value.text, first, terms), StartMs: -1}
+		if field == FieldTranscript && len(value.cueOffsets) > 0 {
+			cue := sort.SearchInts(value.cueOffsets, first+1) - 1
+			if cue < 0 {
+				cue = 0
+			}
+			match.StartMs = value.cueStartMs[cue]
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// snippet cuts a window of text around offset, HTML-escapes it and highlights every query term
+func snippet(text string, offset int, terms []string) string {
+	start := offset - snippetRadius
+	if start < 0 {
+		start = 0
+	}
+	end := offset + snippetRadius
+	if end > len(text) {
+		end = len(text)
+	}
+	// Move to rune, then word boundaries so the snippet doesn't start or end mid-word
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	if start > 0 {
+		if space := strings.IndexByte(text[start:offset], ' '); space >= 0 {
+			start += space + 1
+		}
+	}
+	if end < len(text) {
+		if space := strings.LastIndexByte(text[offset:end], ' '); space > 0 {
+			end = offset + space
+		}
+	}
+
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	window := text[start:end]
+	var out strings.Builder
+	if start > 0 {
+		out.WriteString("…")
+	}
+	last := 0
+	for _, tok := range tokenize(window) {
+		if !wanted[tok.term] {
+			continue
+		}
+		out.WriteString(html.EscapeString(window[last:tok.start]))
+		out.WriteString(highlightOpen)
+		out.WriteString(html.EscapeString(window[tok.start:tok.end]))
+		out.WriteString(highlightClose)
+		last = tok.end
+	}
+	out.WriteString(html.EscapeString(window[last:]))
+	if end < len(text) {
+		out.WriteString("…")
+	}
+	return out.String()
+}
+
+// token is a normalized term and its byte range in the source text
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into lower-cased letter/digit runs, skipping single letters
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := text[start:end]
+		if utf8.RuneCountInString(word) > 1 || unicode.IsDigit([]rune(word)[0]) {
+			tokens = append(tokens, token{term: strings.ToLower(word), start: start, end: end})
+		}
+		start = -1
+	}
+
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+	return tokens
+}
+
+// uniqueTerms returns the distinct terms of a query
+func uniqueTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, tok := range tokenize(query) {
+		if !seen[tok.term] {
+			seen[tok.term] = true
+			terms = append(terms, tok.term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,125 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func video(id, title, description string) store.VideoEntry {
+	entry := rss.Entry{ID: id, Title: title, Published: time.Now()}
+	entry.MediaGroup.MediaDescription = description
+	return store.VideoEntry{Entry: entry, ChannelID: "UCchannel"}
+}
+
+func TestIndex_SearchRanksAndHighlights(t *testing.T) {
+	index := NewIndex()
+	index.IndexVideo(video("yt:video:a", "Building a compiler in Go", "We write a lexer & parser."))
+	index.IndexVideo(video("yt:video:b", "Cooking pasta", "A compiler of recipes, go to the kitchen."))
+	index.IndexVideo(video("yt:video:c", "Gardening", "Nothing relevant here."))
+
+	hits := index.Search("compiler go", 0)
+	require.Len(t, hits, 2)
+	// The title match outranks the description match
+	assert.Equal(t, "yt:video:a", hits[0].VideoID)
+	assert.Equal(t, "Building a compiler in Go", hits[0].Title)
+	require.NotEmpty(t, hits[0].Matches)
+	assert.Equal(t, FieldTitle, hits[0].Matches[0].Field)
+	assert.Equal(t, "Building a <mark>compiler</mark> in <mark>Go</mark>", hits[0].Matches[0].Snippet)
+	assert.Equal(t, -1, hits[0].Matches[0].StartMs)
+
+	// Snippets are HTML-escaped
+	hits = index.Search("lexer", 0)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "We write a <mark>lexer</mark> &amp; parser.", hits[0].Matches[0].Snippet)
+
+	// Every term must match
+	assert.Empty(t, index.Search("compiler gardening", 0))
+	assert.Empty(t, index.Search("  ", 0))
+
+	// The total counts every match, not just the page returned
+	hits, total := index.SearchWithTotal("compiler", 1)
+	require.Len(t, hits, 1)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "yt:video:a", hits[0].VideoID)
+}
+
+func TestIndex_TranscriptTimestampsAndUpdates(t *testing.T) {
+	index := NewIndex()
+	index.IndexVideo(video("yt:video:a", "Talk", ""))
+	index.IndexTranscript(&store.Transcript{
+		VideoID: "yt:video:a",
+		Cues: []store.TranscriptCue{
+			{StartMs: 0, Text: "welcome everyone"},
+			{StartMs: 61500, Text: "now let me explain monads"},
+		},
+	})
+
+	hits := index.Search("monads", 0)
+	require.Len(t, hits, 1)
+	require.Len(t, hits[0].Matches, 1)
+	assert.Equal(t, FieldTranscript, hits[0].Matches[0].Field)
+	assert.Equal(t, 61500, hits[0].Matches[0].StartMs)
+	assert.True(t, strings.Contains(hits[0].Matches[0].Snippet, "<mark>monads</mark>"))
+
+	// Summaries are searchable and replacing them drops the old terms
+	index.IndexSummary("yt:video:a", "An introduction to functors")
+	assert.Len(t, index.Search("functors", 0), 1)
+	index.IndexSummary("yt:video:a", "An introduction to applicatives")
+	assert.Empty(t, index.Search("functors", 0))
+
+	index.Retain(nil)
+	assert.Empty(t, index.Search("monads", 0))
+	assert.Empty(t, index.postings)
+}
+
+func TestIndexedStore_UpdatesIndexOnWrite(t *testing.T) {
+	db, err := store.NewStore(t.TempDir() + "/test.db")
+	require.NoError(t, err)
+	defer db.Close()
+
+	index := NewIndex()
+	indexed := NewIndexedStore(db, index)
+
+	require.NoError(t, indexed.SaveVideo(video("yt:video:a", "Rust ownership explained", "")))
+	require.NoError(t, indexed.SetSummary(&store.StoredSummary{VideoID: "yt:video:a", Text: "Borrow checker basics"}))
+	require.NoError(t, indexed.SetTranscript(&store.Transcript{VideoID: "yt:video:a", Cues: []store.TranscriptCue{{StartMs: 5000, Text: "lifetimes matter"}}}))
+
+	assert.Len(t, index.Search("ownership", 0), 1)
+	assert.Len(t, index.Search("borrow", 0), 1)
+	assert.Len(t, index.Search("lifetimes", 0), 1)
+
+	// A rebuilt index finds the same content from the store
+	rebuilt := NewIndex()
+	require.NoError(t, rebuilt.Rebuild(db))
+	assert.Len(t, rebuilt.Search("ownership borrow lifetimes", 0), 1)
+}
+
+func TestIndexedStore_IndexesOnlyCataloguedVideos(t *testing.T) {
+	db, err := store.NewStore(t.TempDir() + "/test.db")
+	require.NoError(t, err)
+	defer db.Close()
+
+	index := NewIndex()
+	indexed := NewIndexedStore(db, index)
+
+	// A summary of a video outside the catalogue isn't searchable, as after a rebuild
+	require.NoError(t, indexed.SetSummary(&store.StoredSummary{VideoID: "yt:video:a", Text: "Borrow checker basics"}))
+	require.NoError(t, indexed.SetTranscript(&store.Transcript{VideoID: "yt:video:a", Cues: []store.TranscriptCue{{StartMs: 5000, Text: "lifetimes matter"}}}))
+	assert.Empty(t, index.Search("borrow", 0))
+	assert.Empty(t, index.Search("lifetimes", 0))
+
+	// Once catalogued, the video is indexed with its stored summary and transcript
+	require.NoError(t, indexed.SaveVideo(video("yt:video:a", "Rust ownership explained", "")))
+	assert.Len(t, index.Search("ownership borrow lifetimes", 0), 1)
+
+	rebuilt := NewIndex()
+	require.NoError(t, rebuilt.Rebuild(db))
+	assert.Equal(t, index.docs, rebuilt.docs, "The live index matches a rebuilt one")
+}
@@ -0,0 +1,98 @@
+package search
+
+import (
+	"log"
+
+	"youtube-curator-v2/internal/store"
+)
+
+// indexedStore wraps a Store so every write of a video, summary or transcript also
+// updates the search index. Like Index.Rebuild, only catalogued videos are indexed:
+// summaries and transcripts of other videos are indexed once the video is catalogued.
+type indexedStore struct {
+	store.Store
+	index *Index
+}
+
+// NewIndexedStore returns a Store that keeps index up to date with db
+func NewIndexedStore(db store.Store, index *Index) store.Store {
+	return &indexedStore{Store: db, index: index}
+}
+
+// SaveVideo stores the video and indexes it as merged with any earlier enrichment, along with
+// any transcript stored before it was catalogued
+func (s *indexedStore) SaveVideo(video store.VideoEntry) error {
+	if err := s.Store.SaveVideo(video); err != nil {
+		return err
+	}
+
+	saved, err := s.Store.GetVideo(video.Entry.ID)
+	if err != nil || saved == nil {
+		log.Printf("Warning: Failed to reload video %s for indexing: %v", video.Entry.ID, err)
+		s.index.IndexVideo(video)
+		return nil
+	}
+	s.index.IndexVideo(*saved)
+
+	if !s.index.hasField(video.Entry.ID, FieldTranscript) {
+		transcript, err := s.Store.GetTranscript(video.Entry.ID)
+		if err != nil {
+			log.Printf("Warning: Failed to load transcript of video %s for indexing: %v", video.Entry.ID, err)
+		} else if transcript != nil {
+			s.index.IndexTranscript(transcript)
+		}
+	}
+	return nil
+}
+
+// SetSummary stores the summary and indexes its text if the video is catalogued
+func (s *indexedStore) SetSummary(summary *store.StoredSummary) error {
+	if err := s.Store.SetSummary(summary); err != nil {
+		return err
+	}
+	if s.isCatalogued(summary.VideoID) {
+		s.index.IndexSummary(summary.VideoID, summary.Text)
+	}
+	return nil
+}
+
+// SetTranscript stores the transcript and indexes its cues if the video is catalogued
+func (s *indexedStore) SetTranscript(transcript *store.Transcript) error {
+	if err := s.Store.SetTranscript(transcript); err != nil {
+		return err
+	}
+	if s.isCatalogued(transcript.VideoID) {
+		s.index.IndexTranscript(transcript)
+	}
+	return nil
+}
+
+// isCatalogued reports whether a video is in the catalogue, and so belongs in the index
+func (s *indexedStore) isCatalogued(videoID string) bool {
+	video, err := s.Store.GetVideo(videoID)
+	if err != nil {
+		log.Printf("Warning: Failed to look up video %s for indexing: %v", videoID, err)
+		return false
+	}
+	return video != nil
+}
+
+// PruneVideos removes expired videos and drops them, with their summaries and transcripts, from the index
+func (s *indexedStore) PruneVideos(retention store.VideoRetention) (int, error) {
+	removed, err := s.Store.PruneVideos(retention)
+	if err != nil || removed == 0 {
+		return removed, err
+	}
+
+	videos, err := s.Store.GetVideos()
+	if err != nil {
+		log.Printf("Warning: Failed to refresh search index after pruning: %v", err)
+		return removed, nil
+	}
+	videoIDs := make([]string, len(videos))
+	for i, video := range videos {
+		videoIDs[i] = video.Entry.ID
+	}
+	s.index.Retain(videoIDs)
+	return removed, nil
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// jobKeyPrefix prefixes background job keys, which are followed by the job ID
+const jobKeyPrefix = "job:"
+
+// JobStatus represents the lifecycle state of a background job
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// JobTypeSummary identifies jobs that generate a video summary
+const JobTypeSummary = "summary"
+
+// Job holds the persisted state of a background job
+type Job struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	VideoID    string     `json:"videoId"`    // Full video ID (yt:video:ID)
+	Regenerate bool       `json:"regenerate"` // Whether a stored result should be replaced
+	Status     JobStatus  `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+func jobKey(jobID string) []byte {
+	return []byte(jobKeyPrefix + jobID)
+}
+
+// GetJob retrieves a job by ID, returning nil if it doesn't exist
+func (s *BadgerStore) GetJob(jobID string) (*Job, error) {
+	var job *Job
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(jobKey(jobID))
+		if err == badger.ErrKeyNotFound {
+			return nil // Unknown job
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get job %s: %w", jobID, err)
+		}
+		return item.Value(func(val []byte) error {
+			job = &Job{}
+			return json.Unmarshal(val, job)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// SaveJob creates or updates a job
+func (s *BadgerStore) SaveJob(job *Job) error {
+	if job == nil || job.ID == "" {
+		return fmt.Errorf("job must have an ID")
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		jobBytes, err := json.Marshal(job)
+		if err != nil {
+			return fmt.Errorf("failed to marshal job: %w", err)
+		}
+		return txn.Set(jobKey(job.ID), jobBytes)
+	})
+}
+
+// GetJobsByStatus retrieves all jobs in any of the given statuses, ordered by creation time
+func (s *BadgerStore) GetJobsByStatus(statuses ...JobStatus) ([]Job, error) {
+	wanted := make(map[JobStatus]bool, len(statuses))
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+
+	var jobs []Job
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(jobKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var job Job
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &job)
+			}); err != nil {
+				return fmt.Errorf("failed to read job: %w", err)
+			}
+			if wanted[job.Status] {
+				jobs = append(jobs, job)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first so resumed jobs keep their submission order
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBadgerStore_JobPersistence(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	job, err := db.GetJob("missing")
+	if err != nil {
+		t.Fatalf("Unexpected error getting missing job: %v", err)
+	}
+	if job != nil {
+		t.Fatalf("Expected nil job for unknown ID, got %+v", job)
+	}
+
+	now := time.Now()
+	jobs := []*Job{
+		{ID: "b", Type: JobTypeSummary, VideoID: "yt:video:bbbbbbbbbbb", Status: JobStatusRunning, CreatedAt: now.Add(-time.Minute)},
+		{ID: "a", Type: JobTypeSummary, VideoID: "yt:video:aaaaaaaaaaa", Status: JobStatusQueued, CreatedAt: now.Add(-2 * time.Minute)},
+		{ID: "c", Type: JobTypeSummary, VideoID: "yt:video:ccccccccccc", Status: JobStatusSucceeded, CreatedAt: now},
+	}
+	for _, j := range jobs {
+		if err := db.SaveJob(j); err != nil {
+			t.Fatalf("Failed to save job %s: %v", j.ID, err)
+		}
+	}
+
+	job, err = db.GetJob("b")
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if job == nil || job.VideoID != "yt:video:bbbbbbbbbbb" || job.Status != JobStatusRunning {
+		t.Fatalf("Unexpected job: %+v", job)
+	}
+
+	unfinished, err := db.GetJobsByStatus(JobStatusQueued, JobStatusRunning)
+	if err != nil {
+		t.Fatalf("Failed to list jobs: %v", err)
+	}
+	if len(unfinished) != 2 {
+		t.Fatalf("Expected 2 unfinished jobs, got %d", len(unfinished))
+	}
+	if unfinished[0].ID != "a" || unfinished[1].ID != "b" {
+		t.Errorf("Expected jobs ordered oldest first, got %s, %s", unfinished[0].ID, unfinished[1].ID)
+	}
+}
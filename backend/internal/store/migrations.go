@@ -0,0 +1,156 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// schemaVersionKey holds the schema version the database was last migrated to
+const schemaVersionKey = "schema_version"
+
+// ErrNewerSchema is returned when the database was written by a newer version of the application
+var ErrNewerSchema = errors.New("database schema is newer than this version supports")
+
+// migration upgrades the database to version. Migrations must be idempotent, since a
+// migration interrupted before its version is recorded runs again on the next start.
+type migration struct {
+	version     int
+	description string
+	apply       func(s *BadgerStore) error
+}
+
+// migrations lists every schema migration in the order they are applied.
+// Append new migrations with the next version number; never reorder or remove entries.
+var migrations = []migration{
+	{version: 1, description: "per-record channel and watched video keys", apply: (*BadgerStore).migrateLegacyBlobs},
+	{version: 2, description: "separate last checked video ID and timestamp keys", apply: (*BadgerStore).migrateLastCheckedKeys},
+}
+
+// CurrentSchemaVersion returns the schema version this version of the application writes
+func CurrentSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// GetSchemaVersion returns the schema version recorded in the database, or 0 if none is recorded
+func (s *BadgerStore) GetSchemaVersion() (int, error) {
+	version := 0
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(schemaVersionKey))
+		if err == badger.ErrKeyNotFound {
+			return nil // Database predates schema versioning
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get schema version: %w", err)
+		}
+		return item.Value(func(val []byte) error {
+			parsed, err := strconv.Atoi(string(val))
+			if err != nil {
+				return fmt.Errorf("failed to parse schema version: %w", err)
+			}
+			version = parsed
+			return nil
+		})
+	})
+	return version, err
+}
+
+// setSchemaVersion records the schema version the database has been migrated to
+func (s *BadgerStore) setSchemaVersion(version int) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(schemaVersionKey), []byte(strconv.Itoa(version)))
+	})
+}
+
+// migrate applies all migrations newer than the recorded schema version, in order
+func (s *BadgerStore) migrate() error {
+	version, err := s.GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version > CurrentSchemaVersion() {
+		return fmt.Errorf("%w: database is at version %d, latest supported is %d", ErrNewerSchema, version, CurrentSchemaVersion())
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		log.Printf("Migrating database to schema version %d: %s", m.version, m.description)
+		if err := m.apply(s); err != nil {
+			return fmt.Errorf("migration to version %d failed: %w", m.version, err)
+		}
+		if err := s.setSchemaVersion(m.version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyLastCheckedKey matches the keys last checked state used to be stored under: a bare channel ID
+var legacyLastCheckedKey = regexp.MustCompile(`^UC[a-zA-Z0-9_-]{22}$`)
+
+// migrateLastCheckedKeys moves last checked state from the bare channel ID key, which
+// SetLastCheckedVideoID and SetLastCheckedTimestamp used to share, to separate prefixed keys.
+// The legacy keys are collected first and rewritten in a batch, so the migration isn't bound
+// by the size of a single transaction.
+func (s *BadgerStore) migrateLastCheckedKeys() error {
+	type legacyEntry struct {
+		channelID string
+		val       []byte
+	}
+	var entries []legacyEntry
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := string(it.Item().Key())
+			if !legacyLastCheckedKey.MatchString(key) {
+				continue
+			}
+			val, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("failed to read key %s: %w", key, err)
+			}
+			entries = append(entries, legacyEntry{channelID: key, val: val})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find legacy last checked keys: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	batch := s.db.NewWriteBatch()
+	defer batch.Cancel()
+	for _, entry := range entries {
+		// Whichever setter ran last decides what the shared key holds
+		key := lastCheckedVideoKey(entry.channelID)
+		var timestamp time.Time
+		if err := timestamp.UnmarshalBinary(entry.val); err == nil {
+			key = lastCheckedTimestampKey(entry.channelID)
+		}
+		if err := batch.Set(key, entry.val); err != nil {
+			return fmt.Errorf("failed to migrate last checked state for channel %s: %w", entry.channelID, err)
+		}
+		if err := batch.Delete([]byte(entry.channelID)); err != nil {
+			return fmt.Errorf("failed to remove legacy last checked key %s: %w", entry.channelID, err)
+		}
+	}
+	if err := batch.Flush(); err != nil {
+		return fmt.Errorf("failed to migrate last checked keys: %w", err)
+	}
+
+	log.Printf("Migrated last checked state for %d channels", len(entries))
+	return nil
+}
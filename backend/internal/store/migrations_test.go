@@ -0,0 +1,144 @@
+package store
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// writeRaw writes keys directly to a closed database at dbPath, bypassing migrations
+func writeRaw(t *testing.T, dbPath string, values map[string][]byte) {
+	t.Helper()
+	raw, err := badger.Open(badger.DefaultOptions(dbPath).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("Failed to open raw database: %v", err)
+	}
+	defer raw.Close()
+	err = raw.Update(func(txn *badger.Txn) error {
+		for key, val := range values {
+			if err := txn.Set([]byte(key), val); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to write raw keys: %v", err)
+	}
+}
+
+func TestNewStore_RecordsSchemaVersion(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	version, err := db.(*BadgerStore).GetSchemaVersion()
+	if err != nil {
+		t.Fatalf("Failed to get schema version: %v", err)
+	}
+	if version != CurrentSchemaVersion() {
+		t.Errorf("Expected schema version %d, got %d", CurrentSchemaVersion(), version)
+	}
+}
+
+func TestNewStore_RefusesNewerSchema(t *testing.T) {
+	dbPath := t.TempDir() + "/test.db"
+	writeRaw(t, dbPath, map[string][]byte{
+		schemaVersionKey: []byte(strconv.Itoa(CurrentSchemaVersion() + 1)),
+	})
+
+	db, err := NewStore(dbPath)
+	if err == nil {
+		db.Close()
+		t.Fatal("Expected error opening database with a newer schema version")
+	}
+	if !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("Expected ErrNewerSchema, got %v", err)
+	}
+}
+
+func TestMigrations_AreOrdered(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("Expected migration %d to have version %d, got %d", i, i+1, m.version)
+		}
+	}
+}
+
+func TestLastCheckedKeys_DoNotCollide(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	channelID := "UCuAXFkgsw1L7xaCfnd5JJOw"
+	timestamp := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	if err := db.SetLastCheckedVideoID(channelID, "yt:video:dQw4w9WgXcQ"); err != nil {
+		t.Fatalf("Failed to set last checked video ID: %v", err)
+	}
+	if err := db.SetLastCheckedTimestamp(channelID, timestamp); err != nil {
+		t.Fatalf("Failed to set last checked timestamp: %v", err)
+	}
+
+	videoID, err := db.GetLastCheckedVideoID(channelID)
+	if err != nil || videoID != "yt:video:dQw4w9WgXcQ" {
+		t.Errorf("Expected last checked video ID to survive, got %q (err %v)", videoID, err)
+	}
+	got, err := db.GetLastCheckedTimestamp(channelID)
+	if err != nil || !got.Equal(timestamp) {
+		t.Errorf("Expected last checked timestamp %v, got %v (err %v)", timestamp, got, err)
+	}
+}
+
+func TestMigrateLastCheckedKeys(t *testing.T) {
+	dbPath := t.TempDir() + "/test.db"
+	timestamp := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	timestampBytes, _ := timestamp.MarshalBinary()
+	writeRaw(t, dbPath, map[string][]byte{
+		"UCtimestampchannel000000": timestampBytes,
+		"UCvideochannel0000000000": []byte("yt:video:dQw4w9WgXcQ"),
+		checkIntervalKey:           []byte("2h0m0s"),
+		"UCnotachannel":            []byte("left alone"),
+	})
+
+	db, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	got, err := db.GetLastCheckedTimestamp("UCtimestampchannel000000")
+	if err != nil || !got.Equal(timestamp) {
+		t.Errorf("Expected migrated timestamp %v, got %v (err %v)", timestamp, got, err)
+	}
+	videoID, err := db.GetLastCheckedVideoID("UCvideochannel0000000000")
+	if err != nil || videoID != "yt:video:dQw4w9WgXcQ" {
+		t.Errorf("Expected migrated video ID, got %q (err %v)", videoID, err)
+	}
+	interval, err := db.GetCheckInterval()
+	if err != nil || interval != 2*time.Hour {
+		t.Errorf("Expected config keys to be left alone, got %v (err %v)", interval, err)
+	}
+
+	err = db.(*BadgerStore).db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte("UCtimestampchannel000000"))
+		return err
+	})
+	if err != badger.ErrKeyNotFound {
+		t.Errorf("Expected legacy last checked key to be removed, got %v", err)
+	}
+
+	err = db.(*BadgerStore).db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte("UCnotachannel"))
+		return err
+	})
+	if err != nil {
+		t.Errorf("Expected keys that aren't channel IDs to be left alone, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// outboxKeyPrefix prefixes newsletter outbox keys, which are followed by the message ID. Message
+// IDs start with the hex creation time so keys sort in the order the messages were enqueued.
+const outboxKeyPrefix = "outbox:"
+
+// OutboxHistoryLimit is the number of sent outbox messages kept when the outbox is pruned
+const OutboxHistoryLimit = 500
+
+// OutboxStatus represents the delivery state of an outbox message
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending" // Waiting to be delivered
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusDead    OutboxStatus = "dead" // Delivery gave up; can be resent manually
+)
+
+// OutboxMessage is a rendered newsletter email waiting for, or done with, delivery. The channels'
+// last checked timestamps are only advanced once the message is sent or dead-lettered, so the
+// videos it lists aren't lost if delivery fails part way.
+type OutboxMessage struct {
+	ID        string               `json:"id"`
+	RunID     string               `json:"runId,omitempty"` // Newsletter run that enqueued the message
+	Recipient string               `json:"recipient"`
+	Subject   string               `json:"subject"`
+	HTML      string               `json:"html"`
+	Text      string               `json:"text"`
+	Videos    int                  `json:"videos"`             // Videos listed in the email
+	Items     []DigestItem         `json:"items,omitempty"`    // The listed videos, for notification channels that take structured payloads
+	Target    *NotifyTarget        `json:"target,omitempty"`   // Notification channel the message goes out through; email to Recipient when not set
+	Channels  map[string]time.Time `json:"channels,omitempty"` // Last checked timestamp per channel to commit once the message is resolved
+	Status    OutboxStatus         `json:"status"`
+	Attempts  int                  `json:"attempts"`
+	Error     string               `json:"error,omitempty"` // Last delivery error
+	CreatedAt time.Time            `json:"createdAt"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+	SentAt    *time.Time           `json:"sentAt,omitempty"`
+}
+
+// DigestItem is a video listed in a newsletter digest
+type DigestItem struct {
+	ChannelID    string    `json:"channelId"`
+	ChannelTitle string    `json:"channelTitle"`
+	VideoID      string    `json:"videoId"`
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	Published    time.Time `json:"published"`
+	Summary      string    `json:"summary,omitempty"`
+}
+
+// NewOutboxMessage creates a pending outbox message enqueued now
+func NewOutboxMessage(runID, recipient, subject, html, text string) (*OutboxMessage, error) {
+	now := time.Now()
+	id, err := newTimeOrderedID(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate outbox message ID: %w", err)
+	}
+	return &OutboxMessage{
+		ID:        id,
+		RunID:     runID,
+		Recipient: recipient,
+		Subject:   subject,
+		HTML:      html,
+		Text:      text,
+		Status:    OutboxStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func outboxKey(messageID string) []byte {
+	return []byte(outboxKeyPrefix + messageID)
+}
+
+// GetOutboxMessage retrieves an outbox message by ID, returning nil if it doesn't exist
+func (s *BadgerStore) GetOutboxMessage(messageID string) (*OutboxMessage, error) {
+	var message *OutboxMessage
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(outboxKey(messageID))
+		if err == badger.ErrKeyNotFound {
+			return nil // Unknown message
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get outbox message %s: %w", messageID, err)
+		}
+		return item.Value(func(val []byte) error {
+			message = &OutboxMessage{}
+			return json.Unmarshal(val, message)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// GetOutboxMessages retrieves the outbox messages in any of the given statuses, or all of them
+// when no status is given, oldest first
+func (s *BadgerStore) GetOutboxMessages(statuses ...OutboxStatus) ([]OutboxMessage, error) {
+	wanted := make(map[OutboxStatus]bool, len(statuses))
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+
+	var messages []OutboxMessage
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(outboxKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var message OutboxMessage
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &message)
+			}); err != nil {
+				return fmt.Errorf("failed to read outbox message: %w", err)
+			}
+			if len(wanted) == 0 || wanted[message.Status] {
+				messages = append(messages, message)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// SaveOutboxMessage creates or updates an outbox message
+func (s *BadgerStore) SaveOutboxMessage(message *OutboxMessage) error {
+	if message == nil || message.ID == "" {
+		return fmt.Errorf("outbox message must have an ID")
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		messageBytes, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox message: %w", err)
+		}
+		return txn.Set(outboxKey(message.ID), messageBytes)
+	})
+}
+
+// PruneOutbox removes all but the most recent keep sent messages, returning how many were removed.
+// Pending and dead-lettered messages are never removed.
+func (s *BadgerStore) PruneOutbox(keep int) (int, error) {
+	var stale [][]byte
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(outboxKeyPrefix)
+		opts.Reverse = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		sent := 0
+		for it.Seek(append([]byte(outboxKeyPrefix), 0xFF)); it.Valid(); it.Next() {
+			var message OutboxMessage
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &message)
+			}); err != nil {
+				return fmt.Errorf("failed to read outbox message: %w", err)
+			}
+			if message.Status != OutboxStatusSent {
+				continue
+			}
+			sent++
+			if sent > keep {
+				stale = append(stale, it.Item().KeyCopy(nil))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list outbox messages: %w", err)
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, key := range stale {
+		if err := wb.Delete(key); err != nil {
+			return 0, fmt.Errorf("failed to delete outbox message: %w", err)
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to delete outbox messages: %w", err)
+	}
+	return len(stale), nil
+}
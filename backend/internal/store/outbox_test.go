@@ -0,0 +1,115 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBadgerStore_OutboxPersistence(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	message, err := db.GetOutboxMessage("missing")
+	if err != nil {
+		t.Fatalf("Unexpected error getting missing outbox message: %v", err)
+	}
+	if message != nil {
+		t.Fatalf("Expected nil outbox message for unknown ID, got %+v", message)
+	}
+
+	published := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	var ids []string
+	for _, recipient := range []string{"one@example.com", "two@example.com", "three@example.com"} {
+		message, err := NewOutboxMessage("run-1", recipient, "New videos", "<p>hi</p>", "hi")
+		if err != nil {
+			t.Fatalf("Failed to create outbox message: %v", err)
+		}
+		message.Channels = map[string]time.Time{"UCchannel": published}
+		if err := db.SaveOutboxMessage(message); err != nil {
+			t.Fatalf("Failed to save outbox message: %v", err)
+		}
+		ids = append(ids, message.ID)
+		time.Sleep(time.Millisecond) // Distinct creation times
+	}
+
+	// Resolve the first two messages
+	first, err := db.GetOutboxMessage(ids[0])
+	if err != nil || first == nil {
+		t.Fatalf("Failed to get outbox message: %v", err)
+	}
+	if first.Status != OutboxStatusPending || !first.Channels["UCchannel"].Equal(published) {
+		t.Errorf("Expected a pending message holding the channel timestamp, got %+v", first)
+	}
+	first.Status = OutboxStatusSent
+	second := *first
+	second.ID = ids[1]
+	second.Status = OutboxStatusDead
+	second.Error = "timeout"
+	for _, message := range []*OutboxMessage{first, &second} {
+		if err := db.SaveOutboxMessage(message); err != nil {
+			t.Fatalf("Failed to save outbox message: %v", err)
+		}
+	}
+
+	all, err := db.GetOutboxMessages()
+	if err != nil {
+		t.Fatalf("Failed to list outbox messages: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != ids[0] || all[2].ID != ids[2] {
+		t.Fatalf("Expected all messages oldest first, got %+v", all)
+	}
+	unresolved, err := db.GetOutboxMessages(OutboxStatusPending, OutboxStatusDead)
+	if err != nil {
+		t.Fatalf("Failed to list outbox messages: %v", err)
+	}
+	if len(unresolved) != 2 || unresolved[0].ID != ids[1] || unresolved[1].ID != ids[2] {
+		t.Errorf("Expected the dead and pending messages, got %+v", unresolved)
+	}
+
+	if err := db.SaveOutboxMessage(&OutboxMessage{}); err == nil {
+		t.Error("Expected an error saving an outbox message without an ID")
+	}
+}
+
+func TestBadgerStore_PruneOutbox(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	statuses := []OutboxStatus{OutboxStatusSent, OutboxStatusDead, OutboxStatusSent, OutboxStatusPending, OutboxStatusSent}
+	var ids []string
+	for _, status := range statuses {
+		message, err := NewOutboxMessage("", "me@example.com", "New videos", "", "")
+		if err != nil {
+			t.Fatalf("Failed to create outbox message: %v", err)
+		}
+		message.Status = status
+		if err := db.SaveOutboxMessage(message); err != nil {
+			t.Fatalf("Failed to save outbox message: %v", err)
+		}
+		ids = append(ids, message.ID)
+		time.Sleep(time.Millisecond)
+	}
+
+	removed, err := db.PruneOutbox(1)
+	if err != nil {
+		t.Fatalf("Failed to prune outbox: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 sent messages to be removed, got %d", removed)
+	}
+
+	// Only the newest sent message survives, alongside every unresolved one
+	messages, err := db.GetOutboxMessages()
+	if err != nil {
+		t.Fatalf("Failed to list outbox messages: %v", err)
+	}
+	if len(messages) != 3 || messages[0].ID != ids[1] || messages[1].ID != ids[3] || messages[2].ID != ids[4] {
+		t.Errorf("Unexpected outbox after pruning: %+v", messages)
+	}
+}
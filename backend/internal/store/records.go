@@ -0,0 +1,138 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// Legacy keys that held every channel and watched video ID in a single JSON list
+const (
+	legacyChannelsKey      = "channels"
+	legacyWatchedVideosKey = "watched_videos"
+)
+
+// channelRecord is the value stored under a channel/<id> key
+type channelRecord struct {
+	Channel
+	AddedAt time.Time `json:"addedAt"` // Used to keep channels in the order they were added
+}
+
+func channelKey(channelID string) []byte {
+	return []byte(channelKeyPrefix + channelID)
+}
+
+func watchedKey(videoID string) []byte {
+	return []byte(watchedKeyPrefix + videoID)
+}
+
+func lastCheckedVideoKey(channelID string) []byte {
+	return []byte(lastCheckedVideoKeyPrefix + channelID)
+}
+
+func lastCheckedTimestampKey(channelID string) []byte {
+	return []byte(lastCheckedTimestampKeyPrefix + channelID)
+}
+
+// setChannelRecord writes a channel record within an update transaction
+func setChannelRecord(txn *badger.Txn, record channelRecord) error {
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal channel: %w", err)
+	}
+	return txn.Set(channelKey(record.ID), recordBytes)
+}
+
+// setWatchedRecord writes a watched marker, holding the time it was marked, within an update transaction
+func setWatchedRecord(txn *badger.Txn, videoID string, watchedAt time.Time) error {
+	val, err := watchedAt.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal watched time: %w", err)
+	}
+	return txn.Set(watchedKey(videoID), val)
+}
+
+// migrateLegacyBlobs moves channels and watched videos from the legacy list keys to one key per record.
+// The legacy keys are deleted once converted, so re-running the migration is a no-op.
+func (s *BadgerStore) migrateLegacyBlobs() error {
+	var channels []Channel
+	found, err := s.readLegacyList(legacyChannelsKey, &channels)
+	if err != nil {
+		return err
+	}
+	if found {
+		// Spread AddedAt so the legacy list order is kept
+		base := time.Now()
+		err := s.db.Update(func(txn *badger.Txn) error {
+			for i, channel := range channels {
+				if _, err := txn.Get(channelKey(channel.ID)); err == nil {
+					continue // Already migrated
+				}
+				record := channelRecord{Channel: channel, AddedAt: base.Add(time.Duration(i))}
+				if err := setChannelRecord(txn, record); err != nil {
+					return err
+				}
+			}
+			return txn.Delete([]byte(legacyChannelsKey))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate channels: %w", err)
+		}
+		log.Printf("Migrated %d channels to per-channel keys", len(channels))
+	}
+
+	var watchedVideos []string
+	found, err = s.readLegacyList(legacyWatchedVideosKey, &watchedVideos)
+	if err != nil {
+		return err
+	}
+	if found {
+		// Badger limits the size of a transaction, so large histories are written in batches
+		wb := s.db.NewWriteBatch()
+		defer wb.Cancel()
+		watchedAt, err := time.Now().MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to marshal watched time: %w", err)
+		}
+		for _, videoID := range watchedVideos {
+			if err := wb.Set(watchedKey(videoID), watchedAt); err != nil {
+				return fmt.Errorf("failed to migrate watched videos: %w", err)
+			}
+		}
+		// Deleted last so an interrupted migration is retried on the next start
+		if err := wb.Delete([]byte(legacyWatchedVideosKey)); err != nil {
+			return fmt.Errorf("failed to migrate watched videos: %w", err)
+		}
+		if err := wb.Flush(); err != nil {
+			return fmt.Errorf("failed to migrate watched videos: %w", err)
+		}
+		log.Printf("Migrated %d watched videos to per-video keys", len(watchedVideos))
+	}
+
+	return nil
+}
+
+// readLegacyList decodes a legacy JSON list key into v, reporting whether the key exists
+func (s *BadgerStore) readLegacyList(key string, v interface{}) (bool, error) {
+	found := false
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get legacy key %s: %w", key, err)
+		}
+		found = true
+		return item.Value(func(val []byte) error {
+			if len(val) == 0 {
+				return nil
+			}
+			return json.Unmarshal(val, v)
+		})
+	})
+	return found, err
+}
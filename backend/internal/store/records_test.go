@@ -0,0 +1,155 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+func TestBadgerStore_ChannelsAndWatchedRecords(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	for _, channel := range []Channel{{ID: "UCzzz", Title: "Last ID, added first"}, {ID: "UCaaa", Title: "First ID, added second"}} {
+		if err := db.AddChannel(channel); err != nil {
+			t.Fatalf("Failed to add channel: %v", err)
+		}
+	}
+	// Adding an existing channel is a no-op
+	if err := db.AddChannel(Channel{ID: "UCzzz", Title: "Duplicate"}); err != nil {
+		t.Fatalf("Failed to add duplicate channel: %v", err)
+	}
+
+	channels, err := db.GetChannels()
+	if err != nil {
+		t.Fatalf("Failed to get channels: %v", err)
+	}
+	if len(channels) != 2 || channels[0].ID != "UCzzz" || channels[1].ID != "UCaaa" {
+		t.Fatalf("Expected channels in insertion order, got %+v", channels)
+	}
+	if channels[0].Title != "Last ID, added first" {
+		t.Errorf("Expected duplicate add to keep the original title, got %q", channels[0].Title)
+	}
+
+	if err := db.RemoveChannel("UCzzz"); err != nil {
+		t.Fatalf("Failed to remove channel: %v", err)
+	}
+	channels, err = db.GetChannels()
+	if err != nil {
+		t.Fatalf("Failed to get channels: %v", err)
+	}
+	if len(channels) != 1 || channels[0].ID != "UCaaa" {
+		t.Fatalf("Expected only UCaaa to remain, got %+v", channels)
+	}
+
+	if err := db.SetVideoWatched("yt:video:abc"); err != nil {
+		t.Fatalf("Failed to mark video as watched: %v", err)
+	}
+	watched, err := db.IsVideoWatched("yt:video:abc")
+	if err != nil || !watched {
+		t.Fatalf("Expected video to be watched, got %v (err %v)", watched, err)
+	}
+	watched, err = db.IsVideoWatched("yt:video:other")
+	if err != nil || watched {
+		t.Fatalf("Expected other video to be unwatched, got %v (err %v)", watched, err)
+	}
+}
+
+func TestBadgerStore_MigratesLegacyBlobs(t *testing.T) {
+	dbPath := t.TempDir() + "/test.db"
+
+	// Write a database in the legacy single-key layout
+	raw, err := badger.Open(badger.DefaultOptions(dbPath).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("Failed to open raw database: %v", err)
+	}
+	legacyChannels, _ := json.Marshal([]Channel{{ID: "UCzzz", Title: "Zed"}, {ID: "UCaaa", Title: "Ay"}})
+	legacyWatched, _ := json.Marshal([]string{"yt:video:one", "yt:video:two"})
+	err = raw.Update(func(txn *badger.Txn) error {
+		if err := txn.Set([]byte(legacyChannelsKey), legacyChannels); err != nil {
+			return err
+		}
+		return txn.Set([]byte(legacyWatchedVideosKey), legacyWatched)
+	})
+	if err != nil {
+		t.Fatalf("Failed to write legacy keys: %v", err)
+	}
+	raw.Close()
+
+	// Opening twice checks the migration only runs once and keeps the data intact
+	for i := 0; i < 2; i++ {
+		db, err := NewStore(dbPath)
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+
+		channels, err := db.GetChannels()
+		if err != nil {
+			t.Fatalf("Failed to get channels: %v", err)
+		}
+		if len(channels) != 2 || channels[0].ID != "UCzzz" || channels[1].ID != "UCaaa" {
+			t.Fatalf("Expected legacy channels in original order, got %+v", channels)
+		}
+
+		watchedVideos, err := db.GetWatchedVideos()
+		if err != nil {
+			t.Fatalf("Failed to get watched videos: %v", err)
+		}
+		if len(watchedVideos) != 2 {
+			t.Fatalf("Expected 2 watched videos, got %v", watchedVideos)
+		}
+		for _, videoID := range []string{"yt:video:one", "yt:video:two"} {
+			watched, err := db.IsVideoWatched(videoID)
+			if err != nil || !watched {
+				t.Fatalf("Expected %s to be watched after migration, got %v (err %v)", videoID, watched, err)
+			}
+		}
+
+		found, err := db.(*BadgerStore).readLegacyList(legacyChannelsKey, &[]Channel{})
+		if err != nil || found {
+			t.Fatalf("Expected legacy channels key to be removed, found %v (err %v)", found, err)
+		}
+		db.Close()
+	}
+}
+
+func TestBadgerStore_CheckIntervalFromEarlierVersion(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if interval, err := db.GetCheckInterval(); err != nil || interval != time.Hour {
+		t.Errorf("Expected the 1h default without a stored interval, got %v (err %v)", interval, err)
+	}
+
+	// Earlier versions stored the interval without scheduling by it
+	err = db.(*BadgerStore).db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(checkIntervalKey), []byte("2h0m0s"))
+	})
+	if err != nil {
+		t.Fatalf("Failed to seed legacy interval: %v", err)
+	}
+	if interval, err := db.GetCheckInterval(); err != nil || interval != 2*time.Hour {
+		t.Errorf("Expected the stored interval, got %v (err %v)", interval, err)
+	}
+	if interval, err := db.GetScheduleInterval(); err != nil || interval != 0 {
+		t.Errorf("Expected a legacy interval not to drive the schedule, got %v (err %v)", interval, err)
+	}
+
+	if err := db.SetCheckInterval(30 * time.Minute); err != nil {
+		t.Fatalf("Failed to set check interval: %v", err)
+	}
+	if interval, err := db.GetCheckInterval(); err != nil || interval != 30*time.Minute {
+		t.Errorf("Expected the new interval, got %v (err %v)", interval, err)
+	}
+	if interval, err := db.GetScheduleInterval(); err != nil || interval != 30*time.Minute {
+		t.Errorf("Expected a newly set interval to drive the schedule, got %v (err %v)", interval, err)
+	}
+}
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// runKeyPrefix prefixes newsletter run keys, which are followed by the run ID. Run IDs
+// start with the hex start time so keys sort in the order the runs started.
+const runKeyPrefix = "run:"
+
+// RunHistoryLimit is the number of newsletter runs kept when the history is pruned
+const RunHistoryLimit = 500
+
+// RunTrigger identifies what started a newsletter run
+type RunTrigger string
+
+const (
+	RunTriggerScheduled RunTrigger = "scheduled"
+	RunTriggerManual    RunTrigger = "manual"
+)
+
+// RunStatus represents the outcome of a newsletter run
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// EmailStatus represents the delivery outcome of a newsletter email
+type EmailStatus string
+
+const (
+	EmailStatusSent    EmailStatus = "sent"
+	EmailStatusFailed  EmailStatus = "failed"  // Dead-lettered, or could not be queued
+	EmailStatusSkipped EmailStatus = "skipped" // Nothing new for the recipient
+	EmailStatusPending EmailStatus = "pending" // Left in the outbox when the run was interrupted; a later delivery sends it
+)
+
+// Run records a newsletter run: the channels checked, the videos found and the emails sent
+type Run struct {
+	ID             string       `json:"id"`
+	Trigger        RunTrigger   `json:"trigger"`
+	Status         RunStatus    `json:"status"`
+	Error          string       `json:"error,omitempty"` // Why the run failed
+	StartedAt      time.Time    `json:"startedAt"`
+	FinishedAt     *time.Time   `json:"finishedAt,omitempty"`
+	Channels       []RunChannel `json:"channels,omitempty"`
+	NewVideosFound int          `json:"newVideosFound"`
+	Emails         []RunEmail   `json:"emails,omitempty"`
+}
+
+// RunChannel records the outcome of checking one channel during a run
+type RunChannel struct {
+	ChannelID    string `json:"channelId"`
+	ChannelTitle string `json:"channelTitle,omitempty"`
+	NewVideos    int    `json:"newVideos"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RunEmail records the delivery of one newsletter email during a run
+type RunEmail struct {
+	Recipient string      `json:"recipient"`
+	Videos    int         `json:"videos"` // Videos listed in the email
+	Status    EmailStatus `json:"status"`
+	Error     string      `json:"error,omitempty"`
+	OutboxID  string      `json:"outboxId,omitempty"` // Outbox message the email was sent from
+}
+
+// NewRun creates a running newsletter run started now
+func NewRun(trigger RunTrigger) (*Run, error) {
+	now := time.Now()
+	id, err := newTimeOrderedID(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate run ID: %w", err)
+	}
+	return &Run{
+		ID:        id,
+		Trigger:   trigger,
+		Status:    RunStatusRunning,
+		StartedAt: now,
+	}, nil
+}
+
+// newTimeOrderedID generates a random ID that starts with the hex timestamp t, so keys built
+// from these IDs sort in creation order
+func newTimeOrderedID(t time.Time) (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016x%s", t.UnixNano(), hex.EncodeToString(b)), nil
+}
+
+// Finish marks the run as finished now, failed if err is not nil
+func (r *Run) Finish(err error) {
+	now := time.Now()
+	r.FinishedAt = &now
+	r.Status = RunStatusSucceeded
+	if err != nil {
+		r.Status = RunStatusFailed
+		r.Error = err.Error()
+	}
+}
+
+func runKey(runID string) []byte {
+	return []byte(runKeyPrefix + runID)
+}
+
+// GetRun retrieves a newsletter run by ID, returning nil if it doesn't exist
+func (s *BadgerStore) GetRun(runID string) (*Run, error) {
+	var run *Run
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(runKey(runID))
+		if err == badger.ErrKeyNotFound {
+			return nil // Unknown run
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get run %s: %w", runID, err)
+		}
+		return item.Value(func(val []byte) error {
+			run = &Run{}
+			return json.Unmarshal(val, run)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// GetRuns retrieves the most recent newsletter runs, newest first. A limit of 0 returns all of them.
+func (s *BadgerStore) GetRuns(limit int) ([]Run, error) {
+	var runs []Run
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(runKeyPrefix)
+		opts.Reverse = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(append([]byte(runKeyPrefix), 0xFF)); it.Valid(); it.Next() {
+			if limit > 0 && len(runs) >= limit {
+				break
+			}
+			var run Run
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &run)
+			}); err != nil {
+				return fmt.Errorf("failed to read run: %w", err)
+			}
+			runs = append(runs, run)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// SaveRun creates or updates a newsletter run
+func (s *BadgerStore) SaveRun(run *Run) error {
+	if run == nil || run.ID == "" {
+		return fmt.Errorf("run must have an ID")
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		runBytes, err := json.Marshal(run)
+		if err != nil {
+			return fmt.Errorf("failed to marshal run: %w", err)
+		}
+		return txn.Set(runKey(run.ID), runBytes)
+	})
+}
+
+// PruneRuns removes all but the most recent keep newsletter runs, returning how many were removed
+func (s *BadgerStore) PruneRuns(keep int) (int, error) {
+	var stale [][]byte
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(runKeyPrefix)
+		opts.Reverse = true
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		seen := 0
+		for it.Seek(append([]byte(runKeyPrefix), 0xFF)); it.Valid(); it.Next() {
+			seen++
+			if seen > keep {
+				stale = append(stale, it.Item().KeyCopy(nil))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list runs: %w", err)
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, key := range stale {
+		if err := wb.Delete(key); err != nil {
+			return 0, fmt.Errorf("failed to delete run: %w", err)
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to delete runs: %w", err)
+	}
+	return len(stale), nil
+}
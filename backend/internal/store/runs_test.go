@@ -0,0 +1,79 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBadgerStore_RunPersistence(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	run, err := db.GetRun("missing")
+	if err != nil {
+		t.Fatalf("Unexpected error getting missing run: %v", err)
+	}
+	if run != nil {
+		t.Fatalf("Expected nil run for unknown ID, got %+v", run)
+	}
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		run, err := NewRun(RunTriggerScheduled)
+		if err != nil {
+			t.Fatalf("Failed to create run: %v", err)
+		}
+		if err := db.SaveRun(run); err != nil {
+			t.Fatalf("Failed to save run: %v", err)
+		}
+		ids = append(ids, run.ID)
+		time.Sleep(time.Millisecond) // Distinct start times
+	}
+
+	// Finishing a run updates it in place
+	last, err := db.GetRun(ids[2])
+	if err != nil || last == nil {
+		t.Fatalf("Failed to get run: %v", err)
+	}
+	if last.Status != RunStatusRunning {
+		t.Errorf("Expected a new run to be running, got %s", last.Status)
+	}
+	last.Channels = []RunChannel{{ChannelID: "UCchannel", NewVideos: 2}}
+	last.Emails = []RunEmail{{Recipient: "me@example.com", Videos: 2, Status: EmailStatusFailed, Error: "timeout"}}
+	last.Finish(errors.New("failed to send email"))
+	if err := db.SaveRun(last); err != nil {
+		t.Fatalf("Failed to save finished run: %v", err)
+	}
+
+	runs, err := db.GetRuns(0)
+	if err != nil {
+		t.Fatalf("Failed to list runs: %v", err)
+	}
+	if len(runs) != 3 || runs[0].ID != ids[2] || runs[2].ID != ids[0] {
+		t.Fatalf("Expected runs newest first, got %+v", runs)
+	}
+	if runs[0].Status != RunStatusFailed || runs[0].Error != "failed to send email" || runs[0].FinishedAt == nil {
+		t.Errorf("Expected the finished run to be failed, got %+v", runs[0])
+	}
+	if len(runs[0].Emails) != 1 || runs[0].Emails[0].Status != EmailStatusFailed {
+		t.Errorf("Expected the email outcome to be persisted, got %+v", runs[0].Emails)
+	}
+
+	runs, err = db.GetRuns(2)
+	if err != nil || len(runs) != 2 || runs[0].ID != ids[2] {
+		t.Fatalf("Expected the 2 newest runs, got %+v (err %v)", runs, err)
+	}
+
+	removed, err := db.PruneRuns(1)
+	if err != nil || removed != 2 {
+		t.Fatalf("Expected 2 runs to be pruned, got %d (err %v)", removed, err)
+	}
+	runs, err = db.GetRuns(0)
+	if err != nil || len(runs) != 1 || runs[0].ID != ids[2] {
+		t.Fatalf("Expected only the newest run to remain, got %+v (err %v)", runs, err)
+	}
+}
@@ -3,6 +3,8 @@ package store
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	badger "github.com/dgraph-io/badger/v3"
@@ -14,8 +16,18 @@ const (
 	smtpConfigKey      = "smtp_config"
 	llmConfigKey       = "llm_config"
 	checkIntervalKey   = "check_interval"
-	channelsKey        = "channels"
-	watchedVideosKey   = "watched_videos"
+	// Copy of the check interval that the scheduler runs on. Only intervals set since the
+	// scheduler started reading it are copied, so intervals saved by earlier versions, which
+	// nothing scheduled by, don't replace CRON_SCHEDULE on upgrade.
+	scheduleIntervalKey = "schedule_interval"
+)
+
+// Record key prefixes, each followed by the record's ID
+const (
+	channelKeyPrefix              = "channel/"
+	watchedKeyPrefix              = "watched/"
+	lastCheckedVideoKeyPrefix     = "last_checked_video/"
+	lastCheckedTimestampKeyPrefix = "last_checked_at/"
 )
 
 // Package store provides a Store interface for database operations, with both a BadgerDB-backed implementation (BadgerStore)
@@ -45,6 +57,7 @@ type Store interface {
 
 	// Configuration methods
 	GetCheckInterval() (time.Duration, error)
+	GetScheduleInterval() (time.Duration, error)
 	SetCheckInterval(interval time.Duration) error
 
 	// SMTP configuration methods
@@ -63,6 +76,53 @@ type Store interface {
 	GetWatchedVideos() ([]string, error)
 	SetVideoWatched(videoID string) error
 	IsVideoWatched(videoID string) (bool, error)
+
+	// Video catalogue methods
+	SaveVideo(video VideoEntry) error
+	GetVideo(videoID string) (*VideoEntry, error)
+	GetVideos() ([]VideoEntry, error)
+	PruneVideos(retention VideoRetention) (int, error)
+
+	// Summary persistence methods
+	GetSummary(videoID string) (*StoredSummary, error)
+	SetSummary(summary *StoredSummary) error
+
+	// Transcript methods
+	GetTranscript(videoID string) (*Transcript, error)
+	SetTranscript(transcript *Transcript) error
+
+	// Background job methods
+	GetJob(jobID string) (*Job, error)
+	SaveJob(job *Job) error
+	GetJobsByStatus(statuses ...JobStatus) ([]Job, error)
+
+	// Newsletter subscriber methods
+	GetSubscriber(subscriberID string) (*Subscriber, error)
+	GetSubscribers() ([]Subscriber, error)
+	SaveSubscriber(subscriber *Subscriber) error
+	DeleteSubscriber(subscriberID string) error
+
+	// Newsletter run history methods
+	GetRun(runID string) (*Run, error)
+	GetRuns(limit int) ([]Run, error)
+	SaveRun(run *Run) error
+	PruneRuns(keep int) (int, error)
+
+	// Newsletter outbox methods
+	GetOutboxMessage(messageID string) (*OutboxMessage, error)
+	GetOutboxMessages(statuses ...OutboxStatus) ([]OutboxMessage, error)
+	SaveOutboxMessage(message *OutboxMessage) error
+	PruneOutbox(keep int) (int, error)
+
+	// Outgoing webhook methods
+	GetWebhook(webhookID string) (*Webhook, error)
+	GetWebhooks() ([]Webhook, error)
+	SaveWebhook(webhook *Webhook) error
+	DeleteWebhook(webhookID string) error
+	GetWebhookDelivery(deliveryID string) (*WebhookDelivery, error)
+	GetWebhookDeliveries(webhookID string, limit int) ([]WebhookDelivery, error)
+	SaveWebhookDelivery(delivery *WebhookDelivery) error
+	PruneWebhookDeliveries(keep int) (int, error)
 }
 
 // SMTPConfig holds SMTP configuration
@@ -72,6 +132,11 @@ type SMTPConfig struct {
 	Username       string `json:"username"`
 	Password       string `json:"password"`
 	RecipientEmail string `json:"recipient_email"`
+
+	FromAddress        string `json:"from_address,omitempty"`         // Defaults to Username
+	FromName           string `json:"from_name,omitempty"`            // Display name for the From header
+	TLSMode            string `json:"tls_mode,omitempty"`             // "none", "starttls" or "implicit"; empty picks by port
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"` // Skip certificate verification for self-hosted relays
 }
 
 // LLMConfig holds LLM configuration for video summarization
@@ -83,7 +148,10 @@ type LLMConfig struct {
 
 // NewsletterConfig holds newsletter configuration
 type NewsletterConfig struct {
-	Enabled bool `json:"enabled"` // Whether the newsletter cron is enabled
+	Enabled             bool   `json:"enabled"`                       // Whether the newsletter cron is enabled
+	MaxVideosPerChannel int    `json:"maxVideosPerChannel,omitempty"` // Cap on videos listed per channel in a digest; 0 lists all
+	IncludeSummaries    bool   `json:"includeSummaries,omitempty"`    // Whether to summarise new videos in the digest
+	CronSchedule        string `json:"cronSchedule,omitempty"`        // Cron expression for newsletter runs; overrides the check interval when set
 }
 
 // BadgerStore handles database operations
@@ -98,7 +166,13 @@ func NewStore(dbPath string) (Store, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open badger database: %w", err)
 	}
-	return &BadgerStore{db: db}, nil
+
+	store := &BadgerStore{db: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return store, nil
 }
 
 // Close closes the database connection
@@ -110,7 +184,7 @@ func (s *BadgerStore) Close() error {
 func (s *BadgerStore) GetLastCheckedVideoID(channelID string) (string, error) {
 	var videoID string
 	err := s.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get([]byte(channelID))
+		item, err := txn.Get(lastCheckedVideoKey(channelID))
 		if err == badger.ErrKeyNotFound {
 			return nil // No entry yet, not an error
 		}
@@ -128,7 +202,7 @@ func (s *BadgerStore) GetLastCheckedVideoID(channelID string) (string, error) {
 // SetLastCheckedVideoID stores the ID of the last checked video for a channel
 func (s *BadgerStore) SetLastCheckedVideoID(channelID, videoID string) error {
 	return s.db.Update(func(txn *badger.Txn) error {
-		return txn.Set([]byte(channelID), []byte(videoID))
+		return txn.Set(lastCheckedVideoKey(channelID), []byte(videoID))
 	})
 }
 
@@ -136,7 +210,7 @@ func (s *BadgerStore) SetLastCheckedVideoID(channelID, videoID string) error {
 // This can be used as an alternative or in conjunction with VideoID
 func (s *BadgerStore) GetLastCheckedTimestamp(channelID string) (time.Time, error) {
 	var lastChecked time.Time
-	key := []byte(channelID) // Use channel ID directly as key
+	key := lastCheckedTimestampKey(channelID)
 
 	err := s.db.View(func(txn *badger.Txn) error {
 		item, err := txn.Get(key)
@@ -155,7 +229,7 @@ func (s *BadgerStore) GetLastCheckedTimestamp(channelID string) (time.Time, erro
 
 // SetLastCheckedTimestamp stores the timestamp of the last video check for a channel
 func (s *BadgerStore) SetLastCheckedTimestamp(channelID string, timestamp time.Time) error {
-	key := []byte(channelID) // Use channel ID directly as key
+	key := lastCheckedTimestampKey(channelID)
 	return s.db.Update(func(txn *badger.Txn) error {
 		val, err := timestamp.MarshalBinary()
 		if err != nil {
@@ -165,110 +239,86 @@ func (s *BadgerStore) SetLastCheckedTimestamp(channelID string, timestamp time.T
 	})
 }
 
-// GetChannels retrieves the list of all configured channels
+// GetChannels retrieves the list of all configured channels in the order they were added
 func (s *BadgerStore) GetChannels() ([]Channel, error) {
-	var channels []Channel
-	key := []byte(channelsKey)
+	var records []channelRecord
 
 	err := s.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(key)
-		if err == badger.ErrKeyNotFound {
-			return nil // No channels configured yet
-		}
-		if err != nil {
-			return fmt.Errorf("failed to get channels: %w", err)
-		}
-		return item.Value(func(val []byte) error {
-			if len(val) == 0 {
-				return nil
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(channelKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var record channelRecord
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &record)
+			}); err != nil {
+				return fmt.Errorf("failed to read channel: %w", err)
 			}
-			return json.Unmarshal(val, &channels)
-		})
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channels: %w", err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].AddedAt.Before(records[j].AddedAt)
 	})
-	return channels, err
+
+	var channels []Channel
+	for _, record := range records {
+		channels = append(channels, record.Channel)
+	}
+	return channels, nil
 }
 
 // AddChannel adds a new channel to the list of configured channels
 func (s *BadgerStore) AddChannel(channel Channel) error {
-	key := []byte(channelsKey)
+	key := channelKey(channel.ID)
 	return s.db.Update(func(txn *badger.Txn) error {
-		var channels []Channel
-		item, err := txn.Get(key)
-		if err != nil && err != badger.ErrKeyNotFound {
-			return fmt.Errorf("failed to get existing channels: %w", err)
-		}
+		_, err := txn.Get(key)
 		if err == nil {
-			err = item.Value(func(val []byte) error {
-				if len(val) == 0 {
-					return nil
-				}
-				return json.Unmarshal(val, &channels)
-			})
-			if err != nil {
-				return err
-			}
+			return nil // Channel already exists, no-op
 		}
-		// Check if channel already exists
-		for _, existing := range channels {
-			if existing.ID == channel.ID {
-				return nil // Channel already exists, no-op
-			}
-		}
-		// Add new channel
-		channels = append(channels, channel)
-		channelsBytes, err := json.Marshal(channels)
-		if err != nil {
-			return fmt.Errorf("failed to marshal channels: %w", err)
+		if err != badger.ErrKeyNotFound {
+			return fmt.Errorf("failed to get existing channel: %w", err)
 		}
-		return txn.Set(key, channelsBytes)
+		return setChannelRecord(txn, channelRecord{Channel: channel, AddedAt: time.Now()})
 	})
 }
 
 // RemoveChannel removes a channel from the list of configured channels
 func (s *BadgerStore) RemoveChannel(channelID string) error {
-	key := []byte(channelsKey)
 	return s.db.Update(func(txn *badger.Txn) error {
-		var channels []Channel
-		item, err := txn.Get(key)
-		if err == badger.ErrKeyNotFound {
-			return nil // No channels to remove
-		}
-		if err != nil {
-			return fmt.Errorf("failed to get existing channels: %w", err)
-		}
-		err = item.Value(func(val []byte) error {
-			if len(val) == 0 {
-				return nil
-			}
-			return json.Unmarshal(val, &channels)
-		})
-		if err != nil {
-			return err
-		}
-		// Filter out the channel to remove
-		var filteredChannels []Channel
-		for _, existing := range channels {
-			if existing.ID != channelID {
-				filteredChannels = append(filteredChannels, existing)
-			}
-		}
-		channelsBytes, err := json.Marshal(filteredChannels)
-		if err != nil {
-			return fmt.Errorf("failed to marshal channels: %w", err)
-		}
-		return txn.Set(key, channelsBytes)
+		return txn.Delete(channelKey(channelID))
 	})
 }
 
 // GetCheckInterval retrieves the configured check interval
 func (s *BadgerStore) GetCheckInterval() (time.Duration, error) {
+	interval, err := s.getInterval(checkIntervalKey)
+	if err == nil && interval == 0 {
+		interval = time.Hour // Default to 1 hour
+	}
+	return interval, err
+}
+
+// GetScheduleInterval retrieves the check interval the scheduler runs on, or 0 when no
+// interval has been set since the scheduler started reading it
+func (s *BadgerStore) GetScheduleInterval() (time.Duration, error) {
+	return s.getInterval(scheduleIntervalKey)
+}
+
+// getInterval reads a duration stored under key, or 0 when the key is not set
+func (s *BadgerStore) getInterval(key string) (time.Duration, error) {
 	var interval time.Duration
-	key := []byte(checkIntervalKey)
 
 	err := s.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(key)
+		item, err := txn.Get([]byte(key))
 		if err == badger.ErrKeyNotFound {
-			interval = time.Hour // Default to 1 hour
 			return nil
 		}
 		if err != nil {
@@ -287,11 +337,14 @@ func (s *BadgerStore) GetCheckInterval() (time.Duration, error) {
 	return interval, err
 }
 
-// SetCheckInterval stores the check interval configuration
+// SetCheckInterval stores the check interval configuration and schedules runs by it
 func (s *BadgerStore) SetCheckInterval(interval time.Duration) error {
-	key := []byte(checkIntervalKey)
+	value := []byte(interval.String())
 	return s.db.Update(func(txn *badger.Txn) error {
-		return txn.Set(key, []byte(interval.String()))
+		if err := txn.Set([]byte(checkIntervalKey), value); err != nil {
+			return err
+		}
+		return txn.Set([]byte(scheduleIntervalKey), value)
 	})
 }
 
@@ -411,72 +464,54 @@ func (s *BadgerStore) SetNewsletterConfig(config *NewsletterConfig) error {
 // GetWatchedVideos retrieves the list of all watched video IDs
 func (s *BadgerStore) GetWatchedVideos() ([]string, error) {
 	var watchedVideos []string
-	key := []byte(watchedVideosKey)
 
 	err := s.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(key)
-		if err == badger.ErrKeyNotFound {
-			return nil // No watched videos yet
-		}
-		if err != nil {
-			return fmt.Errorf("failed to get watched videos: %w", err)
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(watchedKeyPrefix)
+		opts.PrefetchValues = false // Only the keys are needed
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := string(it.Item().Key())
+			watchedVideos = append(watchedVideos, strings.TrimPrefix(key, watchedKeyPrefix))
 		}
-		return item.Value(func(val []byte) error {
-			if len(val) == 0 {
-				return nil
-			}
-			return json.Unmarshal(val, &watchedVideos)
-		})
+		return nil
 	})
-	return watchedVideos, err
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched videos: %w", err)
+	}
+	return watchedVideos, nil
 }
 
-// SetVideoWatched marks a video as watched by adding it to the watched videos list
+// SetVideoWatched marks a video as watched, recording when it was first marked
 func (s *BadgerStore) SetVideoWatched(videoID string) error {
-	key := []byte(watchedVideosKey)
+	key := watchedKey(videoID)
 	return s.db.Update(func(txn *badger.Txn) error {
-		var watchedVideos []string
-		item, err := txn.Get(key)
-		if err != nil && err != badger.ErrKeyNotFound {
-			return fmt.Errorf("failed to get existing watched videos: %w", err)
-		}
+		_, err := txn.Get(key)
 		if err == nil {
-			err = item.Value(func(val []byte) error {
-				if len(val) == 0 {
-					return nil
-				}
-				return json.Unmarshal(val, &watchedVideos)
-			})
-			if err != nil {
-				return err
-			}
+			return nil // Already watched, no-op
 		}
-		// Check if video is already marked as watched
-		for _, existingID := range watchedVideos {
-			if existingID == videoID {
-				return nil // Already watched, no-op
-			}
-		}
-		// Add new watched video
-		watchedVideos = append(watchedVideos, videoID)
-		watchedBytes, err := json.Marshal(watchedVideos)
-		if err != nil {
-			return fmt.Errorf("failed to marshal watched videos: %w", err)
+		if err != badger.ErrKeyNotFound {
+			return fmt.Errorf("failed to get watched state for video %s: %w", videoID, err)
 		}
-		return txn.Set(key, watchedBytes)
+		return setWatchedRecord(txn, videoID, time.Now())
 	})
 }
 
 // IsVideoWatched checks if a video is marked as watched
 func (s *BadgerStore) IsVideoWatched(videoID string) (bool, error) {
-	watchedVideos, err := s.GetWatchedVideos()
-	if err != nil {
-		return false, err
-	}
-	for _, id := range watchedVideos {
-		if id == videoID {
-			return true, nil
+	var watched bool
+	err := s.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(watchedKey(videoID))
+		if err == badger.ErrKeyNotFound {
+			return nil
 		}
-	}
-	return false, nil
+		if err != nil {
+			return fmt.Errorf("failed to get watched state for video %s: %w", videoID, err)
+		}
+		watched = true
+		return nil
+	})
+	return watched, err
 }
@@ -68,6 +68,34 @@ func (mr *MockStoreMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockStore)(nil).Close))
 }
 
+// DeleteSubscriber mocks base method.
+func (m *MockStore) DeleteSubscriber(subscriberID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscriber", subscriberID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscriber indicates an expected call of DeleteSubscriber.
+func (mr *MockStoreMockRecorder) DeleteSubscriber(subscriberID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriber", reflect.TypeOf((*MockStore)(nil).DeleteSubscriber), subscriberID)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStore) DeleteWebhook(webhookID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStoreMockRecorder) DeleteWebhook(webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStore)(nil).DeleteWebhook), webhookID)
+}
+
 // GetChannels mocks base method.
 func (m *MockStore) GetChannels() ([]Channel, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +126,40 @@ func (mr *MockStoreMockRecorder) GetCheckInterval() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckInterval", reflect.TypeOf((*MockStore)(nil).GetCheckInterval))
 }
 
+// GetJob mocks base method.
+func (m *MockStore) GetJob(jobID string) (*Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", jobID)
+	ret0, _ := ret[0].(*Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockStoreMockRecorder) GetJob(jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockStore)(nil).GetJob), jobID)
+}
+
+// GetJobsByStatus mocks base method.
+func (m *MockStore) GetJobsByStatus(statuses ...JobStatus) ([]Job, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range statuses {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetJobsByStatus", varargs...)
+	ret0, _ := ret[0].([]Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobsByStatus indicates an expected call of GetJobsByStatus.
+func (mr *MockStoreMockRecorder) GetJobsByStatus(statuses ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobsByStatus", reflect.TypeOf((*MockStore)(nil).GetJobsByStatus), statuses...)
+}
+
 // GetLLMConfig mocks base method.
 func (m *MockStore) GetLLMConfig() (*LLMConfig, error) {
 	m.ctrl.T.Helper()
@@ -158,6 +220,70 @@ func (mr *MockStoreMockRecorder) GetNewsletterConfig() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsletterConfig", reflect.TypeOf((*MockStore)(nil).GetNewsletterConfig))
 }
 
+// GetOutboxMessage mocks base method.
+func (m *MockStore) GetOutboxMessage(messageID string) (*OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxMessage", messageID)
+	ret0, _ := ret[0].(*OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxMessage indicates an expected call of GetOutboxMessage.
+func (mr *MockStoreMockRecorder) GetOutboxMessage(messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxMessage", reflect.TypeOf((*MockStore)(nil).GetOutboxMessage), messageID)
+}
+
+// GetOutboxMessages mocks base method.
+func (m *MockStore) GetOutboxMessages(statuses ...OutboxStatus) ([]OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range statuses {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetOutboxMessages", varargs...)
+	ret0, _ := ret[0].([]OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxMessages indicates an expected call of GetOutboxMessages.
+func (mr *MockStoreMockRecorder) GetOutboxMessages(statuses ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxMessages", reflect.TypeOf((*MockStore)(nil).GetOutboxMessages), statuses...)
+}
+
+// GetRun mocks base method.
+func (m *MockStore) GetRun(runID string) (*Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRun", runID)
+	ret0, _ := ret[0].(*Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRun indicates an expected call of GetRun.
+func (mr *MockStoreMockRecorder) GetRun(runID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRun", reflect.TypeOf((*MockStore)(nil).GetRun), runID)
+}
+
+// GetRuns mocks base method.
+func (m *MockStore) GetRuns(limit int) ([]Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuns", limit)
+	ret0, _ := ret[0].([]Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuns indicates an expected call of GetRuns.
+func (mr *MockStoreMockRecorder) GetRuns(limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuns", reflect.TypeOf((*MockStore)(nil).GetRuns), limit)
+}
+
 // GetSMTPConfig mocks base method.
 func (m *MockStore) GetSMTPConfig() (*SMTPConfig, error) {
 	m.ctrl.T.Helper()
@@ -173,6 +299,111 @@ func (mr *MockStoreMockRecorder) GetSMTPConfig() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSMTPConfig", reflect.TypeOf((*MockStore)(nil).GetSMTPConfig))
 }
 
+// GetScheduleInterval mocks base method.
+func (m *MockStore) GetScheduleInterval() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduleInterval")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduleInterval indicates an expected call of GetScheduleInterval.
+func (mr *MockStoreMockRecorder) GetScheduleInterval() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleInterval", reflect.TypeOf((*MockStore)(nil).GetScheduleInterval))
+}
+
+// GetSubscriber mocks base method.
+func (m *MockStore) GetSubscriber(subscriberID string) (*Subscriber, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriber", subscriberID)
+	ret0, _ := ret[0].(*Subscriber)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriber indicates an expected call of GetSubscriber.
+func (mr *MockStoreMockRecorder) GetSubscriber(subscriberID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriber", reflect.TypeOf((*MockStore)(nil).GetSubscriber), subscriberID)
+}
+
+// GetSubscribers mocks base method.
+func (m *MockStore) GetSubscribers() ([]Subscriber, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscribers")
+	ret0, _ := ret[0].([]Subscriber)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscribers indicates an expected call of GetSubscribers.
+func (mr *MockStoreMockRecorder) GetSubscribers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribers", reflect.TypeOf((*MockStore)(nil).GetSubscribers))
+}
+
+// GetSummary mocks base method.
+func (m *MockStore) GetSummary(videoID string) (*StoredSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummary", videoID)
+	ret0, _ := ret[0].(*StoredSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSummary indicates an expected call of GetSummary.
+func (mr *MockStoreMockRecorder) GetSummary(videoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummary", reflect.TypeOf((*MockStore)(nil).GetSummary), videoID)
+}
+
+// GetTranscript mocks base method.
+func (m *MockStore) GetTranscript(videoID string) (*Transcript, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTranscript", videoID)
+	ret0, _ := ret[0].(*Transcript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTranscript indicates an expected call of GetTranscript.
+func (mr *MockStoreMockRecorder) GetTranscript(videoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTranscript", reflect.TypeOf((*MockStore)(nil).GetTranscript), videoID)
+}
+
+// GetVideo mocks base method.
+func (m *MockStore) GetVideo(videoID string) (*VideoEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideo", videoID)
+	ret0, _ := ret[0].(*VideoEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideo indicates an expected call of GetVideo.
+func (mr *MockStoreMockRecorder) GetVideo(videoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideo", reflect.TypeOf((*MockStore)(nil).GetVideo), videoID)
+}
+
+// GetVideos mocks base method.
+func (m *MockStore) GetVideos() ([]VideoEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideos")
+	ret0, _ := ret[0].([]VideoEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideos indicates an expected call of GetVideos.
+func (mr *MockStoreMockRecorder) GetVideos() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideos", reflect.TypeOf((*MockStore)(nil).GetVideos))
+}
+
 // GetWatchedVideos mocks base method.
 func (m *MockStore) GetWatchedVideos() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -188,6 +419,66 @@ func (mr *MockStoreMockRecorder) GetWatchedVideos() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchedVideos", reflect.TypeOf((*MockStore)(nil).GetWatchedVideos))
 }
 
+// GetWebhook mocks base method.
+func (m *MockStore) GetWebhook(webhookID string) (*Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", webhookID)
+	ret0, _ := ret[0].(*Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockStoreMockRecorder) GetWebhook(webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockStore)(nil).GetWebhook), webhookID)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockStore) GetWebhookDeliveries(webhookID string, limit int) ([]WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", webhookID, limit)
+	ret0, _ := ret[0].([]WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveries(webhookID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveries), webhookID, limit)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(deliveryID string) (*WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", deliveryID)
+	ret0, _ := ret[0].(*WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), deliveryID)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockStore) GetWebhooks() ([]Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks")
+	ret0, _ := ret[0].([]Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockStoreMockRecorder) GetWebhooks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockStore)(nil).GetWebhooks))
+}
+
 // IsVideoWatched mocks base method.
 func (m *MockStore) IsVideoWatched(videoID string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -203,6 +494,66 @@ func (mr *MockStoreMockRecorder) IsVideoWatched(videoID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVideoWatched", reflect.TypeOf((*MockStore)(nil).IsVideoWatched), videoID)
 }
 
+// PruneOutbox mocks base method.
+func (m *MockStore) PruneOutbox(keep int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneOutbox", keep)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneOutbox indicates an expected call of PruneOutbox.
+func (mr *MockStoreMockRecorder) PruneOutbox(keep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneOutbox", reflect.TypeOf((*MockStore)(nil).PruneOutbox), keep)
+}
+
+// PruneRuns mocks base method.
+func (m *MockStore) PruneRuns(keep int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneRuns", keep)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneRuns indicates an expected call of PruneRuns.
+func (mr *MockStoreMockRecorder) PruneRuns(keep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneRuns", reflect.TypeOf((*MockStore)(nil).PruneRuns), keep)
+}
+
+// PruneVideos mocks base method.
+func (m *MockStore) PruneVideos(retention VideoRetention) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneVideos", retention)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneVideos indicates an expected call of PruneVideos.
+func (mr *MockStoreMockRecorder) PruneVideos(retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneVideos", reflect.TypeOf((*MockStore)(nil).PruneVideos), retention)
+}
+
+// PruneWebhookDeliveries mocks base method.
+func (m *MockStore) PruneWebhookDeliveries(keep int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneWebhookDeliveries", keep)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneWebhookDeliveries indicates an expected call of PruneWebhookDeliveries.
+func (mr *MockStoreMockRecorder) PruneWebhookDeliveries(keep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).PruneWebhookDeliveries), keep)
+}
+
 // RemoveChannel mocks base method.
 func (m *MockStore) RemoveChannel(channelID string) error {
 	m.ctrl.T.Helper()
@@ -217,6 +568,104 @@ func (mr *MockStoreMockRecorder) RemoveChannel(channelID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveChannel", reflect.TypeOf((*MockStore)(nil).RemoveChannel), channelID)
 }
 
+// SaveJob mocks base method.
+func (m *MockStore) SaveJob(job *Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveJob", job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveJob indicates an expected call of SaveJob.
+func (mr *MockStoreMockRecorder) SaveJob(job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveJob", reflect.TypeOf((*MockStore)(nil).SaveJob), job)
+}
+
+// SaveOutboxMessage mocks base method.
+func (m *MockStore) SaveOutboxMessage(message *OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOutboxMessage", message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOutboxMessage indicates an expected call of SaveOutboxMessage.
+func (mr *MockStoreMockRecorder) SaveOutboxMessage(message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOutboxMessage", reflect.TypeOf((*MockStore)(nil).SaveOutboxMessage), message)
+}
+
+// SaveRun mocks base method.
+func (m *MockStore) SaveRun(run *Run) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRun", run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRun indicates an expected call of SaveRun.
+func (mr *MockStoreMockRecorder) SaveRun(run any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRun", reflect.TypeOf((*MockStore)(nil).SaveRun), run)
+}
+
+// SaveSubscriber mocks base method.
+func (m *MockStore) SaveSubscriber(subscriber *Subscriber) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSubscriber", subscriber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSubscriber indicates an expected call of SaveSubscriber.
+func (mr *MockStoreMockRecorder) SaveSubscriber(subscriber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSubscriber", reflect.TypeOf((*MockStore)(nil).SaveSubscriber), subscriber)
+}
+
+// SaveVideo mocks base method.
+func (m *MockStore) SaveVideo(video VideoEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVideo", video)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveVideo indicates an expected call of SaveVideo.
+func (mr *MockStoreMockRecorder) SaveVideo(video any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVideo", reflect.TypeOf((*MockStore)(nil).SaveVideo), video)
+}
+
+// SaveWebhook mocks base method.
+func (m *MockStore) SaveWebhook(webhook *Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWebhook", webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWebhook indicates an expected call of SaveWebhook.
+func (mr *MockStoreMockRecorder) SaveWebhook(webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebhook", reflect.TypeOf((*MockStore)(nil).SaveWebhook), webhook)
+}
+
+// SaveWebhookDelivery mocks base method.
+func (m *MockStore) SaveWebhookDelivery(delivery *WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWebhookDelivery indicates an expected call of SaveWebhookDelivery.
+func (mr *MockStoreMockRecorder) SaveWebhookDelivery(delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebhookDelivery", reflect.TypeOf((*MockStore)(nil).SaveWebhookDelivery), delivery)
+}
+
 // SetCheckInterval mocks base method.
 func (m *MockStore) SetCheckInterval(interval time.Duration) error {
 	m.ctrl.T.Helper()
@@ -301,6 +750,34 @@ func (mr *MockStoreMockRecorder) SetSMTPConfig(config any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSMTPConfig", reflect.TypeOf((*MockStore)(nil).SetSMTPConfig), config)
 }
 
+// SetSummary mocks base method.
+func (m *MockStore) SetSummary(summary *StoredSummary) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSummary", summary)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSummary indicates an expected call of SetSummary.
+func (mr *MockStoreMockRecorder) SetSummary(summary any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSummary", reflect.TypeOf((*MockStore)(nil).SetSummary), summary)
+}
+
+// SetTranscript mocks base method.
+func (m *MockStore) SetTranscript(transcript *Transcript) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTranscript", transcript)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTranscript indicates an expected call of SetTranscript.
+func (mr *MockStoreMockRecorder) SetTranscript(transcript any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTranscript", reflect.TypeOf((*MockStore)(nil).SetTranscript), transcript)
+}
+
 // SetVideoWatched mocks base method.
 func (m *MockStore) SetVideoWatched(videoID string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"youtube-curator-v2/internal/rss"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// subscriberKeyPrefix prefixes newsletter subscriber keys, which are followed by the subscriber ID
+const subscriberKeyPrefix = "subscriber:"
+
+// Subscriber is a newsletter recipient with the channels and tags they want in their digest.
+// A subscriber with neither channels nor tags receives every new video.
+type Subscriber struct {
+	ID         string        `json:"id"`
+	Email      string        `json:"email"`
+	ChannelIDs []string      `json:"channelIds,omitempty"` // Channels whose new videos are included
+	Tags       []string      `json:"tags,omitempty"`       // Videos with any of these tags are included, whatever the channel
+	Notify     *NotifyTarget `json:"notify,omitempty"`     // Where the digest is delivered; by email when not set
+	Enabled    bool          `json:"enabled"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	UpdatedAt  time.Time     `json:"updatedAt"`
+}
+
+// NotifyTarget is a notification channel a subscriber's digest is delivered through
+type NotifyTarget struct {
+	Type   string `json:"type"`             // email, webhook, slack, discord, ntfy or gotify
+	URL    string `json:"url,omitempty"`    // Webhook URL, ntfy topic URL or Gotify server URL
+	Secret string `json:"secret,omitempty"` // Key the generic webhook payload is signed with
+	Token  string `json:"token,omitempty"`  // ntfy access token or Gotify application token
+}
+
+// Wants reports whether a new video from channelID belongs in the subscriber's digest
+func (s *Subscriber) Wants(channelID string, video rss.Entry) bool {
+	if len(s.ChannelIDs) == 0 && len(s.Tags) == 0 {
+		return true
+	}
+	for _, id := range s.ChannelIDs {
+		if id == channelID {
+			return true
+		}
+	}
+	for _, tag := range s.Tags {
+		for _, videoTag := range video.Tags {
+			if strings.EqualFold(tag, videoTag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func subscriberKey(subscriberID string) []byte {
+	return []byte(subscriberKeyPrefix + subscriberID)
+}
+
+// GetSubscriber retrieves a subscriber by ID, returning nil if it doesn't exist
+func (s *BadgerStore) GetSubscriber(subscriberID string) (*Subscriber, error) {
+	var subscriber *Subscriber
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(subscriberKey(subscriberID))
+		if err == badger.ErrKeyNotFound {
+			return nil // Unknown subscriber
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get subscriber %s: %w", subscriberID, err)
+		}
+		return item.Value(func(val []byte) error {
+			subscriber = &Subscriber{}
+			return json.Unmarshal(val, subscriber)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriber, nil
+}
+
+// GetSubscribers retrieves all subscribers, ordered by creation time
+func (s *BadgerStore) GetSubscribers() ([]Subscriber, error) {
+	var subscribers []Subscriber
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(subscriberKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var subscriber Subscriber
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &subscriber)
+			}); err != nil {
+				return fmt.Errorf("failed to read subscriber: %w", err)
+			}
+			subscribers = append(subscribers, subscriber)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(subscribers, func(i, j int) bool {
+		return subscribers[i].CreatedAt.Before(subscribers[j].CreatedAt)
+	})
+	return subscribers, nil
+}
+
+// SaveSubscriber creates or updates a subscriber
+func (s *BadgerStore) SaveSubscriber(subscriber *Subscriber) error {
+	if subscriber == nil || subscriber.ID == "" {
+		return fmt.Errorf("subscriber must have an ID")
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		subscriberBytes, err := json.Marshal(subscriber)
+		if err != nil {
+			return fmt.Errorf("failed to marshal subscriber: %w", err)
+		}
+		return txn.Set(subscriberKey(subscriber.ID), subscriberBytes)
+	})
+}
+
+// DeleteSubscriber removes a subscriber; deleting an unknown subscriber is not an error
+func (s *BadgerStore) DeleteSubscriber(subscriberID string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(subscriberKey(subscriberID))
+	})
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/rss"
+)
+
+func TestBadgerStore_SubscriberPersistence(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	subscriber, err := db.GetSubscriber("missing")
+	if err != nil {
+		t.Fatalf("Unexpected error getting missing subscriber: %v", err)
+	}
+	if subscriber != nil {
+		t.Fatalf("Expected nil subscriber for unknown ID, got %+v", subscriber)
+	}
+
+	now := time.Now()
+	subscribers := []*Subscriber{
+		{ID: "b", Email: "bob@example.com", ChannelIDs: []string{"UCbbbbbbbbbbbbbbbbbbbbbb"}, Enabled: true, CreatedAt: now},
+		{ID: "a", Email: "alice@example.com", Tags: []string{"golang"}, Enabled: false, CreatedAt: now.Add(-time.Minute)},
+	}
+	for _, sub := range subscribers {
+		if err := db.SaveSubscriber(sub); err != nil {
+			t.Fatalf("Failed to save subscriber %s: %v", sub.ID, err)
+		}
+	}
+
+	subscriber, err = db.GetSubscriber("b")
+	if err != nil {
+		t.Fatalf("Failed to get subscriber: %v", err)
+	}
+	if subscriber == nil || subscriber.Email != "bob@example.com" || len(subscriber.ChannelIDs) != 1 || !subscriber.Enabled {
+		t.Fatalf("Unexpected subscriber: %+v", subscriber)
+	}
+
+	all, err := db.GetSubscribers()
+	if err != nil {
+		t.Fatalf("Failed to list subscribers: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "a" || all[1].ID != "b" {
+		t.Fatalf("Expected subscribers ordered oldest first, got %+v", all)
+	}
+
+	if err := db.DeleteSubscriber("a"); err != nil {
+		t.Fatalf("Failed to delete subscriber: %v", err)
+	}
+	if err := db.DeleteSubscriber("missing"); err != nil {
+		t.Fatalf("Deleting an unknown subscriber should not fail: %v", err)
+	}
+	all, err = db.GetSubscribers()
+	if err != nil {
+		t.Fatalf("Failed to list subscribers: %v", err)
+	}
+	if len(all) != 1 || all[0].ID != "b" {
+		t.Fatalf("Expected only subscriber b to remain, got %+v", all)
+	}
+}
+
+func TestSubscriber_Wants(t *testing.T) {
+	video := rss.Entry{ID: "yt:video:1", Tags: []string{"Golang", "Databases"}}
+
+	tests := []struct {
+		name       string
+		subscriber Subscriber
+		channelID  string
+		want       bool
+	}{
+		{name: "no filter receives everything", subscriber: Subscriber{}, channelID: "UCany", want: true},
+		{name: "subscribed channel", subscriber: Subscriber{ChannelIDs: []string{"UCone", "UCtwo"}}, channelID: "UCtwo", want: true},
+		{name: "other channel", subscriber: Subscriber{ChannelIDs: []string{"UCone"}}, channelID: "UCtwo", want: false},
+		{name: "matching tag ignores case", subscriber: Subscriber{Tags: []string{"golang"}}, channelID: "UCany", want: true},
+		{name: "no matching tag", subscriber: Subscriber{Tags: []string{"rust"}}, channelID: "UCany", want: false},
+		{name: "tag matches outside subscribed channels", subscriber: Subscriber{ChannelIDs: []string{"UCone"}, Tags: []string{"databases"}}, channelID: "UCtwo", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.subscriber.Wants(tt.channelID, video); got != tt.want {
+				t.Errorf("Wants(%s) = %v, want %v", tt.channelID, got, tt.want)
+			}
+		})
+	}
+}
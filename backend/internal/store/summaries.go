@@ -0,0 +1,65 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// summaryKeyPrefix prefixes summary keys, which are followed by the full yt:video: ID
+const summaryKeyPrefix = "summary:"
+
+// StoredSummary holds a persisted video summary
+type StoredSummary struct {
+	VideoID        string    `json:"videoId"`             // Full video ID (yt:video:ID)
+	ChannelID      string    `json:"channelId,omitempty"` // Channel the video belongs to, if known
+	Text           string    `json:"text"`                // The generated summary text
+	Thinking       string    `json:"thinking,omitempty"`  // LLM thinking content from <think> blocks
+	SourceLanguage string    `json:"sourceLanguage"`      // Language of subtitles used (e.g., "en")
+	Model          string    `json:"model"`               // Model name used to generate the summary
+	GeneratedAt    time.Time `json:"generatedAt"`         // When the summary was generated
+}
+
+func summaryKey(videoID string) []byte {
+	return []byte(summaryKeyPrefix + videoID)
+}
+
+// GetSummary retrieves the stored summary for a video, returning nil if none exists
+func (s *BadgerStore) GetSummary(videoID string) (*StoredSummary, error) {
+	var summary *StoredSummary
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(summaryKey(videoID))
+		if err == badger.ErrKeyNotFound {
+			return nil // No summary yet
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get summary for video %s: %w", videoID, err)
+		}
+		return item.Value(func(val []byte) error {
+			summary = &StoredSummary{}
+			return json.Unmarshal(val, summary)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// SetSummary stores a summary, overwriting any existing summary for the same video
+func (s *BadgerStore) SetSummary(summary *StoredSummary) error {
+	if summary == nil || summary.VideoID == "" {
+		return fmt.Errorf("summary must have a video ID")
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		summaryBytes, err := json.Marshal(summary)
+		if err != nil {
+			return fmt.Errorf("failed to marshal summary: %w", err)
+		}
+		return txn.Set(summaryKey(summary.VideoID), summaryBytes)
+	})
+}
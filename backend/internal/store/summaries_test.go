@@ -0,0 +1,81 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBadgerStore_SummaryPersistence(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	videoID := "yt:video:dQw4w9WgXcQ"
+
+	// Missing summaries are not an error
+	summary, err := db.GetSummary(videoID)
+	if err != nil {
+		t.Fatalf("Unexpected error getting missing summary: %v", err)
+	}
+	if summary != nil {
+		t.Fatalf("Expected nil summary for unknown video, got %+v", summary)
+	}
+
+	generatedAt := time.Now().UTC().Truncate(time.Second)
+	stored := &StoredSummary{
+		VideoID:        videoID,
+		ChannelID:      "UCuAXFkgsw1L7xaCfnd5JJOw",
+		Text:           "First summary",
+		Thinking:       "Some thinking",
+		SourceLanguage: "en",
+		Model:          "test-model",
+		GeneratedAt:    generatedAt,
+	}
+	if err := db.SetSummary(stored); err != nil {
+		t.Fatalf("Failed to store summary: %v", err)
+	}
+
+	summary, err = db.GetSummary(videoID)
+	if err != nil {
+		t.Fatalf("Failed to get summary: %v", err)
+	}
+	if summary == nil {
+		t.Fatalf("Expected stored summary, got nil")
+	}
+	if summary.Text != "First summary" || summary.Thinking != "Some thinking" || summary.Model != "test-model" {
+		t.Errorf("Stored summary mismatch: %+v", summary)
+	}
+	if summary.ChannelID != stored.ChannelID || summary.SourceLanguage != "en" {
+		t.Errorf("Stored summary metadata mismatch: %+v", summary)
+	}
+	if !summary.GeneratedAt.Equal(generatedAt) {
+		t.Errorf("Expected GeneratedAt %v, got %v", generatedAt, summary.GeneratedAt)
+	}
+
+	// Overwriting replaces the existing entry
+	stored.Text = "Regenerated summary"
+	if err := db.SetSummary(stored); err != nil {
+		t.Fatalf("Failed to overwrite summary: %v", err)
+	}
+	summary, err = db.GetSummary(videoID)
+	if err != nil {
+		t.Fatalf("Failed to get summary: %v", err)
+	}
+	if summary.Text != "Regenerated summary" {
+		t.Errorf("Expected overwritten summary text, got %q", summary.Text)
+	}
+}
+
+func TestBadgerStore_SetSummaryRequiresVideoID(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetSummary(&StoredSummary{Text: "orphan"}); err == nil {
+		t.Fatalf("Expected error when storing summary without video ID")
+	}
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// transcriptKeyPrefix prefixes transcript keys, which are followed by the full yt:video: ID
+const transcriptKeyPrefix = "transcript:"
+
+// TranscriptCue is a single timed line of a transcript
+type TranscriptCue struct {
+	StartMs int    `json:"startMs"` // Offset from the start of the video in milliseconds
+	Text    string `json:"text"`
+}
+
+// Transcript holds the timed subtitle text of a video
+type Transcript struct {
+	VideoID   string          `json:"videoId"` // Full video ID (yt:video:ID)
+	Cues      []TranscriptCue `json:"cues"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+}
+
+func transcriptKey(videoID string) []byte {
+	return []byte(transcriptKeyPrefix + videoID)
+}
+
+// GetTranscript retrieves the stored transcript for a video, returning nil if none exists
+func (s *BadgerStore) GetTranscript(videoID string) (*Transcript, error) {
+	var transcript *Transcript
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(transcriptKey(videoID))
+		if err == badger.ErrKeyNotFound {
+			return nil // No transcript yet
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get transcript for video %s: %w", videoID, err)
+		}
+		return item.Value(func(val []byte) error {
+			transcript = &Transcript{}
+			return json.Unmarshal(val, transcript)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transcript, nil
+}
+
+// SetTranscript stores a transcript, overwriting any existing transcript for the same video
+func (s *BadgerStore) SetTranscript(transcript *Transcript) error {
+	if transcript == nil || transcript.VideoID == "" {
+		return fmt.Errorf("transcript must have a video ID")
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		transcriptBytes, err := json.Marshal(transcript)
+		if err != nil {
+			return fmt.Errorf("failed to marshal transcript: %w", err)
+		}
+		return txn.Set(transcriptKey(transcript.VideoID), transcriptBytes)
+	})
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// VideoSortField selects the field videos are ordered by
+type VideoSortField string
+
+const (
+	VideoSortPublished VideoSortField = "published"
+	VideoSortTitle     VideoSortField = "title"
+	VideoSortDuration  VideoSortField = "duration"
+)
+
+// VideoQuery filters and orders videos. Zero values leave the corresponding filter off.
+type VideoQuery struct {
+	ChannelID       string
+	Watched         *bool     // Only watched (true) or unwatched (false) videos
+	PublishedAfter  time.Time // Inclusive lower bound on the published date
+	PublishedBefore time.Time // Exclusive upper bound on the published date
+	MinDuration     int       // Minimum duration in seconds
+	MaxDuration     int       // Maximum duration in seconds
+	SortBy          VideoSortField
+	Ascending       bool
+}
+
+// Matches reports whether a video passes the query's filters.
+// Videos with an unknown duration never match a duration filter.
+func (q VideoQuery) Matches(video VideoEntry) bool {
+	if q.ChannelID != "" && video.ChannelID != q.ChannelID {
+		return false
+	}
+	if q.Watched != nil && video.Watched != *q.Watched {
+		return false
+	}
+	if !q.PublishedAfter.IsZero() && video.Entry.Published.Before(q.PublishedAfter) {
+		return false
+	}
+	if !q.PublishedBefore.IsZero() && !video.Entry.Published.Before(q.PublishedBefore) {
+		return false
+	}
+	if q.MinDuration > 0 || q.MaxDuration > 0 {
+		duration := video.Entry.Duration
+		if duration == 0 {
+			return false
+		}
+		if q.MinDuration > 0 && duration < q.MinDuration {
+			return false
+		}
+		if q.MaxDuration > 0 && duration > q.MaxDuration {
+			return false
+		}
+	}
+	return true
+}
+
+// Less orders two videos by the query's sort field, breaking ties by video ID so the
+// order is total and stable across requests
+func (q VideoQuery) Less(a, b VideoEntry) bool {
+	var cmp int
+	switch q.SortBy {
+	case VideoSortTitle:
+		cmp = strings.Compare(strings.ToLower(a.Entry.Title), strings.ToLower(b.Entry.Title))
+	case VideoSortDuration:
+		cmp = a.Entry.Duration - b.Entry.Duration
+	default:
+		cmp = a.Entry.Published.Compare(b.Entry.Published)
+	}
+	if cmp == 0 {
+		cmp = strings.Compare(a.Entry.ID, b.Entry.ID)
+	}
+	if q.Ascending {
+		return cmp < 0
+	}
+	return cmp > 0
+}
+
+// Apply returns the videos that match the query, in the query's sort order
+func (q VideoQuery) Apply(videos []VideoEntry) []VideoEntry {
+	matched := make([]VideoEntry, 0, len(videos))
+	for _, video := range videos {
+		if q.Matches(video) {
+			matched = append(matched, video)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return q.Less(matched[i], matched[j])
+	})
+	return matched
+}
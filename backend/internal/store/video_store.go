@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"log"
 	"sync"
 	"time"
 	"youtube-curator-v2/internal/rss"
@@ -9,19 +10,23 @@ import (
 
 // VideoEntry represents a video with metadata and TTL
 type VideoEntry struct {
-	Entry     rss.Entry `json:"entry"`
-	ChannelID string    `json:"channelId"`
-	CachedAt  time.Time `json:"cachedAt"`
-	Watched   bool      `json:"watched"`
+	Entry        rss.Entry `json:"entry"`
+	ChannelID    string    `json:"channelId"`
+	CachedAt     time.Time `json:"cachedAt"`               // When the video was last fetched
+	DiscoveredAt time.Time `json:"discoveredAt,omitempty"` // When the video was first fetched
+	Watched      bool      `json:"watched"`
 }
 
-// VideoStore provides in-memory storage for videos with TTL
+// VideoStore provides storage for fetched videos. With a persistent store the videos are kept
+// in the database catalogue and limited by a retention policy; without one they are held in
+// memory with a TTL.
 type VideoStore struct {
-	videos          map[string]VideoEntry // key: video ID
+	videos          map[string]VideoEntry // key: video ID, only used without a persistent store
 	mutex           sync.RWMutex
 	ttl             time.Duration
+	retention       VideoRetention
 	lastRefreshedAt time.Time
-	store           Store // Reference to persistent store for watched state
+	store           Store // Persistent video catalogue and watched state
 }
 
 // NewVideoStore creates a new in-memory video store with the specified TTL
@@ -39,11 +44,12 @@ func NewVideoStore(ttl time.Duration) *VideoStore {
 	return store
 }
 
-// NewVideoStoreWithStore creates a new in-memory video store with persistent store reference
-func NewVideoStoreWithStore(ttl time.Duration, store Store) *VideoStore {
+// NewVideoStoreWithStore creates a video store backed by the persistent video catalogue,
+// pruned according to retention
+func NewVideoStoreWithStore(retention VideoRetention, store Store) *VideoStore {
 	vs := &VideoStore{
 		videos:          make(map[string]VideoEntry),
-		ttl:             ttl,
+		retention:       retention,
 		lastRefreshedAt: time.Time{},
 		store:           store,
 	}
@@ -66,17 +72,23 @@ func (vs *VideoStore) AddVideo(channelID string, entry rss.Entry) error {
 	vs.mutex.Lock()
 	defer vs.mutex.Unlock()
 
+	if vs.store != nil {
+		// Watched state is kept by the store and joined when videos are read
+		video := VideoEntry{
+			Entry:     entry,
+			ChannelID: channelID,
+			CachedAt:  time.Now(),
+		}
+		if err := vs.store.SaveVideo(video); err != nil {
+			return fmt.Errorf("failed to save video %s: %w", entry.ID, err)
+		}
+		return nil
+	}
+
 	// Check if video already exists and preserve its watched state
 	var watched bool = false
 	if existingVideo, exists := vs.videos[entry.ID]; exists {
 		watched = existingVideo.Watched
-	} else if vs.store != nil {
-		// If video doesn't exist in memory, check persistent store for watched state
-		isWatched, err := vs.store.IsVideoWatched(entry.ID)
-		if err != nil {
-			return fmt.Errorf("failed to check watched state for video %s: %w", entry.ID, err)
-		}
-		watched = isWatched
 	}
 
 	vs.videos[entry.ID] = VideoEntry{
@@ -88,11 +100,38 @@ func (vs *VideoStore) AddVideo(channelID string, entry rss.Entry) error {
 	return nil
 }
 
+// HasVideo reports whether the video is already in the store
+func (vs *VideoStore) HasVideo(videoID string) bool {
+	vs.mutex.RLock()
+	defer vs.mutex.RUnlock()
+
+	if vs.store != nil {
+		video, err := vs.store.GetVideo(videoID)
+		if err != nil {
+			log.Printf("Failed to get video %s from store: %v", videoID, err)
+			return true // Assume known rather than announce it twice
+		}
+		return video != nil
+	}
+
+	_, exists := vs.videos[videoID]
+	return exists
+}
+
 // GetAllVideos returns all non-expired videos
 func (vs *VideoStore) GetAllVideos() []VideoEntry {
 	vs.mutex.RLock()
 	defer vs.mutex.RUnlock()
 
+	if vs.store != nil {
+		videos, err := vs.store.GetVideos()
+		if err != nil {
+			log.Printf("Failed to get videos from store: %v", err)
+			return nil
+		}
+		return videos
+	}
+
 	now := time.Now()
 	var validVideos []VideoEntry
 
@@ -110,6 +149,14 @@ func (vs *VideoStore) cleanupExpired() {
 	ticker := time.NewTicker(time.Hour) // Run cleanup every hour
 	defer ticker.Stop()
 
+	if vs.store != nil {
+		vs.pruneStore()
+		for range ticker.C {
+			vs.pruneStore()
+		}
+		return
+	}
+
 	for range ticker.C {
 		vs.mutex.Lock()
 		now := time.Now()
@@ -123,8 +170,24 @@ func (vs *VideoStore) cleanupExpired() {
 	}
 }
 
+// pruneStore removes videos outside the retention policy from the persistent catalogue
+func (vs *VideoStore) pruneStore() {
+	removed, err := vs.store.PruneVideos(vs.retention)
+	if err != nil {
+		log.Printf("Failed to prune video catalogue: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("Pruned %d videos from the video catalogue", removed)
+	}
+}
+
 // GetVideoCount returns the number of videos currently in the store
 func (vs *VideoStore) GetVideoCount() int {
+	if vs.store != nil {
+		return len(vs.GetAllVideos())
+	}
+
 	vs.mutex.RLock()
 	defer vs.mutex.RUnlock()
 	return len(vs.videos)
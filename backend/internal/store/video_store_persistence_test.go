@@ -19,7 +19,7 @@ func TestVideoStore_WatchedStatePersistence_AcrossRestarts(t *testing.T) {
 	}
 
 	// Create first video store
-	videoStore1 := NewVideoStoreWithStore(1*time.Hour, db1)
+	videoStore1 := NewVideoStoreWithStore(VideoRetention{}, db1)
 
 	// Add a test video
 	testVideo := rss.Entry{
@@ -67,7 +67,7 @@ func TestVideoStore_WatchedStatePersistence_AcrossRestarts(t *testing.T) {
 	defer db2.Close()
 
 	// Create second video store (simulating new container startup)
-	videoStore2 := NewVideoStoreWithStore(1*time.Hour, db2)
+	videoStore2 := NewVideoStoreWithStore(VideoRetention{}, db2)
 
 	// Add the same video again (this would happen when RSS feeds are refreshed on restart)
 	videoStore2.AddVideo("channel1", testVideo)
@@ -101,7 +101,7 @@ func TestVideoStore_MultiplePersistentWatchedVideos(t *testing.T) {
 	defer db.Close()
 
 	// Create video store
-	videoStore := NewVideoStoreWithStore(1*time.Hour, db)
+	videoStore := NewVideoStoreWithStore(VideoRetention{}, db)
 
 	// Add multiple test videos
 	testVideo1 := rss.Entry{
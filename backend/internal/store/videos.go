@@ -0,0 +1,232 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"youtube-curator-v2/internal/rss"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// videoKeyPrefix prefixes video catalogue keys, which are followed by the full yt:video: ID
+const videoKeyPrefix = "video/"
+
+// VideoRetention limits how many videos the catalogue keeps. Zero values mean no limit.
+type VideoRetention struct {
+	MaxAge        time.Duration // Remove videos published longer ago than this
+	MaxPerChannel int           // Keep only the newest videos of each channel
+}
+
+func videoKey(videoID string) []byte {
+	return []byte(videoKeyPrefix + videoID)
+}
+
+// SaveVideo adds or updates a video in the catalogue. Enrichment data from an earlier
+// save is kept when the new entry doesn't carry it, e.g. when a plain RSS refresh
+// follows a yt-dlp enrichment.
+func (s *BadgerStore) SaveVideo(video VideoEntry) error {
+	if video.Entry.ID == "" {
+		return fmt.Errorf("video must have an ID")
+	}
+
+	// Watched state and summaries live under their own keys and are joined on read
+	video.Watched = false
+	video.Entry.Summary = nil
+
+	return s.db.Update(func(txn *badger.Txn) error {
+		existing, err := getVideoRecord(txn, video.Entry.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			mergeEnrichment(&video.Entry, existing.Entry)
+			if video.DiscoveredAt.IsZero() {
+				video.DiscoveredAt = existing.DiscoveredAt
+			}
+		}
+		if video.DiscoveredAt.IsZero() {
+			video.DiscoveredAt = video.CachedAt
+		}
+
+		videoBytes, err := json.Marshal(video)
+		if err != nil {
+			return fmt.Errorf("failed to marshal video: %w", err)
+		}
+		return txn.Set(videoKey(video.Entry.ID), videoBytes)
+	})
+}
+
+// GetVideo retrieves a video from the catalogue, returning nil if it doesn't exist
+func (s *BadgerStore) GetVideo(videoID string) (*VideoEntry, error) {
+	var video *VideoEntry
+	err := s.db.View(func(txn *badger.Txn) error {
+		record, err := getVideoRecord(txn, videoID)
+		if err != nil || record == nil {
+			return err
+		}
+		if err := joinVideoState(txn, record); err != nil {
+			return err
+		}
+		video = record
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return video, nil
+}
+
+// GetVideos retrieves every video in the catalogue with its watched state and stored summary
+func (s *BadgerStore) GetVideos() ([]VideoEntry, error) {
+	var videos []VideoEntry
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(videoKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var video VideoEntry
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &video)
+			}); err != nil {
+				return fmt.Errorf("failed to read video: %w", err)
+			}
+			if err := joinVideoState(txn, &video); err != nil {
+				return err
+			}
+			videos = append(videos, video)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get videos: %w", err)
+	}
+	return videos, nil
+}
+
+// PruneVideos removes videos that fall outside the retention limits and returns how many were removed.
+// Watched state and summaries are kept so they still apply if a video is fetched again.
+func (s *BadgerStore) PruneVideos(retention VideoRetention) (int, error) {
+	if retention.MaxAge <= 0 && retention.MaxPerChannel <= 0 {
+		return 0, nil
+	}
+
+	videos, err := s.GetVideos()
+	if err != nil {
+		return 0, err
+	}
+
+	byChannel := make(map[string][]VideoEntry)
+	for _, video := range videos {
+		byChannel[video.ChannelID] = append(byChannel[video.ChannelID], video)
+	}
+
+	cutoff := time.Now().Add(-retention.MaxAge)
+	var expired []string
+	for _, channelVideos := range byChannel {
+		// Newest first, so everything past MaxPerChannel is the oldest
+		sort.Slice(channelVideos, func(i, j int) bool {
+			return channelVideos[i].Entry.Published.After(channelVideos[j].Entry.Published)
+		})
+		for i, video := range channelVideos {
+			tooOld := retention.MaxAge > 0 && video.Entry.Published.Before(cutoff)
+			overLimit := retention.MaxPerChannel > 0 && i >= retention.MaxPerChannel
+			if tooOld || overLimit {
+				expired = append(expired, video.Entry.ID)
+			}
+		}
+	}
+
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, videoID := range expired {
+		if err := wb.Delete(videoKey(videoID)); err != nil {
+			return 0, fmt.Errorf("failed to remove video %s: %w", videoID, err)
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to remove expired videos: %w", err)
+	}
+	return len(expired), nil
+}
+
+// getVideoRecord reads a stored video within a transaction, returning nil if it doesn't exist
+func getVideoRecord(txn *badger.Txn, videoID string) (*VideoEntry, error) {
+	item, err := txn.Get(videoKey(videoID))
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video %s: %w", videoID, err)
+	}
+
+	video := &VideoEntry{}
+	if err := item.Value(func(val []byte) error {
+		return json.Unmarshal(val, video)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read video %s: %w", videoID, err)
+	}
+	return video, nil
+}
+
+// joinVideoState fills in the watched state and stored summary of a video
+func joinVideoState(txn *badger.Txn, video *VideoEntry) error {
+	_, err := txn.Get(watchedKey(video.Entry.ID))
+	switch {
+	case err == nil:
+		video.Watched = true
+	case err != badger.ErrKeyNotFound:
+		return fmt.Errorf("failed to get watched state for video %s: %w", video.Entry.ID, err)
+	}
+
+	item, err := txn.Get(summaryKey(video.Entry.ID))
+	if err == badger.ErrKeyNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get summary for video %s: %w", video.Entry.ID, err)
+	}
+	var summary StoredSummary
+	if err := item.Value(func(val []byte) error {
+		return json.Unmarshal(val, &summary)
+	}); err != nil {
+		return fmt.Errorf("failed to read summary for video %s: %w", video.Entry.ID, err)
+	}
+	video.Entry.Summary = &rss.Summary{
+		Text:               summary.Text,
+		SourceLanguage:     summary.SourceLanguage,
+		SummaryGeneratedAt: summary.GeneratedAt,
+	}
+	return nil
+}
+
+// mergeEnrichment copies yt-dlp enrichment fields from previous into entry where entry lacks them
+func mergeEnrichment(entry *rss.Entry, previous rss.Entry) {
+	if entry.ChannelID == "" {
+		entry.ChannelID = previous.ChannelID
+	}
+	if entry.Duration == 0 {
+		entry.Duration = previous.Duration
+	}
+	if len(entry.Tags) == 0 {
+		entry.Tags = previous.Tags
+	}
+	if len(entry.TopComments) == 0 {
+		entry.TopComments = previous.TopComments
+	}
+	if entry.AutoSubtitles == "" {
+		entry.AutoSubtitles = previous.AutoSubtitles
+	}
+	if strings.TrimSpace(entry.MediaGroup.MediaDescription) == "" {
+		entry.MediaGroup.MediaDescription = previous.MediaGroup.MediaDescription
+	}
+}
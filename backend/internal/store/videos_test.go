@@ -0,0 +1,131 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/rss"
+)
+
+func TestBadgerStore_VideoCatalogue(t *testing.T) {
+	dbPath := t.TempDir() + "/test.db"
+	db, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+
+	enriched := rss.Entry{
+		ID:            "yt:video:dQw4w9WgXcQ",
+		Title:         "Enriched title",
+		Published:     time.Now().Add(-time.Hour),
+		Duration:      212,
+		Tags:          []string{"music"},
+		AutoSubtitles: "https://example.com/subs.vtt",
+	}
+	if err := db.SaveVideo(VideoEntry{Entry: enriched, ChannelID: "UCchannel", CachedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to save video: %v", err)
+	}
+
+	// A plain RSS refresh keeps the enrichment data from the earlier save
+	refreshed := rss.Entry{ID: enriched.ID, Title: "Refreshed title", Published: enriched.Published}
+	if err := db.SaveVideo(VideoEntry{Entry: refreshed, ChannelID: "UCchannel", CachedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to save refreshed video: %v", err)
+	}
+	if err := db.SetVideoWatched(enriched.ID); err != nil {
+		t.Fatalf("Failed to mark video as watched: %v", err)
+	}
+	generatedAt := time.Now().UTC().Truncate(time.Second)
+	if err := db.SetSummary(&StoredSummary{VideoID: enriched.ID, Text: "A summary", SourceLanguage: "en", GeneratedAt: generatedAt}); err != nil {
+		t.Fatalf("Failed to store summary: %v", err)
+	}
+	db.Close()
+
+	// Videos survive a restart
+	db, err = NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	video, err := db.GetVideo(enriched.ID)
+	if err != nil {
+		t.Fatalf("Failed to get video: %v", err)
+	}
+	if video == nil {
+		t.Fatal("Expected video to be persisted")
+	}
+	if video.Entry.Title != "Refreshed title" {
+		t.Errorf("Expected refreshed title, got %q", video.Entry.Title)
+	}
+	if video.Entry.Duration != 212 || video.Entry.AutoSubtitles == "" || len(video.Entry.Tags) != 1 {
+		t.Errorf("Expected enrichment data to be kept, got %+v", video.Entry)
+	}
+	if !video.Watched {
+		t.Error("Expected video to be watched")
+	}
+	if video.Entry.Summary == nil || video.Entry.Summary.Text != "A summary" || !video.Entry.Summary.SummaryGeneratedAt.Equal(generatedAt) {
+		t.Errorf("Expected stored summary to be joined, got %+v", video.Entry.Summary)
+	}
+	if video.DiscoveredAt.IsZero() {
+		t.Error("Expected DiscoveredAt to be set")
+	}
+
+	missing, err := db.GetVideo("yt:video:unknown0000")
+	if err != nil || missing != nil {
+		t.Errorf("Expected nil for unknown video, got %+v (err %v)", missing, err)
+	}
+}
+
+func TestBadgerStore_PruneVideos(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	videos := []VideoEntry{
+		{ChannelID: "UCa", Entry: rss.Entry{ID: "yt:video:a1", Published: now.Add(-1 * time.Hour)}},
+		{ChannelID: "UCa", Entry: rss.Entry{ID: "yt:video:a2", Published: now.Add(-2 * time.Hour)}},
+		{ChannelID: "UCa", Entry: rss.Entry{ID: "yt:video:a3", Published: now.Add(-3 * time.Hour)}},
+		{ChannelID: "UCb", Entry: rss.Entry{ID: "yt:video:b1", Published: now.Add(-1 * time.Hour)}},
+		{ChannelID: "UCb", Entry: rss.Entry{ID: "yt:video:b2", Published: now.Add(-48 * time.Hour)}},
+	}
+	for _, video := range videos {
+		video.CachedAt = now
+		if err := db.SaveVideo(video); err != nil {
+			t.Fatalf("Failed to save video: %v", err)
+		}
+	}
+
+	// No limits keeps everything
+	removed, err := db.PruneVideos(VideoRetention{})
+	if err != nil || removed != 0 {
+		t.Fatalf("Expected nothing pruned without limits, got %d (err %v)", removed, err)
+	}
+
+	removed, err = db.PruneVideos(VideoRetention{MaxAge: 24 * time.Hour, MaxPerChannel: 2})
+	if err != nil {
+		t.Fatalf("Failed to prune videos: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 videos pruned, got %d", removed)
+	}
+
+	remaining, err := db.GetVideos()
+	if err != nil {
+		t.Fatalf("Failed to get videos: %v", err)
+	}
+	kept := make(map[string]bool)
+	for _, video := range remaining {
+		kept[video.Entry.ID] = true
+	}
+	for _, id := range []string{"yt:video:a1", "yt:video:a2", "yt:video:b1"} {
+		if !kept[id] {
+			t.Errorf("Expected %s to be kept", id)
+		}
+	}
+	if len(remaining) != 3 {
+		t.Errorf("Expected 3 videos to remain, got %d", len(remaining))
+	}
+}
@@ -0,0 +1,295 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+const (
+	// webhookKeyPrefix prefixes outgoing webhook keys, which are followed by the webhook ID
+	webhookKeyPrefix = "webhook:"
+	// webhookDeliveryKeyPrefix prefixes webhook delivery keys, which are followed by the delivery ID.
+	// Delivery IDs start with the hex creation time so keys sort in the order events were fired.
+	webhookDeliveryKeyPrefix = "webhook_delivery:"
+)
+
+// WebhookDeliveryHistoryLimit is the number of finished webhook deliveries kept when the log is pruned
+const WebhookDeliveryHistoryLimit = 1000
+
+// Webhook is an outgoing webhook subscription: events of the subscribed types are posted to URL
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // Key the payloads are signed with
+	Events    []string  `json:"events"`           // Subscribed event types, e.g. video.discovered
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Subscribes reports whether the webhook wants events of the given type
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus represents the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending" // Being delivered
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed" // Delivery gave up
+)
+
+// WebhookDelivery records an event posted, or being posted, to a webhook
+type WebhookDelivery struct {
+	ID          string                `json:"id"`
+	WebhookID   string                `json:"webhookId"`
+	Event       string                `json:"event"`
+	URL         string                `json:"url"`     // Where the event was posted, as the webhook's URL may change
+	Payload     string                `json:"payload"` // The JSON body that was posted
+	Status      WebhookDeliveryStatus `json:"status"`
+	Attempts    int                   `json:"attempts"`
+	StatusCode  int                   `json:"statusCode,omitempty"` // HTTP status of the last failed attempt, if the endpoint answered
+	Error       string                `json:"error,omitempty"`      // Last delivery error
+	CreatedAt   time.Time             `json:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt"`
+	DeliveredAt *time.Time            `json:"deliveredAt,omitempty"`
+}
+
+// NewWebhookDelivery creates a pending delivery of an event to webhook, created now. The caller
+// sets the payload, which carries the delivery ID.
+func NewWebhookDelivery(webhook Webhook, event string) (*WebhookDelivery, error) {
+	now := time.Now()
+	id, err := newTimeOrderedID(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook delivery ID: %w", err)
+	}
+	return &WebhookDelivery{
+		ID:        id,
+		WebhookID: webhook.ID,
+		Event:     event,
+		URL:       webhook.URL,
+		Status:    WebhookDeliveryPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func webhookKey(webhookID string) []byte {
+	return []byte(webhookKeyPrefix + webhookID)
+}
+
+func webhookDeliveryKey(deliveryID string) []byte {
+	return []byte(webhookDeliveryKeyPrefix + deliveryID)
+}
+
+// GetWebhook retrieves a webhook by ID, returning nil if it doesn't exist
+func (s *BadgerStore) GetWebhook(webhookID string) (*Webhook, error) {
+	var webhook *Webhook
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(webhookKey(webhookID))
+		if err == badger.ErrKeyNotFound {
+			return nil // Unknown webhook
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get webhook %s: %w", webhookID, err)
+		}
+		return item.Value(func(val []byte) error {
+			webhook = &Webhook{}
+			return json.Unmarshal(val, webhook)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetWebhooks retrieves all webhooks, ordered by creation time
+func (s *BadgerStore) GetWebhooks() ([]Webhook, error) {
+	var webhooks []Webhook
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(webhookKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var webhook Webhook
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &webhook)
+			}); err != nil {
+				return fmt.Errorf("failed to read webhook: %w", err)
+			}
+			webhooks = append(webhooks, webhook)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+	return webhooks, nil
+}
+
+// SaveWebhook creates or updates a webhook
+func (s *BadgerStore) SaveWebhook(webhook *Webhook) error {
+	if webhook == nil || webhook.ID == "" {
+		return fmt.Errorf("webhook must have an ID")
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		webhookBytes, err := json.Marshal(webhook)
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook: %w", err)
+		}
+		return txn.Set(webhookKey(webhook.ID), webhookBytes)
+	})
+}
+
+// DeleteWebhook removes a webhook; deleting an unknown webhook is not an error. Its deliveries
+// stay in the log until they are pruned.
+func (s *BadgerStore) DeleteWebhook(webhookID string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(webhookKey(webhookID))
+	})
+}
+
+// GetWebhookDelivery retrieves a webhook delivery by ID, returning nil if it doesn't exist
+func (s *BadgerStore) GetWebhookDelivery(deliveryID string) (*WebhookDelivery, error) {
+	var delivery *WebhookDelivery
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(webhookDeliveryKey(deliveryID))
+		if err == badger.ErrKeyNotFound {
+			return nil // Unknown delivery
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get webhook delivery %s: %w", deliveryID, err)
+		}
+		return item.Value(func(val []byte) error {
+			delivery = &WebhookDelivery{}
+			return json.Unmarshal(val, delivery)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// GetWebhookDeliveries retrieves up to limit deliveries of the given webhook, or of every webhook
+// when webhookID is empty, newest first. A limit of 0 returns them all.
+func (s *BadgerStore) GetWebhookDeliveries(webhookID string, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(webhookDeliveryKeyPrefix)
+		opts.Reverse = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(append([]byte(webhookDeliveryKeyPrefix), 0xFF)); it.Valid(); it.Next() {
+			var delivery WebhookDelivery
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &delivery)
+			}); err != nil {
+				return fmt.Errorf("failed to read webhook delivery: %w", err)
+			}
+			if webhookID != "" && delivery.WebhookID != webhookID {
+				continue
+			}
+			deliveries = append(deliveries, delivery)
+			if limit > 0 && len(deliveries) >= limit {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// SaveWebhookDelivery creates or updates a webhook delivery
+func (s *BadgerStore) SaveWebhookDelivery(delivery *WebhookDelivery) error {
+	if delivery == nil || delivery.ID == "" {
+		return fmt.Errorf("webhook delivery must have an ID")
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		deliveryBytes, err := json.Marshal(delivery)
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook delivery: %w", err)
+		}
+		return txn.Set(webhookDeliveryKey(delivery.ID), deliveryBytes)
+	})
+}
+
+// PruneWebhookDeliveries removes all but the most recent keep finished deliveries, returning how
+// many were removed. Pending deliveries are never removed.
+func (s *BadgerStore) PruneWebhookDeliveries(keep int) (int, error) {
+	var stale [][]byte
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(webhookDeliveryKeyPrefix)
+		opts.Reverse = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		finished := 0
+		for it.Seek(append([]byte(webhookDeliveryKeyPrefix), 0xFF)); it.Valid(); it.Next() {
+			var delivery WebhookDelivery
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &delivery)
+			}); err != nil {
+				return fmt.Errorf("failed to read webhook delivery: %w", err)
+			}
+			if delivery.Status == WebhookDeliveryPending {
+				continue
+			}
+			finished++
+			if finished > keep {
+				stale = append(stale, it.Item().KeyCopy(nil))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, key := range stale {
+		if err := wb.Delete(key); err != nil {
+			return 0, fmt.Errorf("failed to delete webhook delivery: %w", err)
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return len(stale), nil
+}
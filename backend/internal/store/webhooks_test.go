@@ -0,0 +1,142 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBadgerStore_WebhookPersistence(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	webhook, err := db.GetWebhook("missing")
+	if err != nil {
+		t.Fatalf("Unexpected error getting missing webhook: %v", err)
+	}
+	if webhook != nil {
+		t.Fatalf("Expected nil webhook for unknown ID, got %+v", webhook)
+	}
+
+	now := time.Now()
+	webhooks := []*Webhook{
+		{ID: "b", URL: "https://b.example.com/hook", Secret: "s3cret", Events: []string{"video.discovered"}, Enabled: true, CreatedAt: now},
+		{ID: "a", URL: "https://a.example.com/hook", Events: []string{"newsletter.sent"}, CreatedAt: now.Add(-time.Minute)},
+	}
+	for _, w := range webhooks {
+		if err := db.SaveWebhook(w); err != nil {
+			t.Fatalf("Failed to save webhook %s: %v", w.ID, err)
+		}
+	}
+
+	webhook, err = db.GetWebhook("b")
+	if err != nil {
+		t.Fatalf("Failed to get webhook: %v", err)
+	}
+	if webhook == nil || webhook.Secret != "s3cret" || !webhook.Subscribes("video.discovered") || webhook.Subscribes("newsletter.sent") {
+		t.Fatalf("Unexpected webhook: %+v", webhook)
+	}
+
+	all, err := db.GetWebhooks()
+	if err != nil {
+		t.Fatalf("Failed to list webhooks: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "a" || all[1].ID != "b" {
+		t.Fatalf("Expected webhooks ordered oldest first, got %+v", all)
+	}
+
+	// Deliveries use a separate prefix and never show up as webhooks
+	delivery, err := NewWebhookDelivery(*webhook, "video.discovered")
+	if err != nil {
+		t.Fatalf("Failed to create webhook delivery: %v", err)
+	}
+	if err := db.SaveWebhookDelivery(delivery); err != nil {
+		t.Fatalf("Failed to save webhook delivery: %v", err)
+	}
+
+	if err := db.DeleteWebhook("a"); err != nil {
+		t.Fatalf("Failed to delete webhook: %v", err)
+	}
+	if err := db.DeleteWebhook("missing"); err != nil {
+		t.Fatalf("Deleting an unknown webhook should not fail: %v", err)
+	}
+	all, err = db.GetWebhooks()
+	if err != nil {
+		t.Fatalf("Failed to list webhooks: %v", err)
+	}
+	if len(all) != 1 || all[0].ID != "b" {
+		t.Errorf("Expected only webhook b to remain, got %+v", all)
+	}
+
+	if err := db.SaveWebhook(&Webhook{}); err == nil {
+		t.Error("Expected an error saving a webhook without an ID")
+	}
+}
+
+func TestBadgerStore_WebhookDeliveries(t *testing.T) {
+	db, err := NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	var ids []string
+	for i, webhookID := range []string{"a", "b", "a", "a"} {
+		delivery, err := NewWebhookDelivery(Webhook{ID: webhookID, URL: "https://example.com/" + webhookID}, "video.discovered")
+		if err != nil {
+			t.Fatalf("Failed to create webhook delivery: %v", err)
+		}
+		if i < 3 {
+			delivery.Status = WebhookDeliveryDelivered
+		}
+		if err := db.SaveWebhookDelivery(delivery); err != nil {
+			t.Fatalf("Failed to save webhook delivery: %v", err)
+		}
+		ids = append(ids, delivery.ID)
+		time.Sleep(time.Millisecond) // Distinct creation times
+	}
+
+	delivery, err := db.GetWebhookDelivery(ids[1])
+	if err != nil || delivery == nil {
+		t.Fatalf("Failed to get webhook delivery: %v", err)
+	}
+	if delivery.WebhookID != "b" || delivery.URL != "https://example.com/b" || delivery.Status != WebhookDeliveryDelivered {
+		t.Errorf("Unexpected webhook delivery: %+v", delivery)
+	}
+	if delivery, err := db.GetWebhookDelivery("missing"); err != nil || delivery != nil {
+		t.Errorf("Expected nil for an unknown delivery, got %+v (%v)", delivery, err)
+	}
+
+	all, err := db.GetWebhookDeliveries("", 0)
+	if err != nil {
+		t.Fatalf("Failed to list webhook deliveries: %v", err)
+	}
+	if len(all) != 4 || all[0].ID != ids[3] || all[3].ID != ids[0] {
+		t.Fatalf("Expected all deliveries newest first, got %+v", all)
+	}
+	forA, err := db.GetWebhookDeliveries("a", 2)
+	if err != nil {
+		t.Fatalf("Failed to list webhook deliveries: %v", err)
+	}
+	if len(forA) != 2 || forA[0].ID != ids[3] || forA[1].ID != ids[2] {
+		t.Errorf("Expected the 2 latest deliveries to webhook a, got %+v", forA)
+	}
+
+	// The pending delivery is kept whatever the limit
+	removed, err := db.PruneWebhookDeliveries(1)
+	if err != nil {
+		t.Fatalf("Failed to prune webhook deliveries: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 deliveries to be removed, got %d", removed)
+	}
+	all, err = db.GetWebhookDeliveries("", 0)
+	if err != nil {
+		t.Fatalf("Failed to list webhook deliveries: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != ids[3] || all[1].ID != ids[2] {
+		t.Errorf("Expected the pending and latest delivered deliveries to remain, got %+v", all)
+	}
+}
@@ -0,0 +1,140 @@
+package summary
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+)
+
+// BatchOptions bounds the work done when summarising several videos at once
+type BatchOptions struct {
+	Concurrency int           // Summaries generated at the same time, at least 1
+	Budget      time.Duration // Total time allowed for the batch; 0 means no limit
+}
+
+// SummarizeEntries gets or generates a summary for each entry and attaches it to entry.Summary.
+// Entries sharing a video ID are summarised once. Entries whose summary fails, or isn't ready when
+// the budget runs out, are left unchanged so callers can fall back to the description.
+// It returns the number of videos summarised.
+func SummarizeEntries(ctx context.Context, service SummaryServiceInterface, entries []*rss.Entry, opts BatchOptions) int {
+	if service == nil || len(entries) == 0 {
+		return 0
+	}
+
+	if opts.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Budget)
+		defer cancel()
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		summarised int
+		closed     bool // Set once the caller has the entries back, so late results are dropped
+	)
+	slots := make(chan struct{}, concurrency)
+
+	// The same video can appear in several personalised digests
+	var videoIDs []string
+	byID := make(map[string][]*rss.Entry)
+	for _, entry := range entries {
+		if _, seen := byID[entry.ID]; !seen {
+			videoIDs = append(videoIDs, entry.ID)
+		}
+		byID[entry.ID] = append(byID[entry.ID], entry)
+	}
+
+	for _, videoID := range videoIDs {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(videoID string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result := service.GetOrGenerateSummary(ctx, videoID)
+			if result.Error != nil {
+				log.Printf("Warning: No summary for video %s: %v", videoID, result.Error)
+				return
+			}
+			if result.Summary == "" {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if closed {
+				return
+			}
+			for _, entry := range byID[videoID] {
+				entry.Summary = &rss.Summary{
+					Text:               result.Summary,
+					SourceLanguage:     result.SourceLanguage,
+					SummaryGeneratedAt: result.GeneratedAt,
+				}
+			}
+			summarised++
+		}(videoID)
+	}
+
+	// Stop waiting for stragglers once the budget is spent
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("Warning: Summary budget exhausted, sending the digest without the remaining summaries")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	closed = true
+	return summarised
+}
+
+// AttachStoredSummaries attaches the stored summary of each entry that has one, without generating
+// any, so nothing is sent to the LLM or persisted. It returns the number of videos summarised.
+func AttachStoredSummaries(summaries store.Store, entries []*rss.Entry) int {
+	summarised := 0
+	lookedUp := make(map[string]*rss.Summary) // The same video can appear in several personalised digests
+	for _, entry := range entries {
+		attached, seen := lookedUp[entry.ID]
+		if !seen {
+			stored, err := summaries.GetSummary(entry.ID)
+			if err != nil {
+				log.Printf("Warning: Failed to look up stored summary for video %s: %v", entry.ID, err)
+			}
+			if stored != nil && stored.Text != "" {
+				attached = &rss.Summary{
+					Text:               stored.Text,
+					SourceLanguage:     stored.SourceLanguage,
+					SummaryGeneratedAt: stored.GeneratedAt,
+				}
+				summarised++
+			}
+			lookedUp[entry.ID] = attached
+		}
+		if attached != nil {
+			entry.Summary = attached
+		}
+	}
+	return summarised
+}
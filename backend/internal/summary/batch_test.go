@@ -0,0 +1,112 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/rss"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSummaryService returns canned results after an optional delay, tracking peak concurrency
+type stubSummaryService struct {
+	delay   time.Duration
+	failing map[string]bool
+
+	mu      sync.Mutex
+	running int
+	peak    int
+	calls   atomic.Int32
+}
+
+func (s *stubSummaryService) GetOrGenerateSummary(ctx context.Context, videoID string) *SummaryResult {
+	s.calls.Add(1)
+	s.mu.Lock()
+	s.running++
+	if s.running > s.peak {
+		s.peak = s.running
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running--
+		s.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return &SummaryResult{VideoID: videoID, Error: ctx.Err()}
+	}
+	if s.failing[videoID] {
+		return &SummaryResult{VideoID: videoID, Error: errors.New("no subtitles available for video")}
+	}
+	return &SummaryResult{VideoID: videoID, Summary: "Summary of " + videoID, SourceLanguage: "en"}
+}
+
+func (s *stubSummaryService) RegenerateSummary(ctx context.Context, videoID string) *SummaryResult {
+	return s.GetOrGenerateSummary(ctx, videoID)
+}
+
+func (s *stubSummaryService) StreamSummary(ctx context.Context, videoID string, regenerate bool, events chan<- StreamEvent) {
+	close(events)
+}
+
+func TestSummarizeEntries(t *testing.T) {
+	service := &stubSummaryService{
+		delay:   10 * time.Millisecond,
+		failing: map[string]bool{"yt:video:b": true},
+	}
+	entries := []*rss.Entry{{ID: "yt:video:a"}, {ID: "yt:video:b"}, {ID: "yt:video:c"}, {ID: "yt:video:d"}}
+
+	summarised := SummarizeEntries(context.Background(), service, entries, BatchOptions{Concurrency: 2})
+
+	assert.Equal(t, 3, summarised)
+	assert.LessOrEqual(t, service.peak, 2, "Should not exceed the concurrency limit")
+	assert.Nil(t, entries[1].Summary, "Failed summaries should leave the entry unchanged")
+	for _, i := range []int{0, 2, 3} {
+		if assert.NotNil(t, entries[i].Summary) {
+			assert.Equal(t, "Summary of "+entries[i].ID, entries[i].Summary.Text)
+			assert.Equal(t, "en", entries[i].Summary.SourceLanguage)
+		}
+	}
+}
+
+func TestSummarizeEntries_SharedVideos(t *testing.T) {
+	service := &stubSummaryService{}
+	first, second := &rss.Entry{ID: "yt:video:a"}, &rss.Entry{ID: "yt:video:a"}
+
+	summarised := SummarizeEntries(context.Background(), service, []*rss.Entry{first, second}, BatchOptions{Concurrency: 2})
+
+	assert.Equal(t, 1, summarised)
+	assert.Equal(t, int32(1), service.calls.Load(), "A video in several digests should be summarised once")
+	if assert.NotNil(t, first.Summary) && assert.NotNil(t, second.Summary) {
+		assert.Equal(t, first.Summary.Text, second.Summary.Text)
+	}
+}
+
+func TestSummarizeEntries_Budget(t *testing.T) {
+	service := &stubSummaryService{delay: time.Second}
+	entries := []*rss.Entry{{ID: "yt:video:a"}, {ID: "yt:video:b"}, {ID: "yt:video:c"}}
+
+	start := time.Now()
+	summarised := SummarizeEntries(context.Background(), service, entries, BatchOptions{Concurrency: 1, Budget: 50 * time.Millisecond})
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "Should return once the budget is spent")
+	assert.Equal(t, 0, summarised)
+	assert.Equal(t, int32(1), service.calls.Load(), "Should not start new summaries after the budget is spent")
+	for _, entry := range entries {
+		assert.Nil(t, entry.Summary)
+	}
+}
+
+func TestSummarizeEntries_NoService(t *testing.T) {
+	entries := []*rss.Entry{{ID: "yt:video:a"}}
+	assert.Equal(t, 0, SummarizeEntries(context.Background(), nil, entries, BatchOptions{}))
+	assert.Nil(t, entries[0].Summary)
+}
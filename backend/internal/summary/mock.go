@@ -3,13 +3,16 @@ package summary
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
-	"youtube-curator-v2/internal/rss"
 	"youtube-curator-v2/internal/store"
 )
 
+// mockModelName is reported as the model for mock summaries
+const mockModelName = "mock"
+
 // MockService provides a mock implementation for development and testing
 type MockService struct {
 	store store.Store
@@ -24,18 +27,30 @@ func NewMockService(store store.Store) *MockService {
 
 // GetOrGenerateSummary generates a mock summary for any video ID
 func (ms *MockService) GetOrGenerateSummary(ctx context.Context, videoID string) *SummaryResult {
+	return ms.getOrGenerateSummary(videoID, false)
+}
+
+// RegenerateSummary generates a fresh mock summary, ignoring any stored summary
+func (ms *MockService) RegenerateSummary(ctx context.Context, videoID string) *SummaryResult {
+	return ms.getOrGenerateSummary(videoID, true)
+}
+
+// getOrGenerateSummary returns a stored summary when available unless regenerate is set
+func (ms *MockService) getOrGenerateSummary(videoID string, regenerate bool) *SummaryResult {
 	result := &SummaryResult{
 		VideoID: videoID,
 	}
 
-	// Check if we have existing summary in tracked videos
-	summary, tracked := ms.findExistingSummary(videoID)
-	if summary != nil {
-		result.Summary = summary.Text
-		result.SourceLanguage = summary.SourceLanguage
-		result.GeneratedAt = summary.SummaryGeneratedAt
-		result.Tracked = tracked
-		return result
+	// Check if we have an existing summary in the store
+	if !regenerate {
+		if summary := ms.findExistingSummary(videoID); summary != nil {
+			result.Summary = summary.Text
+			result.Thinking = summary.Thinking
+			result.SourceLanguage = summary.SourceLanguage
+			result.Model = summary.Model
+			result.GeneratedAt = summary.GeneratedAt
+			return result
+		}
 	}
 
 	// Generate mock summary
@@ -43,17 +58,71 @@ func (ms *MockService) GetOrGenerateSummary(ctx context.Context, videoID string)
 
 	result.Summary = mockSummary
 	result.SourceLanguage = "en"
+	result.Model = mockModelName
 	result.GeneratedAt = time.Now()
 	result.Tracked = false
 
+	if ms.store != nil {
+		stored := &store.StoredSummary{
+			VideoID:        videoID,
+			Text:           result.Summary,
+			SourceLanguage: result.SourceLanguage,
+			Model:          result.Model,
+			GeneratedAt:    result.GeneratedAt,
+		}
+		if err := ms.store.SetSummary(stored); err != nil {
+			log.Printf("Warning: Failed to store mock summary for video %s: %v", videoID, err)
+		}
+	}
+
 	return result
 }
 
-// findExistingSummary looks for an existing summary in tracked videos
-func (ms *MockService) findExistingSummary(videoID string) (*rss.Summary, bool) {
-	// This is a placeholder - in a real implementation, you would
-	// search through your video store or database to find existing summaries
-	return nil, false
+// StreamSummary streams a mock summary word by word after replaying the progress phases
+func (ms *MockService) StreamSummary(ctx context.Context, videoID string, regenerate bool, events chan<- StreamEvent) {
+	defer close(events)
+
+	if !regenerate {
+		if summary := ms.findExistingSummary(videoID); summary != nil {
+			replayResult(ctx, events, &SummaryResult{
+				VideoID:        videoID,
+				Summary:        summary.Text,
+				Thinking:       summary.Thinking,
+				SourceLanguage: summary.SourceLanguage,
+				Model:          summary.Model,
+				GeneratedAt:    summary.GeneratedAt,
+			})
+			return
+		}
+	}
+
+	for _, phase := range []string{PhaseFetchingMetadata, PhaseDownloadingSubtitles, PhaseGenerating} {
+		if !sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventPhase, Phase: phase}) {
+			return
+		}
+	}
+
+	result := ms.getOrGenerateSummary(videoID, true)
+	words := strings.SplitAfter(result.Summary, " ")
+	for _, word := range words {
+		if !sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventToken, Text: word}) {
+			return
+		}
+	}
+
+	sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventDone, Result: result})
+}
+
+// findExistingSummary looks up a previously stored summary, if a store is available
+func (ms *MockService) findExistingSummary(videoID string) *store.StoredSummary {
+	if ms.store == nil {
+		return nil
+	}
+	summary, err := ms.store.GetSummary(videoID)
+	if err != nil {
+		return nil
+	}
+	return summary
 }
 
 // generateMockSummary creates a mock summary based on video ID
@@ -88,13 +88,53 @@ func (m *mockStore) GetChannels() ([]store.Channel, error)
 func (m *mockStore) AddChannel(channel store.Channel) error                              { return nil }
 func (m *mockStore) RemoveChannel(channelID string) error                                { return nil }
 func (m *mockStore) GetCheckInterval() (time.Duration, error)                            { return time.Hour, nil }
+func (m *mockStore) GetScheduleInterval() (time.Duration, error)                         { return 0, nil }
 func (m *mockStore) SetCheckInterval(interval time.Duration) error                       { return nil }
 func (m *mockStore) GetSMTPConfig() (*store.SMTPConfig, error)                           { return nil, nil }
 func (m *mockStore) SetSMTPConfig(config *store.SMTPConfig) error                        { return nil }
 func (m *mockStore) GetLLMConfig() (*store.LLMConfig, error)                             { return nil, nil }
 func (m *mockStore) SetLLMConfig(config *store.LLMConfig) error                          { return nil }
-func (m *mockStore) GetNewsletterConfig() (*store.NewsletterConfig, error)              { return nil, nil }
-func (m *mockStore) SetNewsletterConfig(config *store.NewsletterConfig) error           { return nil }
-func (m *mockStore) GetWatchedVideos() ([]string, error)                           { return nil, nil }
-func (m *mockStore) SetVideoWatched(videoID string) error                           { return nil }
-func (m *mockStore) IsVideoWatched(videoID string) (bool, error)                    { return false, nil }
+func (m *mockStore) GetNewsletterConfig() (*store.NewsletterConfig, error)               { return nil, nil }
+func (m *mockStore) SetNewsletterConfig(config *store.NewsletterConfig) error            { return nil }
+func (m *mockStore) GetWatchedVideos() ([]string, error)                                 { return nil, nil }
+func (m *mockStore) SetVideoWatched(videoID string) error                                { return nil }
+func (m *mockStore) IsVideoWatched(videoID string) (bool, error)                         { return false, nil }
+func (m *mockStore) SaveVideo(video store.VideoEntry) error                              { return nil }
+func (m *mockStore) GetVideo(videoID string) (*store.VideoEntry, error)                  { return nil, nil }
+func (m *mockStore) GetVideos() ([]store.VideoEntry, error)                              { return nil, nil }
+func (m *mockStore) PruneVideos(retention store.VideoRetention) (int, error)             { return 0, nil }
+func (m *mockStore) GetSummary(videoID string) (*store.StoredSummary, error)             { return nil, nil }
+func (m *mockStore) SetSummary(summary *store.StoredSummary) error                       { return nil }
+func (m *mockStore) GetTranscript(videoID string) (*store.Transcript, error)             { return nil, nil }
+func (m *mockStore) SetTranscript(transcript *store.Transcript) error                    { return nil }
+func (m *mockStore) GetJob(jobID string) (*store.Job, error)                             { return nil, nil }
+func (m *mockStore) SaveJob(job *store.Job) error                                        { return nil }
+func (m *mockStore) GetJobsByStatus(statuses ...store.JobStatus) ([]store.Job, error) {
+	return nil, nil
+}
+func (m *mockStore) GetSubscriber(subscriberID string) (*store.Subscriber, error)    { return nil, nil }
+func (m *mockStore) GetSubscribers() ([]store.Subscriber, error)                     { return nil, nil }
+func (m *mockStore) SaveSubscriber(subscriber *store.Subscriber) error               { return nil }
+func (m *mockStore) DeleteSubscriber(subscriberID string) error                      { return nil }
+func (m *mockStore) GetRun(runID string) (*store.Run, error)                         { return nil, nil }
+func (m *mockStore) GetRuns(limit int) ([]store.Run, error)                          { return nil, nil }
+func (m *mockStore) SaveRun(run *store.Run) error                                    { return nil }
+func (m *mockStore) PruneRuns(keep int) (int, error)                                 { return 0, nil }
+func (m *mockStore) GetOutboxMessage(messageID string) (*store.OutboxMessage, error) { return nil, nil }
+func (m *mockStore) GetOutboxMessages(statuses ...store.OutboxStatus) ([]store.OutboxMessage, error) {
+	return nil, nil
+}
+func (m *mockStore) SaveOutboxMessage(message *store.OutboxMessage) error { return nil }
+func (m *mockStore) PruneOutbox(keep int) (int, error)                    { return 0, nil }
+func (m *mockStore) GetWebhook(webhookID string) (*store.Webhook, error)  { return nil, nil }
+func (m *mockStore) GetWebhooks() ([]store.Webhook, error)                { return nil, nil }
+func (m *mockStore) SaveWebhook(webhook *store.Webhook) error             { return nil }
+func (m *mockStore) DeleteWebhook(webhookID string) error                 { return nil }
+func (m *mockStore) GetWebhookDelivery(deliveryID string) (*store.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockStore) GetWebhookDeliveries(webhookID string, limit int) ([]store.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockStore) SaveWebhookDelivery(delivery *store.WebhookDelivery) error { return nil }
+func (m *mockStore) PruneWebhookDeliveries(keep int) (int, error)              { return 0, nil }
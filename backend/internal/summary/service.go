@@ -3,8 +3,10 @@ package summary
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"regexp"
 	"sort"
@@ -15,12 +17,23 @@ import (
 	"youtube-curator-v2/internal/openai"
 	"youtube-curator-v2/internal/rss"
 	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/webhooks"
 	"youtube-curator-v2/internal/ytdlp"
 )
 
+// summarySystemPrompt instructs the LLM how to summarize subtitle text
+const summarySystemPrompt = `Summarize the provided YouTube video, providing all the key points of the video and any related insights. Don't be afraid to go in-depth with the details.`
+
+// ErrLLMNotConfigured is reported when a summary has to be generated but no LLM is configured
+var ErrLLMNotConfigured = errors.New("LLM not configured")
+
 // SummaryServiceInterface defines the interface that both real and mock services implement
 type SummaryServiceInterface interface {
 	GetOrGenerateSummary(ctx context.Context, videoID string) *SummaryResult
+	// RegenerateSummary bypasses any stored summary and overwrites it with a freshly generated one
+	RegenerateSummary(ctx context.Context, videoID string) *SummaryResult
+	// StreamSummary reports progress and token deltas on events, closing it when done
+	StreamSummary(ctx context.Context, videoID string, regenerate bool, events chan<- StreamEvent)
 }
 
 // SummaryResult represents the result of a summarization operation
@@ -29,6 +42,7 @@ type SummaryResult struct {
 	Summary        string
 	Thinking       string
 	SourceLanguage string
+	Model          string
 	GeneratedAt    time.Time
 	Tracked        bool
 	Error          error
@@ -39,6 +53,13 @@ type Service struct {
 	store         store.Store
 	ytdlpEnricher ytdlp.Enricher
 	openaiClient  openai.OpenAIClient
+	publisher     webhooks.Publisher // Announces newly generated summaries; optional
+}
+
+// generationProgress receives progress from generateSummary; either callback may be nil
+type generationProgress struct {
+	Phase func(phase string) // Called as each phase of the pipeline starts
+	Chunk func(delta string) // Called with each piece of the LLM response; streams the response when set
 }
 
 // NewService creates a new summary service
@@ -50,107 +71,246 @@ func NewService(store store.Store, ytdlpEnricher ytdlp.Enricher, openaiClient op
 	}
 }
 
+// SetPublisher sets where summary.generated events are published
+func (s *Service) SetPublisher(publisher webhooks.Publisher) {
+	s.publisher = publisher
+}
+
 // GetOrGenerateSummary retrieves an existing summary or generates a new one
 func (s *Service) GetOrGenerateSummary(ctx context.Context, videoID string) *SummaryResult {
+	return s.getOrGenerateSummary(ctx, videoID, false)
+}
+
+// RegenerateSummary generates a new summary, replacing any stored summary for the video
+func (s *Service) RegenerateSummary(ctx context.Context, videoID string) *SummaryResult {
+	return s.getOrGenerateSummary(ctx, videoID, true)
+}
+
+// getOrGenerateSummary consults the summary store unless regenerate is set, and persists newly generated summaries.
+// Stored summaries stay readable without an LLM; the configuration is only required to generate one.
+func (s *Service) getOrGenerateSummary(ctx context.Context, videoID string, regenerate bool) *SummaryResult {
+	// Try to find an existing summary first
+	if !regenerate {
+		if stored := s.storedResult(videoID); stored != nil {
+			return stored
+		}
+	}
+
 	result := &SummaryResult{
 		VideoID: videoID,
 	}
 
 	// Check if we have LLM configuration
-	llmConfig, err := s.store.GetLLMConfig()
+	llmConfig, err := s.currentLLMConfig()
 	if err != nil {
-		result.Error = fmt.Errorf("failed to get LLM configuration: %w", err)
+		result.Error = err
 		return result
 	}
-	if llmConfig == nil || llmConfig.EndpointURL == "" {
-		result.Error = fmt.Errorf("LLM not configured")
+
+	// Generate new summary
+	generated, err := s.generateSummary(ctx, videoID, llmConfig, nil)
+	if err != nil {
+		result.Error = err
 		return result
 	}
+	return generated
+}
 
-	// Try to find existing summary in tracked videos first
-	// This is a simplified approach - in a real implementation,
-	// you might want to search through stored videos more efficiently
-	summary, tracked := s.findExistingSummary(videoID)
-	if summary != nil {
-		result.Summary = summary.Text
-		result.SourceLanguage = summary.SourceLanguage
-		result.GeneratedAt = summary.SummaryGeneratedAt
-		result.Tracked = tracked
-		return result
+// saveSummary persists a newly generated summary and announces it
+func (s *Service) saveSummary(stored *store.StoredSummary) {
+	if err := s.store.SetSummary(stored); err != nil {
+		log.Printf("Warning: Failed to store summary for video %s: %v", stored.VideoID, err)
+	}
+	if s.publisher != nil {
+		s.publisher.Publish(webhooks.EventSummaryGenerated, webhooks.SummaryData{
+			VideoID:        stored.VideoID,
+			ChannelID:      stored.ChannelID,
+			Summary:        stored.Text,
+			SourceLanguage: stored.SourceLanguage,
+			Model:          stored.Model,
+			GeneratedAt:    stored.GeneratedAt,
+		})
 	}
+}
 
-	// Generate new summary
-	generatedSummary, thinking, sourceLanguage, err := s.generateSummary(ctx, videoID, llmConfig)
+// storedResult returns the stored summary of a video as a result, or nil when there is none
+func (s *Service) storedResult(videoID string) *SummaryResult {
+	existing, err := s.findExistingSummary(videoID)
 	if err != nil {
-		result.Error = err
-		return result
+		log.Printf("Warning: Failed to look up stored summary for video %s: %v", videoID, err)
+	}
+	if existing == nil {
+		return nil
+	}
+	return &SummaryResult{
+		VideoID:        videoID,
+		Summary:        existing.Text,
+		Thinking:       existing.Thinking,
+		SourceLanguage: existing.SourceLanguage,
+		Model:          existing.Model,
+		GeneratedAt:    existing.GeneratedAt,
+		Tracked:        s.isTrackedChannel(existing.ChannelID),
 	}
+}
 
-	result.Summary = generatedSummary
-	result.Thinking = thinking
-	result.SourceLanguage = sourceLanguage
-	result.GeneratedAt = time.Now()
-	result.Tracked = false // For now, arbitrary videos are not tracked
+// currentLLMConfig returns the LLM configuration to generate summaries with, or ErrLLMNotConfigured
+func (s *Service) currentLLMConfig() (*store.LLMConfig, error) {
+	llmConfig, err := s.store.GetLLMConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LLM configuration: %w", err)
+	}
+	if llmConfig == nil || llmConfig.EndpointURL == "" {
+		return nil, ErrLLMNotConfigured
+	}
+	return llmConfig, nil
+}
 
-	return result
+// findExistingSummary looks up a previously generated summary in the store
+func (s *Service) findExistingSummary(videoID string) (*store.StoredSummary, error) {
+	return s.store.GetSummary(videoID)
 }
 
-// findExistingSummary looks for an existing summary in tracked videos
-func (s *Service) findExistingSummary(videoID string) (*rss.Summary, bool) {
-	// This is a placeholder - in a real implementation, you would
-	// search through your video store or database to find existing summaries
-	// For now, we'll return nil to always generate new summaries
-	return nil, false
+// isTrackedChannel reports whether the channel is one of the configured channels
+func (s *Service) isTrackedChannel(channelID string) bool {
+	return IsTrackedChannel(s.store, channelID)
 }
 
-// generateSummary generates a new summary for a video
-func (s *Service) generateSummary(ctx context.Context, videoID string, llmConfig *store.LLMConfig) (string, string, string, error) {
-	// Create a temporary entry to enrich with subtitles
-	entry := &rss.Entry{
-		ID: videoID, // videoID should already be in yt:video:ID format from the handler
+// IsTrackedChannel reports whether the channel is one of the channels configured in the store
+func IsTrackedChannel(s store.Store, channelID string) bool {
+	if channelID == "" {
+		return false
 	}
 
-	// Use yt-dlp to fetch subtitles
-	err := s.ytdlpEnricher.EnrichEntry(ctx, entry)
+	channels, err := s.GetChannels()
 	if err != nil {
-		return "", "", "", fmt.Errorf("failed to fetch video metadata: %w", err)
+		log.Printf("Warning: Failed to get channels while checking tracked state: %v", err)
+		return false
 	}
 
-	// Check if we have subtitles
-	if entry.AutoSubtitles == "" {
-		return "", "", "", fmt.Errorf("no subtitles available for video")
+	for _, channel := range channels {
+		if channel.ID == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// generateSummary generates a new summary for a video: it fetches the video's subtitles, asks the
+// LLM to summarize them and persists the result. progress, when set, is told about each phase and
+// every piece of the LLM response as it arrives.
+func (s *Service) generateSummary(ctx context.Context, videoID string, llmConfig *store.LLMConfig, progress *generationProgress) (*SummaryResult, error) {
+	if progress == nil {
+		progress = &generationProgress{}
+	}
+	phase := func(name string) {
+		if progress.Phase != nil {
+			progress.Phase(name)
+		}
+	}
+
+	phase(PhaseFetchingMetadata)
+	entry, err := s.fetchMetadata(ctx, videoID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Fetch and parse actual subtitle content from the URL
-	subtitleText, err := s.fetchSubtitleText(ctx, entry.AutoSubtitles)
+	phase(PhaseDownloadingSubtitles)
+	subtitleText, err := s.fetchSubtitles(ctx, videoID, entry.AutoSubtitles)
 	if err != nil {
-		return "", "", "", fmt.Errorf("failed to fetch subtitle content: %w", err)
+		return nil, fmt.Errorf("failed to fetch subtitle content: %w", err)
 	}
 
 	// Generate summary using LLM
-	rawResponse, err := s.callLLMForSummary(ctx, subtitleText, llmConfig)
+	phase(PhaseGenerating)
+	rawResponse, err := s.callLLMForSummary(ctx, subtitleText, llmConfig, progress.Chunk)
 	if err != nil {
-		return "", "", "", fmt.Errorf("failed to generate summary: %w", err)
+		return nil, fmt.Errorf("failed to generate summary: %w", err)
 	}
 
 	// Parse thinking blocks from the response
 	thinking, summary := parseThinkingBlocks(rawResponse)
 
-	// Assume English for now - in a real implementation, you'd detect language
-	sourceLanguage := "en"
+	result := &SummaryResult{
+		VideoID:        videoID,
+		Summary:        summary,
+		Thinking:       thinking,
+		SourceLanguage: "en", // Assume English for now - in a real implementation, you'd detect language
+		Model:          llmConfig.Model,
+		GeneratedAt:    time.Now(),
+		Tracked:        s.isTrackedChannel(entry.ChannelID),
+	}
+
+	// Persist the summary so subsequent requests don't hit yt-dlp and the LLM again
+	s.saveSummary(&store.StoredSummary{
+		VideoID:        videoID,
+		ChannelID:      entry.ChannelID,
+		Text:           result.Summary,
+		Thinking:       result.Thinking,
+		SourceLanguage: result.SourceLanguage,
+		Model:          result.Model,
+		GeneratedAt:    result.GeneratedAt,
+	})
 
-	return summary, thinking, sourceLanguage, nil
+	return result, nil
+}
+
+// fetchMetadata uses yt-dlp to look up a video's channel and subtitle URL
+func (s *Service) fetchMetadata(ctx context.Context, videoID string) (*rss.Entry, error) {
+	// Create a temporary entry to enrich with subtitles
+	entry := &rss.Entry{
+		ID: videoID, // videoID should already be in yt:video:ID format from the handler
+	}
+
+	if err := s.ytdlpEnricher.EnrichEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to fetch video metadata: %w", err)
+	}
+
+	// Check if we have subtitles
+	if entry.AutoSubtitles == "" {
+		return nil, fmt.Errorf("no subtitles available for video")
+	}
+	return entry, nil
 }
 
-// callLLMForSummary calls the LLM to generate a summary
-func (s *Service) callLLMForSummary(ctx context.Context, subtitleText string, llmConfig *store.LLMConfig) (string, error) {
+// callLLMForSummary calls the LLM to generate a summary. With onChunk set the response is
+// streamed and each delta is passed to onChunk as it arrives.
+func (s *Service) callLLMForSummary(ctx context.Context, subtitleText string, llmConfig *store.LLMConfig, onChunk func(delta string)) (string, error) {
 	// Create OpenAI client with the configured settings
 	client := openai.New(llmConfig.EndpointURL, llmConfig.APIKey, llmConfig.Model)
 
-	systemPrompt := `Summarize the provided YouTube video, providing all the key points of the video and any related insights. Don't be afraid to go in-depth with the details.`
+	systemPrompt := summarySystemPrompt
 
 	userPrompt := subtitleText
 
+	if onChunk != nil {
+		deltas := make(chan customerrors.ErrorString)
+		go client.ChatCompletionStream(
+			ctx,
+			systemPrompt,
+			[]string{userPrompt},
+			nil, // no images
+			nil, // no schema
+			0.7, // temperature
+			0,   // no max tokens limit
+			deltas,
+		)
+
+		var response strings.Builder
+		for delta := range deltas {
+			if delta.Err != nil {
+				return "", delta.Err
+			}
+			response.WriteString(delta.Value)
+			onChunk(delta.Value)
+		}
+		// The stream ends early when ctx is cancelled; don't mistake a partial response for a summary
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		return response.String(), nil
+	}
+
 	// Create a channel to receive the result
 	resultChan := make(chan customerrors.ErrorString, 1)
 
@@ -180,6 +340,37 @@ func (s *Service) callLLMForSummary(ctx context.Context, subtitleText string, ll
 
 // fetchSubtitleText fetches and parses subtitle content from a URL
 func (s *Service) fetchSubtitleText(ctx context.Context, subtitleURL string) (string, error) {
+	content, err := s.fetchSubtitleContent(ctx, subtitleURL)
+	if err != nil {
+		return "", err
+	}
+	return s.subtitleText(content)
+}
+
+// fetchSubtitles fetches the subtitles of a video, stores them as a timed transcript
+// and returns the text optimized for summarization
+func (s *Service) fetchSubtitles(ctx context.Context, videoID, subtitleURL string) (string, error) {
+	content, err := s.fetchSubtitleContent(ctx, subtitleURL)
+	if err != nil {
+		return "", err
+	}
+
+	if cues := parseTranscriptCues(content); len(cues) > 0 {
+		transcript := &store.Transcript{
+			VideoID:   videoID,
+			Cues:      cues,
+			FetchedAt: time.Now(),
+		}
+		if err := s.store.SetTranscript(transcript); err != nil {
+			log.Printf("Warning: Failed to store transcript for video %s: %v", videoID, err)
+		}
+	}
+
+	return s.subtitleText(content)
+}
+
+// fetchSubtitleContent downloads the raw subtitle file from a URL
+func (s *Service) fetchSubtitleContent(ctx context.Context, subtitleURL string) (string, error) {
 	if subtitleURL == "" {
 		return "", fmt.Errorf("subtitle URL is empty")
 	}
@@ -215,8 +406,13 @@ func (s *Service) fetchSubtitleText(ctx context.Context, subtitleURL string) (st
 		return "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	return string(body), nil
+}
+
+// subtitleText extracts the subtitle text from raw subtitle content, optimized for token efficiency
+func (s *Service) subtitleText(content string) (string, error) {
 	// Parse subtitle content based on format
-	subtitleText := s.parseSubtitleContent(string(body))
+	subtitleText := s.parseSubtitleContent(content)
 
 	if subtitleText == "" {
 		return "", fmt.Errorf("no text content found in subtitles")
@@ -0,0 +1,155 @@
+package summary
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/webhooks"
+	"youtube-curator-v2/internal/ytdlp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_GetOrGenerateSummary_UsesStoredSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	videoID := "yt:video:dQw4w9WgXcQ"
+	generatedAt := time.Now().Add(-time.Hour)
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetLLMConfig().Times(0) // Stored summaries don't need an LLM
+	mockStore.EXPECT().GetSummary(videoID).Return(&store.StoredSummary{
+		VideoID:        videoID,
+		ChannelID:      "UCtracked",
+		Text:           "Stored summary",
+		Thinking:       "Stored thinking",
+		SourceLanguage: "en",
+		Model:          "test-model",
+		GeneratedAt:    generatedAt,
+	}, nil)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCtracked", Title: "Tracked"}}, nil)
+
+	// A failing enricher proves that nothing is regenerated
+	enricher := ytdlp.NewMockEnricher()
+	enricher.ShouldFail = true
+	service := NewService(mockStore, enricher, nil)
+
+	result := service.GetOrGenerateSummary(context.Background(), videoID)
+
+	require.NotNil(t, result)
+	require.NoError(t, result.Error)
+	assert.Equal(t, "Stored summary", result.Summary)
+	assert.Equal(t, "Stored thinking", result.Thinking)
+	assert.Equal(t, "test-model", result.Model)
+	assert.True(t, result.GeneratedAt.Equal(generatedAt))
+	assert.True(t, result.Tracked)
+}
+
+func TestService_GetOrGenerateSummary_LLMNotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	videoID := "yt:video:dQw4w9WgXcQ"
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetSummary(videoID).Return(nil, nil)
+	mockStore.EXPECT().GetLLMConfig().Return(&store.LLMConfig{}, nil)
+
+	service := NewService(mockStore, ytdlp.NewMockEnricher(), nil)
+
+	result := service.GetOrGenerateSummary(context.Background(), videoID)
+
+	assert.ErrorIs(t, result.Error, ErrLLMNotConfigured)
+}
+
+func TestService_RegenerateSummary_BypassesStoredSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	videoID := "yt:video:dQw4w9WgXcQ"
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetLLMConfig().Return(&store.LLMConfig{EndpointURL: "http://localhost", Model: "test-model"}, nil)
+	mockStore.EXPECT().GetSummary(gomock.Any()).Times(0)
+	mockStore.EXPECT().SetSummary(gomock.Any()).Times(0)
+
+	enricher := ytdlp.NewMockEnricher()
+	enricher.ShouldFail = true
+	service := NewService(mockStore, enricher, nil)
+
+	result := service.RegenerateSummary(context.Background(), videoID)
+
+	require.NotNil(t, result)
+	assert.ErrorContains(t, result.Error, "failed to fetch video metadata")
+}
+
+func TestService_GetOrGenerateSummary_UntrackedChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	videoID := "yt:video:dQw4w9WgXcQ"
+
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().GetLLMConfig().Times(0) // Stored summaries don't need an LLM
+	mockStore.EXPECT().GetSummary(videoID).Return(&store.StoredSummary{
+		VideoID:   videoID,
+		ChannelID: "UCuntracked",
+		Text:      "Stored summary",
+	}, nil)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCtracked", Title: "Tracked"}}, nil)
+
+	service := NewService(mockStore, ytdlp.NewMockEnricher(), nil)
+
+	result := service.GetOrGenerateSummary(context.Background(), videoID)
+
+	require.NoError(t, result.Error)
+	assert.False(t, result.Tracked)
+}
+
+// recordingPublisher records the events published to it
+type recordingPublisher struct {
+	events []string
+	data   []any
+}
+
+func (p *recordingPublisher) Publish(event string, data any) {
+	p.events = append(p.events, event)
+	p.data = append(p.data, data)
+}
+
+func TestService_SaveSummary_PublishesEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	generatedAt := time.Now()
+	stored := &store.StoredSummary{
+		VideoID:        "dQw4w9WgXcQ",
+		ChannelID:      "UCtracked",
+		Text:           "A summary",
+		SourceLanguage: "en",
+		Model:          "test-model",
+		GeneratedAt:    generatedAt,
+	}
+	mockStore := store.NewMockStore(ctrl)
+	mockStore.EXPECT().SetSummary(stored).Return(nil)
+
+	publisher := &recordingPublisher{}
+	service := NewService(mockStore, ytdlp.NewMockEnricher(), nil)
+	service.SetPublisher(publisher)
+	service.saveSummary(stored)
+
+	require.Equal(t, []string{webhooks.EventSummaryGenerated}, publisher.events)
+	assert.Equal(t, webhooks.SummaryData{
+		VideoID:        "dQw4w9WgXcQ",
+		ChannelID:      "UCtracked",
+		Summary:        "A summary",
+		SourceLanguage: "en",
+		Model:          "test-model",
+		GeneratedAt:    generatedAt,
+	}, publisher.data[0])
+}
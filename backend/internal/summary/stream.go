@@ -0,0 +1,184 @@
+package summary
+
+import (
+	"context"
+	"strings"
+)
+
+// StreamEventType identifies the kind of event emitted while streaming a summary
+type StreamEventType string
+
+const (
+	StreamEventPhase    StreamEventType = "phase"    // Progress moved to a new phase
+	StreamEventToken    StreamEventType = "token"    // A piece of the summary text
+	StreamEventThinking StreamEventType = "thinking" // A piece of the LLM's <think> content
+	StreamEventDone     StreamEventType = "done"     // Generation finished; Result holds the full summary
+	StreamEventError    StreamEventType = "error"    // Generation failed; Err holds the cause
+)
+
+// Progress phases reported through StreamEventPhase events
+const (
+	PhaseFetchingMetadata     = "fetching_metadata"
+	PhaseDownloadingSubtitles = "downloading_subtitles"
+	PhaseGenerating           = "generating"
+)
+
+// StreamEvent is a single progress update from StreamSummary
+type StreamEvent struct {
+	Type   StreamEventType
+	Phase  string         // Set for StreamEventPhase
+	Text   string         // Set for StreamEventToken and StreamEventThinking
+	Result *SummaryResult // Set for StreamEventDone
+	Err    error          // Set for StreamEventError
+}
+
+// StreamSummary generates a summary while reporting progress and token deltas on events.
+// A stored summary is replayed immediately unless regenerate is set. The events channel
+// is closed when streaming ends.
+func (s *Service) StreamSummary(ctx context.Context, videoID string, regenerate bool, events chan<- StreamEvent) {
+	defer close(events)
+
+	if !regenerate {
+		if stored := s.storedResult(videoID); stored != nil {
+			replayResult(ctx, events, stored)
+			return
+		}
+	}
+
+	llmConfig, err := s.currentLLMConfig()
+	if err != nil {
+		sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventError, Err: err})
+		return
+	}
+
+	// Events stop being delivered once ctx is cancelled, which also stops the pipeline
+	splitter := &thinkSplitter{}
+	result, err := s.generateSummary(ctx, videoID, llmConfig, &generationProgress{
+		Phase: func(phase string) {
+			sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventPhase, Phase: phase})
+		},
+		Chunk: func(delta string) {
+			for _, event := range splitter.Write(delta) {
+				sendStreamEvent(ctx, events, event)
+			}
+		},
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventError, Err: err})
+		return
+	}
+
+	for _, event := range splitter.Flush() {
+		if !sendStreamEvent(ctx, events, event) {
+			return
+		}
+	}
+	sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventDone, Result: result})
+}
+
+// replayResult emits an already generated summary as thinking, token and done events
+func replayResult(ctx context.Context, events chan<- StreamEvent, result *SummaryResult) {
+	if result.Thinking != "" {
+		if !sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventThinking, Text: result.Thinking}) {
+			return
+		}
+	}
+	if !sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventToken, Text: result.Summary}) {
+		return
+	}
+	sendStreamEvent(ctx, events, StreamEvent{Type: StreamEventDone, Result: result})
+}
+
+// sendStreamEvent delivers an event unless ctx is cancelled, reporting whether it was sent
+func sendStreamEvent(ctx context.Context, events chan<- StreamEvent, event StreamEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// thinkSplitter incrementally separates <think></think> content from summary text in a token stream.
+// Tags may be split across deltas, so a possible partial tag is held back until it can be resolved.
+type thinkSplitter struct {
+	pending  string
+	inThink  bool
+	started  bool // Whether any summary text has been emitted yet
+	thinking bool // Whether any thinking text has been emitted for the current block
+}
+
+// Write consumes a delta and returns the events that can be emitted so far
+func (t *thinkSplitter) Write(delta string) []StreamEvent {
+	t.pending += delta
+	var events []StreamEvent
+
+	for {
+		tag := thinkOpenTag
+		if t.inThink {
+			tag = thinkCloseTag
+		}
+
+		if idx := strings.Index(t.pending, tag); idx >= 0 {
+			events = t.appendText(events, t.pending[:idx])
+			t.pending = t.pending[idx+len(tag):]
+			t.inThink = !t.inThink
+			t.thinking = false
+			continue
+		}
+
+		// Hold back a trailing prefix of the tag in case it completes in the next delta
+		keep := partialSuffix(t.pending, tag)
+		events = t.appendText(events, t.pending[:len(t.pending)-keep])
+		t.pending = t.pending[len(t.pending)-keep:]
+		return events
+	}
+}
+
+// Flush emits any text still held back at the end of the stream
+func (t *thinkSplitter) Flush() []StreamEvent {
+	events := t.appendText(nil, t.pending)
+	t.pending = ""
+	return events
+}
+
+// appendText adds a token or thinking event for text, trimming whitespace around tag boundaries
+func (t *thinkSplitter) appendText(events []StreamEvent, text string) []StreamEvent {
+	if t.inThink {
+		if !t.thinking {
+			text = strings.TrimLeft(text, " \t\r\n")
+		}
+		if text == "" {
+			return events
+		}
+		t.thinking = true
+		return append(events, StreamEvent{Type: StreamEventThinking, Text: text})
+	}
+
+	if !t.started {
+		text = strings.TrimLeft(text, " \t\r\n")
+	}
+	if text == "" {
+		return events
+	}
+	t.started = true
+	return append(events, StreamEvent{Type: StreamEventToken, Text: text})
+}
+
+// partialSuffix returns the length of the longest suffix of s that is a proper prefix of tag
+func partialSuffix(s, tag string) int {
+	for n := len(tag) - 1; n > 0; n-- {
+		if len(s) >= n && strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,212 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+	"youtube-curator-v2/internal/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// collectSplit feeds deltas through a thinkSplitter and joins the resulting thinking and token text
+func collectSplit(deltas []string) (thinking string, summary string) {
+	splitter := &thinkSplitter{}
+	var events []StreamEvent
+	for _, delta := range deltas {
+		events = append(events, splitter.Write(delta)...)
+	}
+	events = append(events, splitter.Flush()...)
+
+	var thinkingText, summaryText strings.Builder
+	for _, event := range events {
+		switch event.Type {
+		case StreamEventThinking:
+			thinkingText.WriteString(event.Text)
+		case StreamEventToken:
+			summaryText.WriteString(event.Text)
+		}
+	}
+	return thinkingText.String(), summaryText.String()
+}
+
+func TestThinkSplitter(t *testing.T) {
+	tests := []struct {
+		name         string
+		deltas       []string
+		wantThinking string
+		wantSummary  string
+	}{
+		{
+			name:        "No thinking block",
+			deltas:      []string{"This is ", "the summary."},
+			wantSummary: "This is the summary.",
+		},
+		{
+			name:         "Thinking block in single delta",
+			deltas:       []string{"<think>Pondering</think>\n\nThe summary."},
+			wantThinking: "Pondering",
+			wantSummary:  "The summary.",
+		},
+		{
+			name:         "Tags split across deltas",
+			deltas:       []string{"<th", "ink>Deep ", "thought</th", "ink>", "Sum", "mary"},
+			wantThinking: "Deep thought",
+			wantSummary:  "Summary",
+		},
+		{
+			name:        "Angle bracket that is not a tag",
+			deltas:      []string{"a <", "b and <thi", "s>"},
+			wantSummary: "a <b and <this>",
+		},
+		{
+			name:         "Unterminated thinking block",
+			deltas:       []string{"<think>Still thinking"},
+			wantThinking: "Still thinking",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotThinking, gotSummary := collectSplit(tt.deltas)
+			assert.Equal(t, tt.wantThinking, gotThinking)
+			assert.Equal(t, tt.wantSummary, gotSummary)
+		})
+	}
+}
+
+func TestMockService_StreamSummary(t *testing.T) {
+	service := NewMockService(nil)
+
+	events := make(chan StreamEvent)
+	go service.StreamSummary(context.Background(), "yt:video:dQw4w9WgXcQ", false, events)
+
+	var phases []string
+	var text strings.Builder
+	var done *SummaryResult
+	for event := range events {
+		switch event.Type {
+		case StreamEventPhase:
+			phases = append(phases, event.Phase)
+		case StreamEventToken:
+			text.WriteString(event.Text)
+		case StreamEventDone:
+			done = event.Result
+		case StreamEventError:
+			t.Fatalf("Unexpected error event: %v", event.Err)
+		}
+	}
+
+	assert.Equal(t, []string{PhaseFetchingMetadata, PhaseDownloadingSubtitles, PhaseGenerating}, phases)
+	require.NotNil(t, done)
+	assert.Equal(t, done.Summary, text.String())
+}
+
+// subtitleEnricher points every video at a fixed subtitle URL
+type subtitleEnricher struct {
+	url string
+}
+
+func (e *subtitleEnricher) EnrichEntry(ctx context.Context, entry *rss.Entry) error {
+	entry.ChannelID = "UCtracked"
+	entry.AutoSubtitles = e.url
+	return nil
+}
+
+func (e *subtitleEnricher) ResolveChannelID(ctx context.Context, url string) (string, error) {
+	return "", nil
+}
+
+// newPipelineService returns a service backed by test subtitle and LLM servers; the LLM answers
+// with a thinking block and a summary, streamed in pieces when asked to stream
+func newPipelineService(t *testing.T, mockStore *store.MockStore) *Service {
+	subtitles := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("WEBVTT\n\n00:00:01.000 --> 00:00:04.000\nThis is a test subtitle.\n"))
+	}))
+	t.Cleanup(subtitles.Close)
+
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"stream":true`) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"id":"1","object":"chat.completion","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"<think>Hmm</think>The summary."}}]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{"<thi", "nk>Hmm</think>", "The ", "summary."} {
+			fmt.Fprintf(w, "data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", delta)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(llm.Close)
+
+	mockStore.EXPECT().GetLLMConfig().Return(&store.LLMConfig{EndpointURL: llm.URL, Model: "test-model"}, nil)
+	mockStore.EXPECT().SetTranscript(gomock.Any()).Return(nil)
+	mockStore.EXPECT().GetChannels().Return([]store.Channel{{ID: "UCtracked"}}, nil)
+	return NewService(mockStore, &subtitleEnricher{url: subtitles.URL}, nil)
+}
+
+func TestStreamSummary_SharesPipeline(t *testing.T) {
+	for _, streamed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streamed=%v", streamed), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var saved *store.StoredSummary
+			mockStore := store.NewMockStore(ctrl)
+			mockStore.EXPECT().SetSummary(gomock.Any()).DoAndReturn(func(stored *store.StoredSummary) error {
+				saved = stored
+				return nil
+			})
+			service := newPipelineService(t, mockStore)
+			publisher := &recordingPublisher{}
+			service.SetPublisher(publisher)
+
+			var result *SummaryResult
+			if streamed {
+				events := make(chan StreamEvent)
+				go service.StreamSummary(context.Background(), "yt:video:dQw4w9WgXcQ", true, events)
+
+				var phases []string
+				var text, thinking strings.Builder
+				for event := range events {
+					switch event.Type {
+					case StreamEventPhase:
+						phases = append(phases, event.Phase)
+					case StreamEventToken:
+						text.WriteString(event.Text)
+					case StreamEventThinking:
+						thinking.WriteString(event.Text)
+					case StreamEventDone:
+						result = event.Result
+					case StreamEventError:
+						t.Fatalf("Unexpected error event: %v", event.Err)
+					}
+				}
+				assert.Equal(t, []string{PhaseFetchingMetadata, PhaseDownloadingSubtitles, PhaseGenerating}, phases)
+				assert.Equal(t, "The summary.", text.String())
+				assert.Equal(t, "Hmm", thinking.String())
+			} else {
+				result = service.RegenerateSummary(context.Background(), "yt:video:dQw4w9WgXcQ")
+				require.NoError(t, result.Error)
+			}
+
+			require.NotNil(t, result)
+			assert.Equal(t, "The summary.", result.Summary)
+			assert.Equal(t, "Hmm", result.Thinking)
+			assert.True(t, result.Tracked)
+			require.NotNil(t, saved, "Both paths persist the summary")
+			assert.Equal(t, "UCtracked", saved.ChannelID)
+			assert.Equal(t, []string{webhooks.EventSummaryGenerated}, publisher.events, "Both paths announce the summary")
+		})
+	}
+}
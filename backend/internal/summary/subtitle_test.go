@@ -154,6 +154,3 @@ func TestFetchSubtitleText404(t *testing.T) {
 		t.Error("fetchSubtitleText() with 404 should return error")
 	}
 }
-
-// Note: Integration test for generateSummary would require more complex mocking
-// The main functionality is tested through the individual subtitle parsing and fetching tests above
@@ -0,0 +1,111 @@
+package summary
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"youtube-curator-v2/internal/store"
+)
+
+var (
+	// cueTimingPattern matches VTT (00:00:01.000) and SRT (00:00:01,000) timing lines, with optional hours
+	cueTimingPattern = regexp.MustCompile(`^(?:(\d{1,2}):)?(\d{2}):(\d{2})[.,](\d{3}) -->`)
+	cueTagPattern    = regexp.MustCompile(`<[^>]*>`)
+)
+
+// parseTranscriptCues extracts timed cues from JSON (YouTube json3), VTT or SRT subtitles
+func parseTranscriptCues(content string) []store.TranscriptCue {
+	if strings.HasPrefix(strings.TrimSpace(content), "{") {
+		return parseJSONTranscriptCues(content)
+	}
+
+	var cues []store.TranscriptCue
+	var previousLines map[string]bool
+	var currentLines map[string]bool
+	startMs := -1
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if match := cueTimingPattern.FindStringSubmatch(line); match != nil {
+			startMs = cueStartMs(match)
+			previousLines = currentLines
+			currentLines = make(map[string]bool)
+			continue
+		}
+		if line == "" {
+			startMs = -1 // A blank line ends the cue, so SRT sequence numbers aren't read as text
+			continue
+		}
+		if startMs < 0 {
+			continue
+		}
+
+		text := cleanCueText(line)
+		if text == "" {
+			continue
+		}
+		currentLines[text] = true
+
+		// Auto-generated VTT repeats the previous line at the top of each rolling cue
+		if previousLines[text] {
+			continue
+		}
+		cues = append(cues, store.TranscriptCue{StartMs: startMs, Text: text})
+	}
+
+	return cues
+}
+
+// parseJSONTranscriptCues extracts one cue per event from YouTube's JSON subtitle format
+func parseJSONTranscriptCues(content string) []store.TranscriptCue {
+	var subtitleData JSONSubtitleRoot
+	if err := json.Unmarshal([]byte(content), &subtitleData); err != nil {
+		return nil
+	}
+
+	var cues []store.TranscriptCue
+	for _, event := range subtitleData.Events {
+		var parts []string
+		for _, seg := range event.Segs {
+			// Skip music/sound effect markers
+			if strings.HasPrefix(seg.UTF8, "[") && strings.HasSuffix(seg.UTF8, "]") {
+				continue
+			}
+			parts = append(parts, seg.UTF8)
+		}
+		text := strings.Join(strings.Fields(strings.Join(parts, "")), " ")
+		if text != "" {
+			cues = append(cues, store.TranscriptCue{StartMs: event.TStartMs, Text: text})
+		}
+	}
+
+	sort.SliceStable(cues, func(i, j int) bool {
+		return cues[i].StartMs < cues[j].StartMs
+	})
+	return cues
+}
+
+// cueStartMs converts a cueTimingPattern match into milliseconds
+func cueStartMs(match []string) int {
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	seconds, _ := strconv.Atoi(match[3])
+	millis, _ := strconv.Atoi(match[4])
+	return ((hours*60+minutes)*60+seconds)*1000 + millis
+}
+
+// cleanCueText strips inline timing/styling tags and HTML entities from a cue line
+func cleanCueText(line string) string {
+	line = cueTagPattern.ReplaceAllString(line, "")
+	line = strings.ReplaceAll(line, "&amp;", "&")
+	line = strings.ReplaceAll(line, "&lt;", "<")
+	line = strings.ReplaceAll(line, "&gt;", ">")
+	line = strings.ReplaceAll(line, "&quot;", "\"")
+	line = strings.ReplaceAll(line, "&#39;", "'")
+	line = strings.ReplaceAll(line, "&apos;", "'")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,77 @@
+package summary
+
+import (
+	"reflect"
+	"testing"
+
+	"youtube-curator-v2/internal/store"
+)
+
+func TestParseTranscriptCues(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []store.TranscriptCue
+	}{
+		{
+			name: "VTT format",
+			input: `WEBVTT
+
+00:00:01.000 --> 00:00:04.000
+This is the first subtitle.
+
+00:01:04.500 --> 00:01:08.000
+This is the <c>second</c> subtitle.`,
+			expected: []store.TranscriptCue{
+				{StartMs: 1000, Text: "This is the first subtitle."},
+				{StartMs: 64500, Text: "This is the second subtitle."},
+			},
+		},
+		{
+			name: "SRT format",
+			input: `1
+00:00:01,000 --> 00:00:04,000
+First line
+
+2
+01:00:00,250 --> 01:00:02,000
+Second line`,
+			expected: []store.TranscriptCue{
+				{StartMs: 1000, Text: "First line"},
+				{StartMs: 3600250, Text: "Second line"},
+			},
+		},
+		{
+			name: "Rolling auto-generated VTT cues",
+			input: `WEBVTT
+
+00:00:01.000 --> 00:00:03.000 align:start position:0%
+hello there
+
+00:00:03.000 --> 00:00:05.000 align:start position:0%
+hello there
+general<00:00:03.500><c> kenobi</c>`,
+			expected: []store.TranscriptCue{
+				{StartMs: 1000, Text: "hello there"},
+				{StartMs: 3000, Text: "general kenobi"},
+			},
+		},
+		{
+			name:  "JSON format",
+			input: `{"events":[{"tStartMs":2000,"segs":[{"utf8":"world"}]},{"tStartMs":500,"segs":[{"utf8":"hello "},{"utf8":"big"}]},{"tStartMs":3000,"segs":[{"utf8":"[Music]"}]}]}`,
+			expected: []store.TranscriptCue{
+				{StartMs: 500, Text: "hello big"},
+				{StartMs: 2000, Text: "world"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseTranscriptCues(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseTranscriptCues() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// Package webhooks posts curator events, such as newly discovered videos, to the outgoing
+// webhooks subscribed to them. Payloads are signed like the generic notification webhook,
+// retried with backoff, and every delivery is recorded in the store.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"youtube-curator-v2/internal/http/retry"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/store"
+)
+
+// Event types webhooks can subscribe to
+const (
+	EventVideoDiscovered  = "video.discovered"
+	EventSummaryGenerated = "summary.generated"
+	EventNewsletterSent   = "newsletter.sent"
+)
+
+// EventTypes lists every event type webhooks can subscribe to
+var EventTypes = []string{EventVideoDiscovered, EventSummaryGenerated, EventNewsletterSent}
+
+// DeliveryHeader carries the delivery ID, which stays the same across retries so receivers can
+// ignore duplicates
+const DeliveryHeader = "X-Curator-Delivery"
+
+// DefaultRetryConfig bounds the attempts to deliver an event before the delivery is marked failed
+var DefaultRetryConfig = retry.RetryConfig{
+	MaxRetries:      4,
+	InitialBackoff:  2 * time.Second,
+	MaxBackoff:      time.Minute,
+	BackoffFactor:   2.0,
+	MaxTotalTimeout: 5 * time.Minute,
+}
+
+// defaultTimeout bounds a single delivery attempt
+const defaultTimeout = 10 * time.Second
+
+var (
+	// ErrDeliveryNotFound is returned when redelivering an unknown delivery
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrDeliveryPending is returned when redelivering a delivery that is still being attempted
+	ErrDeliveryPending = errors.New("webhook delivery is still pending")
+)
+
+// Publisher fires events to the webhooks subscribed to them
+type Publisher interface {
+	// Publish delivers an event in the background; data is sent as the payload's data field
+	Publish(event string, data any)
+}
+
+// Payload is the JSON body posted to webhooks
+type Payload struct {
+	ID        string    `json:"id"` // Delivery ID, as in DeliveryHeader
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// VideoData describes a video in video.discovered events
+type VideoData struct {
+	ChannelID string    `json:"channelId"`
+	VideoID   string    `json:"videoId"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Author    string    `json:"author,omitempty"`
+	Thumbnail string    `json:"thumbnail,omitempty"`
+	Published time.Time `json:"published"`
+}
+
+// NewVideoData describes a feed entry of channelID
+func NewVideoData(channelID string, entry rss.Entry) VideoData {
+	return VideoData{
+		ChannelID: channelID,
+		VideoID:   strings.TrimPrefix(entry.ID, "yt:video:"),
+		Title:     entry.Title,
+		URL:       entry.Link.Href,
+		Author:    entry.Author.Name,
+		Thumbnail: entry.MediaGroup.MediaThumbnail.URL,
+		Published: entry.Published,
+	}
+}
+
+// SummaryData describes a generated summary in summary.generated events
+type SummaryData struct {
+	VideoID        string    `json:"videoId"`
+	ChannelID      string    `json:"channelId,omitempty"`
+	Summary        string    `json:"summary"`
+	SourceLanguage string    `json:"sourceLanguage,omitempty"`
+	Model          string    `json:"model,omitempty"`
+	GeneratedAt    time.Time `json:"generatedAt"`
+}
+
+// NewsletterData describes a delivered digest in newsletter.sent events
+type NewsletterData struct {
+	RunID     string             `json:"runId,omitempty"`
+	MessageID string             `json:"messageId"` // Outbox message the digest was delivered from
+	Recipient string             `json:"recipient"`
+	Subject   string             `json:"subject"`
+	Channel   string             `json:"channel"` // Notification channel type, e.g. email
+	Videos    []store.DigestItem `json:"videos"`
+	SentAt    time.Time          `json:"sentAt"`
+}
+
+// Dispatcher delivers events to the stored webhooks subscribed to them
+type Dispatcher struct {
+	store       store.Store
+	client      *http.Client
+	retryConfig retry.RetryConfig
+
+	wg sync.WaitGroup // Deliveries in flight
+}
+
+// NewDispatcher creates a dispatcher that posts through client, which defaults to a client with a
+// 10 second timeout when nil
+func NewDispatcher(db store.Store, client *http.Client) *Dispatcher {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Dispatcher{
+		store:       db,
+		client:      client,
+		retryConfig: DefaultRetryConfig,
+	}
+}
+
+// Publish records a delivery of the event for every enabled webhook subscribed to it and
+// delivers them in the background
+func (d *Dispatcher) Publish(event string, data any) {
+	webhooks, err := d.store.GetWebhooks()
+	if err != nil {
+		log.Printf("Warning: Failed to load webhooks for %s event: %v", event, err)
+		return
+	}
+
+	published := false
+	for _, webhook := range webhooks {
+		if !webhook.Enabled || !webhook.Subscribes(event) {
+			continue
+		}
+		delivery, err := d.newDelivery(webhook, event, data)
+		if err != nil {
+			log.Printf("Warning: Failed to queue %s event for webhook %s: %v", event, webhook.ID, err)
+			continue
+		}
+		published = true
+
+		d.wg.Add(1)
+		go func(webhook store.Webhook) {
+			defer d.wg.Done()
+			d.deliver(context.Background(), webhook, delivery)
+		}(webhook)
+	}
+
+	if published {
+		if _, err := d.store.PruneWebhookDeliveries(store.WebhookDeliveryHistoryLimit); err != nil {
+			log.Printf("Warning: Failed to prune webhook deliveries: %v", err)
+		}
+	}
+}
+
+// Wait blocks until the deliveries in flight have finished
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// DeliverPending delivers the deliveries left pending, such as those interrupted by a shutdown
+func (d *Dispatcher) DeliverPending(ctx context.Context) error {
+	deliveries, err := d.store.GetWebhookDeliveries("", 0)
+	if err != nil {
+		return err
+	}
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if delivery.Status != store.WebhookDeliveryPending {
+			continue
+		}
+		log.Printf("Delivering pending %s event to webhook %s", delivery.Event, delivery.WebhookID)
+		if err := d.redeliver(ctx, delivery); err != nil {
+			log.Printf("Error delivering %s event to webhook %s: %v", delivery.Event, delivery.WebhookID, err)
+		}
+	}
+	return nil
+}
+
+// Redeliver posts a finished delivery's payload again to its webhook and returns its updated state
+func (d *Dispatcher) Redeliver(ctx context.Context, deliveryID string) (*store.WebhookDelivery, error) {
+	delivery, err := d.store.GetWebhookDelivery(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrDeliveryNotFound
+	}
+	if delivery.Status == store.WebhookDeliveryPending {
+		return nil, ErrDeliveryPending
+	}
+	if err := d.redeliver(ctx, delivery); err != nil {
+		log.Printf("Error redelivering %s event to webhook %s: %v", delivery.Event, delivery.WebhookID, err)
+	}
+	return delivery, nil
+}
+
+// redeliver posts a recorded delivery to its webhook's current URL and secret, failing it if the
+// webhook has been deleted
+func (d *Dispatcher) redeliver(ctx context.Context, delivery *store.WebhookDelivery) error {
+	webhook, err := d.store.GetWebhook(delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if webhook == nil {
+		d.finish(delivery, errors.New("webhook has been deleted"))
+		return nil
+	}
+	return d.deliver(ctx, *webhook, delivery)
+}
+
+// newDelivery renders the event's payload for webhook and saves it as a pending delivery
+func (d *Dispatcher) newDelivery(webhook store.Webhook, event string, data any) (*store.WebhookDelivery, error) {
+	delivery, err := store.NewWebhookDelivery(webhook, event)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(Payload{ID: delivery.ID, Event: event, CreatedAt: delivery.CreatedAt, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", event, err)
+	}
+	delivery.Payload = string(body)
+	if err := d.store.SaveWebhookDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// deliver posts the delivery's payload to webhook, retrying transient failures with backoff. The
+// delivery ends up delivered, or failed with the last error; either way its new state is saved.
+func (d *Dispatcher) deliver(ctx context.Context, webhook store.Webhook, delivery *store.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+	headers := map[string]string{
+		notify.EventHeader: delivery.Event,
+		DeliveryHeader:     delivery.ID,
+	}
+	if webhook.Secret != "" {
+		headers[notify.SignatureHeader] = notify.Sign(webhook.Secret, body)
+	}
+
+	delivery.URL = webhook.URL
+	_, err := retry.RetryWithBackoff(ctx, d.retryConfig, func(ctx context.Context) (struct{}, error) {
+		delivery.Attempts++
+		err := notify.Post(ctx, d.client, webhook.URL, "application/json", body, headers)
+		delivery.StatusCode = 0
+		var httpErr *notify.HTTPError
+		if errors.As(err, &httpErr) {
+			delivery.StatusCode = httpErr.StatusCode
+		}
+		return struct{}{}, err
+	}, notify.IsTransient)
+
+	d.finish(delivery, err)
+	return err
+}
+
+// finish records the outcome of a delivery
+func (d *Dispatcher) finish(delivery *store.WebhookDelivery, err error) {
+	now := time.Now()
+	delivery.UpdatedAt = now
+	if err != nil {
+		delivery.Status = store.WebhookDeliveryFailed
+		delivery.Error = err.Error()
+	} else {
+		delivery.Status = store.WebhookDeliveryDelivered
+		delivery.Error = ""
+		delivery.DeliveredAt = &now
+	}
+	if saveErr := d.store.SaveWebhookDelivery(delivery); saveErr != nil {
+		log.Printf("Warning: Failed to save webhook delivery %s: %v", delivery.ID, saveErr)
+	}
+}
@@ -0,0 +1,224 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"youtube-curator-v2/internal/http/retry"
+	"youtube-curator-v2/internal/notify"
+	"youtube-curator-v2/internal/store"
+)
+
+// testRetryConfig retries twice without waiting
+var testRetryConfig = retry.RetryConfig{MaxRetries: 2}
+
+// receiver stands in for a webhook endpoint, answering with the next of statuses and then 200
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		if len(r.statuses) > 0 {
+			w.WriteHeader(r.statuses[0])
+			r.statuses = r.statuses[1:]
+		}
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func newTestStore(t *testing.T) store.Store {
+	t.Helper()
+	db, err := store.NewStore(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestDispatcher(db store.Store, client *http.Client) *Dispatcher {
+	dispatcher := NewDispatcher(db, client)
+	dispatcher.retryConfig = testRetryConfig
+	return dispatcher
+}
+
+func saveWebhooks(t *testing.T, db store.Store, webhooks ...store.Webhook) {
+	t.Helper()
+	for i := range webhooks {
+		if err := db.SaveWebhook(&webhooks[i]); err != nil {
+			t.Fatalf("Failed to save webhook: %v", err)
+		}
+	}
+}
+
+func TestDispatcher_Publish(t *testing.T) {
+	db := newTestStore(t)
+	subscribed := newReceiver(t)
+	other := newReceiver(t)
+	saveWebhooks(t, db,
+		store.Webhook{ID: "subscribed", URL: subscribed.URL, Secret: "s3cret", Events: []string{EventVideoDiscovered}, Enabled: true},
+		store.Webhook{ID: "other-event", URL: other.URL, Events: []string{EventNewsletterSent}, Enabled: true},
+		store.Webhook{ID: "disabled", URL: other.URL, Events: []string{EventVideoDiscovered}},
+	)
+
+	dispatcher := newTestDispatcher(db, subscribed.Client())
+	dispatcher.Publish(EventVideoDiscovered, VideoData{ChannelID: "UC1", VideoID: "abc", Title: "New video"})
+	dispatcher.Wait()
+
+	if subscribed.count() != 1 || other.count() != 0 {
+		t.Fatalf("Expected only the subscribed webhook to be called, got %d and %d requests", subscribed.count(), other.count())
+	}
+	req, body := subscribed.requests[0], subscribed.bodies[0]
+	if got, want := req.Header.Get(notify.SignatureHeader), notify.Sign("s3cret", body); got != want {
+		t.Errorf("Expected signature %s, got %s", want, got)
+	}
+	if req.Header.Get(notify.EventHeader) != EventVideoDiscovered {
+		t.Errorf("Expected the event header, got %q", req.Header.Get(notify.EventHeader))
+	}
+
+	var payload struct {
+		ID    string    `json:"id"`
+		Event string    `json:"event"`
+		Data  VideoData `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Expected a JSON payload: %v", err)
+	}
+	if payload.Event != EventVideoDiscovered || payload.Data.VideoID != "abc" || payload.ID != req.Header.Get(DeliveryHeader) {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+
+	deliveries, err := db.GetWebhookDeliveries("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery to be logged, got %+v", deliveries)
+	}
+	delivery := deliveries[0]
+	if delivery.ID != payload.ID || delivery.WebhookID != "subscribed" || delivery.Status != store.WebhookDeliveryDelivered ||
+		delivery.Attempts != 1 || delivery.DeliveredAt == nil || delivery.Payload != string(body) {
+		t.Errorf("Unexpected delivery: %+v", delivery)
+	}
+}
+
+func TestDispatcher_RetriesTransientFailures(t *testing.T) {
+	db := newTestStore(t)
+	server := newReceiver(t, http.StatusBadGateway, http.StatusTooManyRequests)
+	saveWebhooks(t, db, store.Webhook{ID: "hook", URL: server.URL, Events: []string{EventSummaryGenerated}, Enabled: true})
+
+	dispatcher := newTestDispatcher(db, server.Client())
+	dispatcher.Publish(EventSummaryGenerated, SummaryData{VideoID: "abc", Summary: "Summary"})
+	dispatcher.Wait()
+
+	deliveries, err := db.GetWebhookDeliveries("hook", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != store.WebhookDeliveryDelivered || deliveries[0].Attempts != 3 {
+		t.Fatalf("Expected the delivery to succeed on the third attempt, got %+v", deliveries)
+	}
+	if deliveries[0].StatusCode != 0 || deliveries[0].Error != "" {
+		t.Errorf("Expected earlier failures to be cleared, got %+v", deliveries[0])
+	}
+	if server.count() != 3 {
+		t.Errorf("Expected 3 requests, got %d", server.count())
+	}
+}
+
+func TestDispatcher_PermanentFailure(t *testing.T) {
+	db := newTestStore(t)
+	server := newReceiver(t, http.StatusGone)
+	saveWebhooks(t, db, store.Webhook{ID: "hook", URL: server.URL, Events: []string{EventNewsletterSent}, Enabled: true})
+
+	dispatcher := newTestDispatcher(db, server.Client())
+	dispatcher.Publish(EventNewsletterSent, NewsletterData{MessageID: "msg", Recipient: "me@example.com"})
+	dispatcher.Wait()
+
+	deliveries, err := db.GetWebhookDeliveries("hook", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %+v", deliveries)
+	}
+	delivery := deliveries[0]
+	if delivery.Status != store.WebhookDeliveryFailed || delivery.Attempts != 1 || delivery.StatusCode != http.StatusGone || delivery.Error == "" {
+		t.Errorf("Expected a single failed attempt with status 410, got %+v", delivery)
+	}
+}
+
+func TestDispatcher_DeliverPendingAndRedeliver(t *testing.T) {
+	db := newTestStore(t)
+	server := newReceiver(t)
+	webhook := store.Webhook{ID: "hook", URL: server.URL, Events: []string{EventVideoDiscovered}, Enabled: true}
+	saveWebhooks(t, db, webhook)
+
+	dispatcher := newTestDispatcher(db, server.Client())
+	pending, err := dispatcher.newDelivery(webhook, EventVideoDiscovered, VideoData{VideoID: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orphan, err := dispatcher.newDelivery(store.Webhook{ID: "deleted", URL: server.URL}, EventVideoDiscovered, VideoData{VideoID: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dispatcher.Redeliver(context.Background(), pending.ID); !errors.Is(err, ErrDeliveryPending) {
+		t.Errorf("Expected ErrDeliveryPending, got %v", err)
+	}
+
+	if err := dispatcher.DeliverPending(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if server.count() != 1 {
+		t.Fatalf("Expected the pending delivery to be posted once, got %d requests", server.count())
+	}
+	if delivery, _ := db.GetWebhookDelivery(pending.ID); delivery.Status != store.WebhookDeliveryDelivered {
+		t.Errorf("Expected the pending delivery to be delivered, got %+v", delivery)
+	}
+	if delivery, _ := db.GetWebhookDelivery(orphan.ID); delivery.Status != store.WebhookDeliveryFailed {
+		t.Errorf("Expected the delivery to a deleted webhook to fail, got %+v", delivery)
+	}
+
+	time.Sleep(time.Millisecond)
+	delivery, err := dispatcher.Redeliver(context.Background(), pending.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if delivery.Status != store.WebhookDeliveryDelivered || delivery.Attempts != 2 || server.count() != 2 {
+		t.Errorf("Expected the delivery to be posted again, got %+v", delivery)
+	}
+	if server.bodies[0] == nil || string(server.bodies[1]) != string(server.bodies[0]) {
+		t.Error("Expected the same payload to be redelivered")
+	}
+
+	if _, err := dispatcher.Redeliver(context.Background(), "missing"); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("Expected ErrDeliveryNotFound, got %v", err)
+	}
+}
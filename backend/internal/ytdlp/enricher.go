@@ -105,6 +105,7 @@ func NewDefaultEnricherWithExecutor(executor CommandExecutor) *DefaultEnricher {
 
 // YtdlpOutput represents the JSON output structure from yt-dlp
 type YtdlpOutput struct {
+	ChannelID         string                    `json:"channel_id"`
 	Duration          float64                   `json:"duration"`
 	Tags              []string                  `json:"tags"`
 	Subtitles         map[string][]SubtitleInfo `json:"subtitles"`
@@ -209,6 +210,11 @@ func (e *DefaultEnricher) EnrichEntry(ctx context.Context, entry *rss.Entry) err
 
 // enrichEntryWithData enriches an RSS entry with yt-dlp data
 func (e *DefaultEnricher) enrichEntryWithData(entry *rss.Entry, ytdlpData *YtdlpOutput) error {
+	// Set channel ID if the feed didn't provide one
+	if entry.ChannelID == "" && ytdlpData.ChannelID != "" {
+		entry.ChannelID = ytdlpData.ChannelID
+	}
+
 	// Set duration
 	if ytdlpData.Duration > 0 {
 		entry.Duration = int(ytdlpData.Duration)
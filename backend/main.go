@@ -2,33 +2,30 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"sort"
-	"sync"
+	"syscall"
 	"time"
 
 	"youtube-curator-v2/internal/api"
 	"youtube-curator-v2/internal/config"
-	"youtube-curator-v2/internal/email"
-	"youtube-curator-v2/internal/openai"
+	"youtube-curator-v2/internal/jobs"
+	"youtube-curator-v2/internal/newsletter"
+	"youtube-curator-v2/internal/notify"
 	"youtube-curator-v2/internal/processor"
+	"youtube-curator-v2/internal/provider"
 	"youtube-curator-v2/internal/rss"
+	"youtube-curator-v2/internal/scheduler"
+	"youtube-curator-v2/internal/search"
 	"youtube-curator-v2/internal/store"
-	"youtube-curator-v2/internal/summary"
+	"youtube-curator-v2/internal/webhooks"
 	"youtube-curator-v2/internal/ytdlp"
-
-	"github.com/robfig/cron/v3"
 )
 
-// channelJob represents a channel processing job
-type channelJob struct {
-	channelID string
-	result    chan processor.ChannelResult
-}
-
 func main() {
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -37,7 +34,6 @@ func main() {
 
 	fmt.Println("YouTube Curator v2 Starting...")
 	fmt.Printf("Loaded configuration: %+v\n", cfg)
-	fmt.Printf("Checking for new videos on schedule %s\n", cfg.CronSchedule)
 
 	dbDir := filepath.Dir(cfg.DBPath)
 	if err := os.MkdirAll(dbDir, 0755); err != nil {
@@ -50,6 +46,13 @@ func main() {
 	}
 	defer db.Close()
 
+	// Build the search index from the catalogue and keep it updated on every write
+	searchIndex := search.NewIndex()
+	if err := searchIndex.Rebuild(db); err != nil {
+		log.Printf("Warning: Failed to build search index: %v", err)
+	}
+	db = search.NewIndexedStore(db, searchIndex)
+
 	var feedProvider rss.FeedProvider
 	if cfg.DebugMockRSS {
 		fmt.Println("Using Mock RSS Feed Provider")
@@ -60,48 +63,86 @@ func main() {
 		feedProvider = rss.NewFeedProvider()
 	}
 
-	// Create video store with 24 hour TTL and database persistence
-	videoStore := store.NewVideoStoreWithStore(24*time.Hour, db)
+	// Create the video store backed by the persistent video catalogue
+	videoRetention := store.VideoRetention{
+		MaxAge:        time.Duration(cfg.VideoRetentionDays) * 24 * time.Hour,
+		MaxPerChannel: cfg.VideoRetentionPerChannel,
+	}
+	videoStore := store.NewVideoStoreWithStore(videoRetention, db)
 
 	// Create the channel processor
 	channelProcessor := processor.NewDefaultChannelProcessor(db, feedProvider, videoStore)
 
-	// Create email sender with SMTP settings from database
-	smtpConfig, err := db.GetSMTPConfig()
-	if err != nil {
-		log.Printf("Warning: Failed to get SMTP configuration: %v", err)
-	}
-
-	var emailSender email.Sender
-	if smtpConfig != nil && smtpConfig.Server != "" {
-		emailSender = email.NewEmailSender(smtpConfig.Server, smtpConfig.Port, smtpConfig.Username, smtpConfig.Password)
-	} else {
-		// Fall back to environment variables for backward compatibility
-		emailSender = email.NewEmailSender(cfg.SMTPServer, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword)
-	}
-
 	var ytdlpEnricher ytdlp.Enricher
 	fmt.Println("Using YTDLP Enricher")
 	ytdlpEnricher = ytdlp.NewDefaultEnricher()
 
-	var summaryService summary.SummaryServiceInterface
+	// The email sender and summary service are rebuilt from the stored SMTP and LLM
+	// configuration whenever it changes, so API updates apply without a restart
+	services := provider.New(db, cfg, ytdlpEnricher)
+	emailSender := services.EmailSender()
+	summaryService := services.SummaryService()
+
+	// Stop background work on Ctrl+C or SIGTERM, leaving interrupted jobs to resume on the next start
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Outgoing webhooks announce discovered videos, generated summaries and sent newsletters
+	webhookDispatcher := webhooks.NewDispatcher(db, nil)
+	channelProcessor.SetPublisher(webhookDispatcher)
+	services.SetPublisher(webhookDispatcher)
+	go func() {
+		if err := webhookDispatcher.DeliverPending(ctx); err != nil {
+			log.Printf("Warning: Failed to deliver pending webhook events: %v", err)
+		}
+	}()
 
 	if cfg.DebugSkipSummary {
 		fmt.Println("DEBUG_SKIP_SUMMARY is set: Skipping summary generation.")
-		summaryService = summary.NewMockService(db)
+	} else if services.CurrentSummaryService() == nil {
+		log.Println("LLM not configured yet, only stored summaries are available until it is")
 	} else {
 		fmt.Println("Using Summary Service")
+	}
+
+	// Start the background job queue, resuming any jobs interrupted by a previous shutdown
+	jobQueue := jobs.NewQueue(db, summaryService, cfg.SummaryWorkers)
+	if err := jobQueue.Start(ctx); err != nil {
+		log.Printf("Warning: Failed to resume background jobs: %v", err)
+	}
+
+	// Digests go out through each subscriber's notification channel, by email unless they chose another
+	notifier := notify.NewDispatcher(emailSender, nil)
+
+	// The scheduler and the API share the newsletter runner
+	newsletterRunner := newsletter.NewRunner(db, channelProcessor, notifier, summaryService, cfg)
+	newsletterRunner.SetPublisher(webhookDispatcher)
 
-		llmConfig, err := db.GetLLMConfig()
-		if err != nil {
-			log.Printf("Warning: Failed to get LLM configuration: %v", err)
+	// Runs still recorded as running were cut short by a previous shutdown or crash
+	if interrupted, err := newsletterRunner.FailInterruptedRuns(); err != nil {
+		log.Printf("Warning: Failed to mark interrupted newsletter runs: %v", err)
+	} else if interrupted > 0 {
+		log.Printf("Marked %d interrupted newsletter runs as failed", interrupted)
+	}
+
+	// Deliver newsletter emails left in the outbox by a previous shutdown
+	go func() {
+		if err := newsletterRunner.DeliverPending(ctx); err != nil {
+			log.Printf("Warning: Failed to deliver pending newsletter emails: %v", err)
 		}
+	}()
 
-		if llmConfig == nil || llmConfig.EndpointURL == "" {
-			log.Println("LLM not configured, skipping summary service")
-		} else {
-			openAIClient := openai.New(llmConfig.EndpointURL, llmConfig.APIKey, llmConfig.Model)
-			summaryService = summary.NewService(db, ytdlpEnricher, openAIClient)
+	// Schedule newsletter runs from the stored configuration, falling back to CRON_SCHEDULE.
+	// Configuration changes made through the API reschedule it without a restart.
+	var newsletterScheduler *scheduler.Scheduler
+	if cfg.DebugSkipCron {
+		fmt.Println("DEBUG_SKIP_CRON is set: Skipping scheduler.")
+	} else {
+		newsletterScheduler = scheduler.New(db, cfg.CronSchedule, func() {
+			checkForNewVideos(ctx, newsletterRunner)
+		})
+		if err := newsletterScheduler.Start(); err != nil {
+			log.Printf("Warning: Failed to apply newsletter schedule: %v", err)
 		}
 	}
 
@@ -109,217 +150,39 @@ func main() {
 	if cfg.EnableAPI {
 		go func() {
 			fmt.Printf("Starting API server on port %s...\n", cfg.APIPort)
-			e := api.SetupRouter(db, feedProvider, emailSender, cfg, channelProcessor, videoStore, ytdlpEnricher, summaryService)
+			e := api.SetupRouter(db, feedProvider, emailSender, cfg, channelProcessor, videoStore, ytdlpEnricher, summaryService, jobQueue, searchIndex, notifier, webhookDispatcher, newsletterRunner, newsletterScheduler)
 			if err := e.Start(":" + cfg.APIPort); err != nil {
 				log.Printf("API server error: %v", err)
 			}
 		}()
+	} else if newsletterScheduler == nil {
+		fmt.Println("No API server enabled. Exiting.")
+		return
 	}
 
-	// If DebugSkipCron is set, skip the scheduler feature
-	if cfg.DebugSkipCron {
-		fmt.Println("DEBUG_SKIP_CRON is set: Skipping scheduler.")
-		// If API is enabled, we still need to keep the main thread alive
-		if cfg.EnableAPI {
-			fmt.Println("API server is running. Use Ctrl+C to stop.")
-			select {} // Block forever to keep API server running
-		} else {
-			fmt.Println("No API server enabled. Exiting.")
-			return
-		}
-	} else {
-		// Check newsletter configuration to see if cron should be enabled
-		newsletterConfig, err := db.GetNewsletterConfig()
-		if err != nil {
-			log.Printf("Warning: Failed to get newsletter configuration: %v", err)
-		}
-		
-		// If newsletter is disabled, skip cron but keep API running
-		if newsletterConfig != nil && !newsletterConfig.Enabled {
-			fmt.Println("Newsletter is disabled in configuration: Skipping scheduler.")
-			if cfg.EnableAPI {
-				fmt.Println("API server is running. Use Ctrl+C to stop.")
-				select {} // Block forever to keep API server running
-			} else {
-				fmt.Println("No API server enabled. Exiting.")
-				return
-			}
-		} else {
-			// Check if CronSchedule is set before starting cron
-			if cfg.CronSchedule == "" {
-				fmt.Println("No cron schedule configured. API server is running. Use Ctrl+C to stop.")
-				select {} // Block forever to keep API server running
-			} else {
-				// Use robfig/cron for scheduling if CronSchedule is set
-				fmt.Printf("Starting cron scheduler with schedule: %s\n", cfg.CronSchedule)
-				c := cron.New()
-				_, err = c.AddFunc(cfg.CronSchedule, func() {
-					checkForNewVideos(cfg, emailSender, channelProcessor, db)
-				})
-				if err != nil {
-					log.Fatalf("Failed to add cron job: %v", err)
-				}
-				c.Start()
-				select {} // Block forever
-			}
-		}
+	fmt.Println("Running. Use Ctrl+C to stop.")
+	<-ctx.Done() // Keep the API server and scheduler running until asked to stop
+
+	stop() // A second Ctrl+C exits immediately
+	fmt.Println("Shutting down...")
+	if newsletterScheduler != nil {
+		newsletterScheduler.Stop()
 	}
+	jobQueue.Wait()
 }
 
-func checkForNewVideos(cfg *config.Config, emailSender email.Sender, channelProcessor processor.ChannelProcessor, db store.Store) {
+// checkForNewVideos runs a scheduled newsletter run, stopping early when ctx is cancelled on shutdown
+func checkForNewVideos(ctx context.Context, runner *newsletter.Runner) {
 	log.Println("Checking for new videos...")
-	ctx := context.Background()
 
-	// Get channels from database instead of config
-	channels, err := db.GetChannels()
-	if err != nil {
-		log.Printf("Error getting channels from database: %v", err)
-		return
-	}
-
-	if len(channels) == 0 {
+	_, err := runner.Run(ctx, newsletter.Options{Trigger: store.RunTriggerScheduled})
+	if errors.Is(err, newsletter.ErrNoChannels) {
 		fmt.Println("No channels configured. Use the API to add channels.")
-		return
-	}
-
-	// Map to store the latest new video for each channel
-	latestNewVideoPerChannel := make(map[string]rss.Entry)
-
-	// Process channels concurrently using the configured concurrency level
-	results := processChannelsConcurrently(ctx, channels, channelProcessor, cfg.RSSConcurrency)
-
-	// Process results
-	for channelID, result := range results {
-		// Skip channels that had errors
-		if result.Error != nil {
-			log.Printf("Error processing channel %s: %v\n", channelID, result.Error)
-			continue
-		}
-
-		// If a new video was found, add it to our map
-		if result.NewVideo != nil {
-			latestNewVideoPerChannel[channelID] = *result.NewVideo
-		}
-	}
-
-	// Collect the latest new videos from the map into a slice
-	var videosToSendEmail []rss.Entry
-	for _, video := range latestNewVideoPerChannel {
-		videosToSendEmail = append(videosToSendEmail, video)
-	}
-
-	// Only send email if there are new videos from at least one channel
-	if len(videosToSendEmail) > 0 {
-		fmt.Printf("\nFound a total of %d new video(s) to email across all channels.\n", len(videosToSendEmail))
-
-		// Sort videos by published date (optional, but nice for the email)
-		sort.Slice(videosToSendEmail, func(i, j int) bool {
-			return videosToSendEmail[i].Published.Before(videosToSendEmail[j].Published)
-		})
-
-		emailBody, err := email.FormatNewVideosEmail(videosToSendEmail)
-		if err != nil {
-			log.Printf("Error formatting combined email: %v\n", err)
-		} else {
-			// Get SMTP config from database for recipient email
-			smtpConfig, err := db.GetSMTPConfig()
-			if err != nil || smtpConfig == nil || smtpConfig.RecipientEmail == "" {
-				// Fall back to environment variable
-				recipientEmail := cfg.RecipientEmail
-				if recipientEmail == "" {
-					log.Printf("Error: No recipient email configured\n")
-					return
-				}
-				subject := "New YouTube Videos Update"
-				if err := emailSender.Send(recipientEmail, subject, emailBody); err != nil {
-					log.Printf("Error sending combined email: %v\n", err)
-				} else {
-					fmt.Printf("Combined email sent successfully to %s\n", recipientEmail)
-				}
-			} else {
-				subject := "New YouTube Videos Update"
-				if err := emailSender.Send(smtpConfig.RecipientEmail, subject, emailBody); err != nil {
-					log.Printf("Error sending combined email: %v\n", err)
-				} else {
-					fmt.Printf("Combined email sent successfully to %s\n", smtpConfig.RecipientEmail)
-				}
-			}
-		}
-	} else {
-		fmt.Println("No new videos found across all channels since last check.")
+	} else if errors.Is(err, newsletter.ErrRunInProgress) {
+		log.Println("Skipping scheduled newsletter run: a manual run or outbox delivery is in progress")
+	} else if err != nil {
+		log.Printf("Error running newsletter: %v", err)
 	}
 
 	log.Println("Finished checking for new videos.")
 }
-
-// processChannelsConcurrently processes multiple channels concurrently using a worker pool
-// This improves RSS load times by fetching multiple feeds in parallel while respecting rate limits
-func processChannelsConcurrently(ctx context.Context, channels []store.Channel, channelProcessor processor.ChannelProcessor, concurrency int) map[string]processor.ChannelResult {
-	if len(channels) == 0 {
-		return make(map[string]processor.ChannelResult)
-	}
-
-	// Limit concurrency to number of channels if there are fewer channels than workers
-	if concurrency > len(channels) {
-		concurrency = len(channels)
-	}
-
-	// Safety check: Limit maximum concurrency to prevent overwhelming YouTube's servers
-	// which could trigger rate limiting
-	maxConcurrency := 10
-	if concurrency > maxConcurrency {
-		log.Printf("Warning: RSS_CONCURRENCY=%d exceeds recommended maximum of %d, limiting to %d",
-			concurrency, maxConcurrency, maxConcurrency)
-		concurrency = maxConcurrency
-	}
-
-	log.Printf("Processing %d channels with %d concurrent workers", len(channels), concurrency)
-
-	// Create channels for job distribution and result collection
-	jobs := make(chan channelJob, len(channels))
-	results := make(map[string]processor.ChannelResult)
-	var resultsMutex sync.Mutex
-
-	// Start worker goroutines
-	var wg sync.WaitGroup
-	for i := 0; i < concurrency; i++ {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-			log.Printf("Worker %d started", workerID)
-
-			for job := range jobs {
-				// Process the channel
-				result := channelProcessor.ProcessChannel(ctx, job.channelID)
-
-				// Store result safely
-				resultsMutex.Lock()
-				results[job.channelID] = result
-				resultsMutex.Unlock()
-
-				log.Printf("Worker %d processed channel %s", workerID, job.channelID)
-			}
-
-			log.Printf("Worker %d finished", workerID)
-		}(i)
-	}
-
-	// Send jobs to workers
-	go func() {
-		defer close(jobs)
-		for _, channel := range channels {
-			select {
-			case jobs <- channelJob{channelID: channel.ID}:
-			case <-ctx.Done():
-				log.Printf("Context cancelled while sending jobs")
-				return
-			}
-		}
-	}()
-
-	// Wait for all workers to complete
-	wg.Wait()
-
-	log.Printf("Completed processing %d channels", len(channels))
-	return results
-}
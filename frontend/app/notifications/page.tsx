@@ -1,14 +1,16 @@
 'use client';
 
 import { useState, useEffect } from 'react';
-import { newsletterAPI, channelAPI, configAPI } from '@/lib/api';
-import { Channel, SMTPConfigRequest, LLMConfigRequest, RunNewsletterRequest, NewsletterConfigRequest } from '@/lib/types';
+import { newsletterAPI, channelAPI, configAPI, schedulerAPI, outboxAPI } from '@/lib/api';
+import { Channel, SMTPConfigRequest, SMTPTLSMode, LLMConfigRequest, NewsletterEmail, NewsletterPreviewParams, RunNewsletterRequest, NewsletterConfigRequest, OutboxMessage, SchedulerStatus } from '@/lib/types';
 
 export default function NotificationsPage() {
   const [channels, setChannels] = useState<Channel[]>([]);
   const [selectedChannel, setSelectedChannel] = useState<string>('all');
   const [ignoreLastChecked, setIgnoreLastChecked] = useState<boolean>(false);
   const [maxItems, setMaxItems] = useState<number>(0);
+  const [dryRun, setDryRun] = useState<boolean>(false);
+  const [previewEmails, setPreviewEmails] = useState<NewsletterEmail[]>([]);
   const [isLoading, setIsLoading] = useState(false);
   const [isLoadingChannels, setIsLoadingChannels] = useState(true);
   const [result, setResult] = useState<{ type: 'success' | 'error', message: string } | null>(null);
@@ -19,12 +21,17 @@ export default function NotificationsPage() {
     port: '',
     username: '',
     password: '',
-    recipientEmail: ''
+    recipientEmail: '',
+    fromAddress: '',
+    fromName: '',
+    tlsMode: '',
+    insecureSkipVerify: false
   });
   const [isLoadingSMTP, setIsLoadingSMTP] = useState(true);
   const [isSavingSMTP, setIsSavingSMTP] = useState(false);
   const [smtpResult, setSMTPResult] = useState<{ type: 'success' | 'error', message: string } | null>(null);
   const [smtpPasswordSet, setSMTPPasswordSet] = useState(false);
+  const [isTestingSMTP, setIsTestingSMTP] = useState(false);
 
   // LLM Configuration State
   const [llmConfig, setLLMConfig] = useState<LLMConfigRequest>({
@@ -35,6 +42,7 @@ export default function NotificationsPage() {
   const [isLoadingLLM, setIsLoadingLLM] = useState(true);
   const [isSavingLLM, setIsSavingLLM] = useState(false);
   const [llmResult, setLLMResult] = useState<{ type: 'success' | 'error', message: string } | null>(null);
+  const [isTestingLLM, setIsTestingLLM] = useState(false);
   const [llmApiKeySet, setLLMApiKeySet] = useState(false);
 
   // Newsletter Configuration State
@@ -44,12 +52,19 @@ export default function NotificationsPage() {
   const [isLoadingNewsletter, setIsLoadingNewsletter] = useState(true);
   const [isSavingNewsletter, setIsSavingNewsletter] = useState(false);
   const [newsletterResult, setNewsletterResult] = useState<{ type: 'success' | 'error', message: string } | null>(null);
+  const [schedulerStatus, setSchedulerStatus] = useState<SchedulerStatus | null>(null);
+
+  // Dead-lettered newsletter emails
+  const [deadLetters, setDeadLetters] = useState<OutboxMessage[]>([]);
+  const [resendingId, setResendingId] = useState<string | null>(null);
 
   useEffect(() => {
     loadChannels();
     loadSMTPConfig();
     loadLLMConfig();
     loadNewsletterConfig();
+    loadSchedulerStatus();
+    loadDeadLetters();
   }, []);
 
   const loadChannels = async () => {
@@ -71,7 +86,11 @@ export default function NotificationsPage() {
         port: data.port || '',
         username: data.username || '',
         password: '', // Password is never returned from API
-        recipientEmail: data.recipientEmail || ''
+        recipientEmail: data.recipientEmail || '',
+        fromAddress: data.fromAddress || '',
+        fromName: data.fromName || '',
+        tlsMode: data.tlsMode || '',
+        insecureSkipVerify: data.insecureSkipVerify || false
       });
       setSMTPPasswordSet(data.passwordSet || false);
     } catch (error) {
@@ -101,7 +120,10 @@ export default function NotificationsPage() {
     try {
       const data = await configAPI.getNewsletter();
       setNewsletterConfig({
-        enabled: data.enabled
+        enabled: data.enabled,
+        maxVideosPerChannel: data.maxVideosPerChannel,
+        includeSummaries: data.includeSummaries,
+        cronSchedule: data.cronSchedule
       });
     } catch (error) {
       console.error('Failed to load newsletter configuration:', error);
@@ -110,9 +132,46 @@ export default function NotificationsPage() {
     }
   };
 
+  const loadSchedulerStatus = async () => {
+    try {
+      setSchedulerStatus(await schedulerAPI.getStatus());
+    } catch (error) {
+      // The scheduler isn't running when DEBUG_SKIP_CRON is set
+      setSchedulerStatus(null);
+    }
+  };
+
+  const loadDeadLetters = async () => {
+    try {
+      const data = await outboxAPI.getAll('dead');
+      setDeadLetters(data.messages);
+    } catch (error) {
+      console.error('Failed to load undelivered emails:', error);
+    }
+  };
+
+  const handleResend = async (messageId: string) => {
+    setResendingId(messageId);
+    try {
+      const message = await outboxAPI.resend(messageId);
+      setResult(message.status === 'sent'
+        ? { type: 'success', message: `Email resent to ${message.recipient}.` }
+        : { type: 'error', message: `Resending to ${message.recipient} failed: ${message.error}` });
+    } catch (error) {
+      setResult({
+        type: 'error',
+        message: error instanceof Error ? error.message : 'Failed to resend email'
+      });
+    } finally {
+      setResendingId(null);
+      loadDeadLetters();
+    }
+  };
+
   const handleRunNewsletter = async () => {
     setIsLoading(true);
     setResult(null);
+    setPreviewEmails([]);
 
     try {
       const request: RunNewsletterRequest = {};
@@ -125,8 +184,18 @@ export default function NotificationsPage() {
       if (maxItems > 0) {
         request.maxItems = maxItems;
       }
+      if (dryRun) {
+        request.dryRun = true;
+      }
       
       const response = await newsletterAPI.run(request);
+
+      if (response.dryRun) {
+        const emails = response.emails || [];
+        setPreviewEmails(emails);
+        setResult({ type: 'success', message: `Dry run completed, no emails were sent.\nProcessed ${response.channelsProcessed} channel(s), found ${response.newVideosFound} new video(s), rendered ${emails.length} email(s).` });
+        return;
+      }
       
       const successMessage = `Newsletter run completed successfully!\nProcessed ${response.channelsProcessed} channel(s), found ${response.newVideosFound} new video(s).\n${response.emailSent ? 'Email sent.' : 'No email sent (no new videos).'}`;
       
@@ -136,6 +205,44 @@ export default function NotificationsPage() {
         type: 'error', 
         message: error instanceof Error ? error.message : 'Failed to run newsletter' 
       });
+    } finally {
+      setIsLoading(false);
+      if (!dryRun) {
+        loadDeadLetters();
+      }
+    }
+  };
+
+  const handlePreviewNewsletter = async () => {
+    setIsLoading(true);
+    setResult(null);
+    setPreviewEmails([]);
+
+    try {
+      const params: NewsletterPreviewParams = {};
+      if (selectedChannel !== 'all') {
+        params.channelId = selectedChannel;
+      }
+      if (ignoreLastChecked) {
+        params.ignoreLastChecked = true;
+      }
+      if (maxItems > 0) {
+        params.maxItems = maxItems;
+      }
+
+      const preview = await newsletterAPI.preview(params);
+      setPreviewEmails(preview.emails);
+      setResult({
+        type: 'success',
+        message: preview.emails.length > 0
+          ? `Preview of the next newsletter: ${preview.newVideosFound} new video(s), ${preview.emails.length} email(s).`
+          : 'No new videos since the last check, the next newsletter would be empty.'
+      });
+    } catch (error) {
+      setResult({
+        type: 'error',
+        message: error instanceof Error ? error.message : 'Failed to preview newsletter'
+      });
     } finally {
       setIsLoading(false);
     }
@@ -160,6 +267,25 @@ export default function NotificationsPage() {
     }
   };
 
+  const handleTestSMTP = async () => {
+    setIsTestingSMTP(true);
+    setSMTPResult(null);
+
+    try {
+      const result = await configAPI.testSMTP(smtpConfig);
+      setSMTPResult(result.success
+        ? { type: 'success', message: `${result.message} (${result.durationMs} ms)` }
+        : { type: 'error', message: `${result.message}: ${result.error}` });
+    } catch (error) {
+      setSMTPResult({
+        type: 'error',
+        message: error instanceof Error ? error.message : 'Failed to send test email'
+      });
+    } finally {
+      setIsTestingSMTP(false);
+    }
+  };
+
   const handleSaveLLM = async (e: React.FormEvent) => {
     e.preventDefault();
     setIsSavingLLM(true);
@@ -179,6 +305,25 @@ export default function NotificationsPage() {
     }
   };
 
+  const handleTestLLM = async () => {
+    setIsTestingLLM(true);
+    setLLMResult(null);
+
+    try {
+      const result = await configAPI.testLLM(llmConfig);
+      setLLMResult(result.success
+        ? { type: 'success', message: `${result.model} replied in ${result.latencyMs} ms` }
+        : { type: 'error', message: `${result.model} failed (${result.errorCategory}): ${result.error}` });
+    } catch (error) {
+      setLLMResult({
+        type: 'error',
+        message: error instanceof Error ? error.message : 'Failed to test LLM configuration'
+      });
+    } finally {
+      setIsTestingLLM(false);
+    }
+  };
+
   const handleSaveNewsletter = async (e: React.FormEvent) => {
     e.preventDefault();
     setIsSavingNewsletter(true);
@@ -190,6 +335,7 @@ export default function NotificationsPage() {
         type: 'success', 
         message: `Newsletter ${newsletterConfig.enabled ? 'enabled' : 'disabled'} successfully!` 
       });
+      loadSchedulerStatus();
     } catch (error) {
       setNewsletterResult({ 
         type: 'error', 
@@ -274,6 +420,13 @@ export default function NotificationsPage() {
                 </>
               )}
             </button>
+            <button
+              onClick={handlePreviewNewsletter}
+              disabled={isLoading || isLoadingChannels}
+              className="px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed transition-colors"
+            >
+              Preview
+            </button>
             
           </div>
           <div className="flex items-center gap-2">
@@ -289,10 +442,24 @@ export default function NotificationsPage() {
               Ignore last checked
             </label>
           </div>
+          <div className="flex items-center gap-2">
+            <input
+              id="dry-run"
+              type="checkbox"
+              checked={dryRun}
+              onChange={(e) => setDryRun(e.target.checked)}
+              disabled={isLoading || isLoadingChannels}
+              className="h-4 w-4 text-blue-600 focus:ring-blue-500 border-gray-300 dark:border-gray-600 rounded disabled:opacity-50 disabled:cursor-not-allowed"
+            />
+            <label htmlFor="dry-run" className="text-sm font-medium">
+              Dry run
+            </label>
+          </div>
 
           <div className="text-sm text-gray-600 dark:text-gray-400 space-y-2">
             <p><strong>Ignore last checked:</strong> When enabled, this will process all videos in the RSS feed regardless of when they were last checked. This is useful for debugging and testing to see all available videos.</p>
             <p><strong>Max Items:</strong> Limits the number of new videos to process per channel. Set to 0 to process all new videos. This can help reduce processing time for channels with many videos.</p>
+            <p><strong>Dry run / Preview:</strong> Renders the emails below instead of sending them. The videos are still included in the next newsletter.</p>
           </div>
 
           {result && (
@@ -333,9 +500,58 @@ export default function NotificationsPage() {
               </div>
             </div>
           )}
+
+          {previewEmails.map((previewEmail) => (
+            <div key={previewEmail.recipient || 'all'} className="border border-gray-200 dark:border-gray-700 rounded-md">
+              <div className="px-4 py-2 border-b border-gray-200 dark:border-gray-700 text-sm">
+                <span className="font-medium">{previewEmail.subject}</span>
+                <span className="text-gray-600 dark:text-gray-400"> to {previewEmail.recipient || 'all subscribers'} ({previewEmail.videos} video(s))</span>
+              </div>
+              <iframe
+                title={`Newsletter preview for ${previewEmail.recipient}`}
+                srcDoc={previewEmail.html}
+                sandbox=""
+                className="w-full h-96 bg-white rounded-b-md"
+              />
+            </div>
+          ))}
         </div>
       </div>
       
+      {/* Undelivered Emails */}
+      {deadLetters.length > 0 && (
+        <div className="bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-700 rounded-lg shadow-sm mb-6">
+          <div className="p-6 border-b border-gray-200 dark:border-gray-700">
+            <h2 className="text-xl font-semibold mb-2">Undelivered Emails</h2>
+            <p className="text-gray-600 dark:text-gray-400">
+              These emails could not be delivered after retrying. Their videos won&apos;t be sent again by the next run, so resend them once the problem is fixed.
+            </p>
+          </div>
+          <ul className="divide-y divide-gray-200 dark:divide-gray-700">
+            {deadLetters.map((message) => (
+              <li key={message.id} className="p-4 flex items-start justify-between gap-4">
+                <div className="min-w-0">
+                  <p className="font-medium">{message.recipient}</p>
+                  <p className="text-sm text-gray-600 dark:text-gray-400">
+                    {message.videos} video(s) · {new Date(message.createdAt).toLocaleString()} · {message.attempts} attempt(s)
+                  </p>
+                  {message.error && (
+                    <p className="text-sm text-red-600 dark:text-red-400 mt-1 break-words">{message.error}</p>
+                  )}
+                </div>
+                <button
+                  onClick={() => handleResend(message.id)}
+                  disabled={resendingId !== null}
+                  className="px-3 py-1.5 text-sm bg-blue-600 text-white rounded-md hover:bg-blue-700 disabled:opacity-50 disabled:cursor-not-allowed"
+                >
+                  {resendingId === message.id ? 'Resending...' : 'Resend'}
+                </button>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
       {/* Newsletter Configuration */}
       <div className="bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-700 rounded-lg shadow-sm mb-6">
         <div className="p-6 border-b border-gray-200 dark:border-gray-700">
@@ -375,7 +591,7 @@ export default function NotificationsPage() {
                     <input
                       type="checkbox"
                       checked={newsletterConfig.enabled}
-                      onChange={(e) => setNewsletterConfig({ enabled: e.target.checked })}
+                      onChange={(e) => setNewsletterConfig({ ...newsletterConfig, enabled: e.target.checked })}
                       className="sr-only peer"
                     />
                     <div className="w-11 h-6 bg-gray-200 peer-focus:outline-none peer-focus:ring-4 peer-focus:ring-blue-300 dark:peer-focus:ring-blue-800 rounded-full peer dark:bg-gray-700 peer-checked:after:translate-x-full peer-checked:after:border-white after:content-[''] after:absolute after:top-[2px] after:left-[2px] after:bg-white after:border-gray-300 after:border after:rounded-full after:h-5 after:w-5 after:transition-all dark:border-gray-600 peer-checked:bg-blue-600"></div>
@@ -383,6 +599,48 @@ export default function NotificationsPage() {
                 </div>
               </div>
 
+              <div>
+                <label htmlFor="cron-schedule" className="block text-sm font-medium mb-2">
+                  Cron Schedule
+                </label>
+                <input
+                  id="cron-schedule"
+                  type="text"
+                  value={newsletterConfig.cronSchedule ?? ''}
+                  onChange={(e) => setNewsletterConfig({ ...newsletterConfig, cronSchedule: e.target.value })}
+                  placeholder="e.g. 30 7 * * 1-5"
+                  className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
+                />
+                <p className="text-sm text-gray-600 dark:text-gray-400 mt-1">
+                  Optional. Overrides the check interval when set. Changes apply immediately.
+                </p>
+                {schedulerStatus && (
+                  <p className="text-sm text-gray-600 dark:text-gray-400 mt-1">
+                    {schedulerStatus.error
+                      ? `Schedule error: ${schedulerStatus.error}`
+                      : schedulerStatus.nextRun
+                        ? `Next run: ${new Date(schedulerStatus.nextRun).toLocaleString()} (${schedulerStatus.schedule})`
+                        : 'No run scheduled.'}
+                    {schedulerStatus.lastRun && ` · Last run: ${new Date(schedulerStatus.lastRun).toLocaleString()}`}
+                  </p>
+                )}
+              </div>
+
+              <label className="flex items-start gap-3 p-4 bg-gray-50 dark:bg-gray-700/50 rounded-lg cursor-pointer">
+                <input
+                  type="checkbox"
+                  checked={newsletterConfig.includeSummaries ?? false}
+                  onChange={(e) => setNewsletterConfig({ ...newsletterConfig, includeSummaries: e.target.checked })}
+                  className="mt-1 h-4 w-4 rounded border-gray-300 text-blue-600 focus:ring-blue-500"
+                />
+                <div>
+                  <span className="font-medium">Include AI summaries</span>
+                  <p className="text-sm text-gray-600 dark:text-gray-400 mt-1">
+                    Summarise each new video in the email using the configured LLM. Videos without subtitles show their description instead.
+                  </p>
+                </div>
+              </label>
+
               {!newsletterConfig.enabled && (
                 <div className="p-4 bg-yellow-50 dark:bg-yellow-900/20 border border-yellow-300 dark:border-yellow-700 rounded-lg">
                   <div className="flex items-start gap-3">
@@ -518,8 +776,10 @@ export default function NotificationsPage() {
                     onChange={(e) => setSMTPConfig({ ...smtpConfig, username: e.target.value })}
                     placeholder="your-email@example.com"
                     className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
-                    required
                   />
+                  <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+                    Leave blank for an unauthenticated relay
+                  </p>
                 </div>
                 
                 <div>
@@ -533,7 +793,7 @@ export default function NotificationsPage() {
                     onChange={(e) => setSMTPConfig({ ...smtpConfig, password: e.target.value })}
                     placeholder={smtpPasswordSet ? "••••••••" : "Enter password"}
                     className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
-                    required
+                    required={smtpConfig.username !== ''}
                   />
                   <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
                     {smtpPasswordSet ? "Password is already configured. Leave blank to keep current password." : "For Gmail, use an app-specific password"}
@@ -541,6 +801,72 @@ export default function NotificationsPage() {
                 </div>
               </div>
 
+              <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+                <div>
+                  <label htmlFor="smtp-from-address" className="block text-sm font-medium mb-2">
+                    From Address
+                  </label>
+                  <input
+                    id="smtp-from-address"
+                    type="email"
+                    value={smtpConfig.fromAddress}
+                    onChange={(e) => setSMTPConfig({ ...smtpConfig, fromAddress: e.target.value })}
+                    placeholder="curator@example.com"
+                    className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
+                    required={smtpConfig.username === ''}
+                  />
+                  <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+                    Defaults to the username
+                  </p>
+                </div>
+
+                <div>
+                  <label htmlFor="smtp-from-name" className="block text-sm font-medium mb-2">
+                    From Name
+                  </label>
+                  <input
+                    id="smtp-from-name"
+                    type="text"
+                    value={smtpConfig.fromName}
+                    onChange={(e) => setSMTPConfig({ ...smtpConfig, fromName: e.target.value })}
+                    placeholder="YouTube Curator"
+                    className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
+                  />
+                </div>
+              </div>
+
+              <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+                <div>
+                  <label htmlFor="smtp-tls-mode" className="block text-sm font-medium mb-2">
+                    Encryption
+                  </label>
+                  <select
+                    id="smtp-tls-mode"
+                    value={smtpConfig.tlsMode}
+                    onChange={(e) => setSMTPConfig({ ...smtpConfig, tlsMode: e.target.value as SMTPTLSMode })}
+                    className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-md shadow-sm bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-blue-500"
+                  >
+                    <option value="">Automatic (TLS on 465, STARTTLS when offered)</option>
+                    <option value="starttls">STARTTLS (required)</option>
+                    <option value="implicit">Implicit TLS</option>
+                    <option value="none">None</option>
+                  </select>
+                </div>
+
+                <div className="flex items-center gap-2 md:mt-8">
+                  <input
+                    id="smtp-insecure-skip-verify"
+                    type="checkbox"
+                    checked={smtpConfig.insecureSkipVerify || false}
+                    onChange={(e) => setSMTPConfig({ ...smtpConfig, insecureSkipVerify: e.target.checked })}
+                    className="h-4 w-4"
+                  />
+                  <label htmlFor="smtp-insecure-skip-verify" className="text-sm">
+                    Skip certificate verification (self-hosted relays)
+                  </label>
+                </div>
+              </div>
+
               <div>
                 <label htmlFor="recipient-email" className="block text-sm font-medium mb-2">
                   Recipient Email
@@ -591,23 +917,33 @@ export default function NotificationsPage() {
                 </div>
               )}
 
-              <button
-                type="submit"
-                disabled={isSavingSMTP}
-                className="px-4 py-2 bg-blue-600 text-white rounded-md hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed transition-colors flex items-center gap-2"
-              >
-                {isSavingSMTP ? (
-                  <>
-                    <svg className="animate-spin h-4 w-4" xmlns="http://www.w3.org/2000/svg" fill="none" viewBox="0 0 24 24">
-                      <circle className="opacity-25" cx="12" cy="12" r="10" stroke="currentColor" strokeWidth="4"></circle>
-                      <path className="opacity-75" fill="currentColor" d="M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4zm2 5.291A7.962 7.962 0 014 12H0c0 3.042 1.135 5.824 3 7.938l3-2.647z"></path>
-                    </svg>
-                    Saving...
-                  </>
-                ) : (
-                  'Save Configuration'
-                )}
-              </button>
+              <div className="flex gap-3">
+                <button
+                  type="submit"
+                  disabled={isSavingSMTP}
+                  className="px-4 py-2 bg-blue-600 text-white rounded-md hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed transition-colors flex items-center gap-2"
+                >
+                  {isSavingSMTP ? (
+                    <>
+                      <svg className="animate-spin h-4 w-4" xmlns="http://www.w3.org/2000/svg" fill="none" viewBox="0 0 24 24">
+                        <circle className="opacity-25" cx="12" cy="12" r="10" stroke="currentColor" strokeWidth="4"></circle>
+                        <path className="opacity-75" fill="currentColor" d="M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4zm2 5.291A7.962 7.962 0 014 12H0c0 3.042 1.135 5.824 3 7.938l3-2.647z"></path>
+                      </svg>
+                      Saving...
+                    </>
+                  ) : (
+                    'Save Configuration'
+                  )}
+                </button>
+                <button
+                  type="button"
+                  onClick={handleTestSMTP}
+                  disabled={isTestingSMTP}
+                  className="px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed transition-colors"
+                >
+                  {isTestingSMTP ? 'Testing...' : 'Send Test Email'}
+                </button>
+              </div>
             </form>
           )}
         </div>
@@ -719,23 +1055,33 @@ export default function NotificationsPage() {
                 </div>
               )}
 
-              <button
-                type="submit"
-                disabled={isSavingLLM}
-                className="px-4 py-2 bg-blue-600 text-white rounded-md hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed transition-colors flex items-center gap-2"
-              >
-                {isSavingLLM ? (
-                  <>
-                    <svg className="animate-spin h-4 w-4" xmlns="http://www.w3.org/2000/svg" fill="none" viewBox="0 0 24 24">
-                      <circle className="opacity-25" cx="12" cy="12" r="10" stroke="currentColor" strokeWidth="4"></circle>
-                      <path className="opacity-75" fill="currentColor" d="M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4zm2 5.291A7.962 7.962 0 014 12H0c0 3.042 1.135 5.824 3 7.938l3-2.647z"></path>
-                    </svg>
-                    Saving...
-                  </>
-                ) : (
-                  'Save Configuration'
-                )}
-              </button>
+              <div className="flex gap-3">
+                <button
+                  type="submit"
+                  disabled={isSavingLLM}
+                  className="px-4 py-2 bg-blue-600 text-white rounded-md hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed transition-colors flex items-center gap-2"
+                >
+                  {isSavingLLM ? (
+                    <>
+                      <svg className="animate-spin h-4 w-4" xmlns="http://www.w3.org/2000/svg" fill="none" viewBox="0 0 24 24">
+                        <circle className="opacity-25" cx="12" cy="12" r="10" stroke="currentColor" strokeWidth="4"></circle>
+                        <path className="opacity-75" fill="currentColor" d="M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4zm2 5.291A7.962 7.962 0 014 12H0c0 3.042 1.135 5.824 3 7.938l3-2.647z"></path>
+                      </svg>
+                      Saving...
+                    </>
+                  ) : (
+                    'Save Configuration'
+                  )}
+                </button>
+                <button
+                  type="button"
+                  onClick={handleTestLLM}
+                  disabled={isTestingLLM}
+                  className="px-4 py-2 border border-gray-300 dark:border-gray-600 rounded-md hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-blue-500 focus:ring-offset-2 disabled:opacity-50 disabled:cursor-not-allowed transition-colors"
+                >
+                  {isTestingLLM ? 'Testing...' : 'Test Connection'}
+                </button>
+              </div>
             </form>
           )}
         </div>
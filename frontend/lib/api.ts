@@ -1,5 +1,5 @@
 import axios from 'axios';
-import { Channel, ChannelRequest, ConfigInterval, ImportChannelsRequest, ImportChannelsResponse, LLMConfigRequest, LLMConfigResponse, NewsletterConfigRequest, NewsletterConfigResponse, RunNewsletterRequest, RunNewsletterResponse, SMTPConfigRequest, SMTPConfigResponse, VideosAPIResponse, VideoSummaryResponse } from './types';
+import { Channel, ChannelRequest, ConfigInterval, FeedOptions, ImportChannelsRequest, ImportChannelsResponse, LLMConfigRequest, LLMConfigResponse, LLMTestResponse, NewsletterConfigRequest, NewsletterConfigResponse, NewsletterPreview, NewsletterPreviewParams, NotifyTestResponse, OutboxMessage, OutboxMessagesResponse, OutboxStatus, Run, RunNewsletterRequest, RunNewsletterResponse, RunsResponse, SMTPConfigRequest, SMTPConfigResponse, SMTPTestResponse, SchedulerStatus, Subscriber, SubscriberRequest, SubscribersResponse, VideosAPIResponse, VideoSummaryResponse, Webhook, WebhookDeliveriesResponse, WebhookDelivery, WebhookRequest, WebhooksResponse } from './types';
 import { getRuntimeConfig } from './config';
 
 // Create axios instance that will be configured with runtime config
@@ -82,6 +82,35 @@ export const channelAPI = {
       return data;
     });
   },
+
+  importOPML: async (file: File): Promise<ImportChannelsResponse> => {
+    return makeRequest(async () => {
+      const form = new FormData();
+      form.append('file', file);
+      const { data } = await api.post('/channels/import/opml', form, {
+        headers: { 'Content-Type': 'multipart/form-data' },
+      });
+      return data;
+    });
+  },
+
+  importTakeout: async (file: File): Promise<ImportChannelsResponse> => {
+    return makeRequest(async () => {
+      const form = new FormData();
+      form.append('file', file);
+      const { data } = await api.post('/channels/import/takeout', form, {
+        headers: { 'Content-Type': 'multipart/form-data' },
+      });
+      return data;
+    });
+  },
+
+  exportOPML: async (): Promise<Blob> => {
+    return makeRequest(async () => {
+      const { data } = await api.get('/channels/export/opml', { responseType: 'blob' });
+      return data;
+    });
+  },
 };
 
 // Configuration APIs
@@ -114,6 +143,13 @@ export const configAPI = {
     });
   },
 
+  testSMTP: async (config: SMTPConfigRequest): Promise<SMTPTestResponse> => {
+    return makeRequest(async () => {
+      const { data } = await api.post('/config/smtp/test', config);
+      return data;
+    });
+  },
+
   getLLM: async (): Promise<LLMConfigResponse> => {
     return makeRequest(async () => {
       const { data } = await api.get('/config/llm');
@@ -128,6 +164,13 @@ export const configAPI = {
     });
   },
 
+  testLLM: async (config: LLMConfigRequest): Promise<LLMTestResponse> => {
+    return makeRequest(async () => {
+      const { data } = await api.post('/config/llm/test', config);
+      return data;
+    });
+  },
+
   getNewsletter: async (): Promise<NewsletterConfigResponse> => {
     return makeRequest(async () => {
       const { data } = await api.get('/config/newsletter');
@@ -151,6 +194,162 @@ export const newsletterAPI = {
       return data;
     });
   },
+
+  preview: async (params?: NewsletterPreviewParams): Promise<NewsletterPreview> => {
+    return makeRequest(async () => {
+      const { data } = await api.get('/newsletter/preview', { params });
+      return data;
+    });
+  },
+};
+
+// Scheduler APIs
+export const schedulerAPI = {
+  getStatus: async (): Promise<SchedulerStatus> => {
+    return makeRequest(async () => {
+      const { data } = await api.get('/scheduler');
+      return data;
+    });
+  },
+};
+
+// Newsletter run history APIs
+export const runAPI = {
+  getAll: async (limit?: number): Promise<RunsResponse> => {
+    return makeRequest(async () => {
+      const { data } = await api.get('/runs', { params: limit ? { limit } : undefined });
+      return data;
+    });
+  },
+
+  getById: async (runId: string): Promise<Run> => {
+    return makeRequest(async () => {
+      const { data } = await api.get(`/runs/${runId}`);
+      return data;
+    });
+  },
+};
+
+// Newsletter outbox APIs
+export const outboxAPI = {
+  getAll: async (status?: OutboxStatus): Promise<OutboxMessagesResponse> => {
+    return makeRequest(async () => {
+      const { data } = await api.get('/outbox', { params: status ? { status } : undefined });
+      return data;
+    });
+  },
+
+  getById: async (messageId: string): Promise<OutboxMessage> => {
+    return makeRequest(async () => {
+      const { data } = await api.get(`/outbox/${messageId}`);
+      return data;
+    });
+  },
+
+  resend: async (messageId: string): Promise<OutboxMessage> => {
+    return makeRequest(async () => {
+      const { data } = await api.post(`/outbox/${messageId}/resend`);
+      return data;
+    });
+  },
+};
+
+// Subscriber APIs
+export const subscriberAPI = {
+  getAll: async (): Promise<SubscribersResponse> => {
+    return makeRequest(async () => {
+      const { data } = await api.get('/subscribers');
+      return data;
+    });
+  },
+
+  create: async (request: SubscriberRequest): Promise<Subscriber> => {
+    return makeRequest(async () => {
+      const { data } = await api.post('/subscribers', request);
+      return data;
+    });
+  },
+
+  update: async (id: string, request: SubscriberRequest): Promise<Subscriber> => {
+    return makeRequest(async () => {
+      const { data } = await api.put(`/subscribers/${id}`, request);
+      return data;
+    });
+  },
+
+  remove: async (id: string): Promise<void> => {
+    return makeRequest(async () => {
+      await api.delete(`/subscribers/${id}`);
+    });
+  },
+
+  test: async (id: string): Promise<NotifyTestResponse> => {
+    return makeRequest(async () => {
+      const { data } = await api.post(`/subscribers/${id}/test`);
+      return data;
+    });
+  },
+};
+
+export const webhookAPI = {
+  getAll: async (): Promise<WebhooksResponse> => {
+    return makeRequest(async () => {
+      const { data } = await api.get('/webhooks');
+      return data;
+    });
+  },
+
+  create: async (request: WebhookRequest): Promise<Webhook> => {
+    return makeRequest(async () => {
+      const { data } = await api.post('/webhooks', request);
+      return data;
+    });
+  },
+
+  update: async (id: string, request: WebhookRequest): Promise<Webhook> => {
+    return makeRequest(async () => {
+      const { data } = await api.put(`/webhooks/${id}`, request);
+      return data;
+    });
+  },
+
+  remove: async (id: string): Promise<void> => {
+    return makeRequest(async () => {
+      await api.delete(`/webhooks/${id}`);
+    });
+  },
+
+  getDeliveries: async (id: string, limit?: number): Promise<WebhookDeliveriesResponse> => {
+    return makeRequest(async () => {
+      const { data } = await api.get(`/webhooks/${id}/deliveries`, { params: limit ? { limit } : undefined });
+      return data;
+    });
+  },
+
+  redeliver: async (id: string, deliveryId: string): Promise<WebhookDelivery> => {
+    return makeRequest(async () => {
+      const { data } = await api.post(`/webhooks/${id}/deliveries/${deliveryId}/redeliver`);
+      return data;
+    });
+  },
+};
+
+// Feeds are served next to the API rather than under it, so readers get URLs without /api
+export const feedAPI = {
+  urls: async (options: FeedOptions = {}, channelId?: string): Promise<{ atom: string; json: string; channelAtom?: string }> => {
+    const config = await getRuntimeConfig();
+    const base = config.apiUrl.replace(/\/api\/?$/, '');
+    const params = new URLSearchParams();
+    if (options.summaries) params.set('summaries', 'true');
+    if (options.excludeWatched) params.set('excludeWatched', 'true');
+    if (options.limit) params.set('limit', String(options.limit));
+    const query = params.toString() ? `?${params}` : '';
+    return {
+      atom: `${base}/feeds/all.atom${query}`,
+      json: `${base}/feeds/all.json${query}`,
+      channelAtom: channelId ? `${base}/feeds/channel/${channelId}.atom${query}` : undefined,
+    };
+  },
 };
 
 // Helper function to extract raw video ID from full format
@@ -31,12 +31,18 @@ export interface ConfigInterval {
   interval: string;
 }
 
+export type SMTPTLSMode = '' | 'none' | 'starttls' | 'implicit';
+
 export interface SMTPConfigRequest {
   server: string;
   port: string;
   username: string;
   password: string;
   recipientEmail: string;
+  fromAddress?: string;
+  fromName?: string;
+  tlsMode?: SMTPTLSMode;
+  insecureSkipVerify?: boolean;
 }
 
 export interface SMTPConfigResponse {
@@ -45,6 +51,27 @@ export interface SMTPConfigResponse {
   username: string;
   recipientEmail: string;
   passwordSet: boolean;
+  fromAddress: string;
+  fromName: string;
+  tlsMode: SMTPTLSMode;
+  insecureSkipVerify: boolean;
+}
+
+export interface SMTPTestResponse {
+  success: boolean;
+  message: string;
+  recipient: string;
+  durationMs: number;
+  error?: string;
+}
+
+export interface LLMTestResponse {
+  success: boolean;
+  model: string;
+  latencyMs: number;
+  reply?: string;
+  error?: string;
+  errorCategory?: string;
 }
 
 export interface LLMConfigRequest {
@@ -61,10 +88,27 @@ export interface LLMConfigResponse {
 
 export interface NewsletterConfigRequest {
   enabled: boolean;
+  maxVideosPerChannel?: number;
+  includeSummaries?: boolean;
+  cronSchedule?: string;
 }
 
 export interface NewsletterConfigResponse {
   enabled: boolean;
+  maxVideosPerChannel: number;
+  includeSummaries: boolean;
+  cronSchedule?: string;
+}
+
+export interface SchedulerStatus {
+  enabled: boolean;
+  schedule: string;
+  source: 'cron' | 'interval' | 'environment';
+  nextRun?: string;
+  lastRun?: string;
+  lastRunFinished?: string;
+  running: boolean;
+  error?: string;
 }
 
 export interface ApiError {
@@ -75,6 +119,36 @@ export interface RunNewsletterRequest {
   channelId?: string;
   ignoreLastChecked?: boolean;
   maxItems?: number;
+  maxPerChannel?: number;
+  dryRun?: boolean;
+}
+
+export interface NewsletterEmail {
+  recipient: string;
+  subject: string;
+  videos: number;
+  html: string;
+  text: string;
+}
+
+export interface NewsletterPreviewParams {
+  channelId?: string;
+  ignoreLastChecked?: boolean;
+  maxItems?: number;
+  maxPerChannel?: number;
+  recipient?: string;
+}
+
+export interface NewsletterPreview {
+  channelsProcessed: number;
+  channelsWithError: number;
+  newVideosFound: number;
+  recipient?: string;
+  subject?: string;
+  videos: number;
+  html: string;
+  text: string;
+  emails: NewsletterEmail[];
 }
 
 export interface RunNewsletterResponse {
@@ -83,6 +157,175 @@ export interface RunNewsletterResponse {
   channelsWithError: number;
   newVideosFound: number;
   emailSent: boolean;
+  emailsSent: number;
+  emailsFailed: number;
+  runId?: string;
+  dryRun?: boolean;
+  emails?: NewsletterEmail[];
+}
+
+// Newsletter run history types
+export interface RunChannel {
+  channelId: string;
+  channelTitle?: string;
+  newVideos: number;
+  error?: string;
+}
+
+export interface RunEmail {
+  recipient: string;
+  videos: number;
+  status: 'sent' | 'failed' | 'skipped' | 'pending';
+  error?: string;
+  outboxId?: string;
+}
+
+export interface Run {
+  id: string;
+  trigger: 'scheduled' | 'manual';
+  status: 'running' | 'succeeded' | 'failed';
+  error?: string;
+  startedAt: string;
+  finishedAt?: string;
+  channelsProcessed: number;
+  channelsWithError: number;
+  newVideosFound: number;
+  emailsSent: number;
+  emailsFailed: number;
+  channels: RunChannel[];
+  emails: RunEmail[];
+}
+
+export interface RunsResponse {
+  runs: Run[];
+  totalCount: number;
+}
+
+// Newsletter outbox types
+export type OutboxStatus = 'pending' | 'sent' | 'dead';
+
+export interface OutboxMessage {
+  id: string;
+  runId?: string;
+  recipient: string;
+  subject: string;
+  html?: string; // Only included when fetching a single message
+  text?: string;
+  videos: number;
+  status: OutboxStatus;
+  attempts: number;
+  error?: string;
+  createdAt: string;
+  updatedAt: string;
+  sentAt?: string;
+}
+
+export interface OutboxMessagesResponse {
+  messages: OutboxMessage[];
+  totalCount: number;
+}
+
+// Newsletter subscriber types
+export type NotifyType = 'email' | 'webhook' | 'slack' | 'discord' | 'ntfy' | 'gotify';
+
+export interface NotifyTargetRequest {
+  type: NotifyType;
+  url?: string;
+  secret?: string; // Blank keeps the saved secret for the same URL
+  token?: string; // Blank keeps the saved token for the same URL
+}
+
+export interface NotifyTarget {
+  type: NotifyType;
+  url?: string;
+  secretSet: boolean;
+  tokenSet: boolean;
+}
+
+export interface SubscriberRequest {
+  email: string;
+  channelIds?: string[];
+  tags?: string[];
+  enabled?: boolean;
+  notify?: NotifyTargetRequest;
+}
+
+export interface Subscriber {
+  id: string;
+  email: string;
+  channelIds: string[];
+  tags: string[];
+  enabled: boolean;
+  notify: NotifyTarget;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface NotifyTestResponse {
+  success: boolean;
+  message: string;
+  type: NotifyType;
+  durationMs: number;
+  error?: string;
+}
+
+export interface SubscribersResponse {
+  subscribers: Subscriber[];
+  totalCount: number;
+}
+
+// Outgoing webhook types
+export type WebhookEvent = 'video.discovered' | 'summary.generated' | 'newsletter.sent';
+
+export interface WebhookRequest {
+  url: string;
+  secret?: string; // Blank keeps the saved secret for the same URL
+  events: WebhookEvent[];
+  enabled?: boolean;
+}
+
+export interface Webhook {
+  id: string;
+  url: string;
+  secretSet: boolean;
+  events: WebhookEvent[];
+  enabled: boolean;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface WebhooksResponse {
+  webhooks: Webhook[];
+  totalCount: number;
+}
+
+export type WebhookDeliveryStatus = 'pending' | 'delivered' | 'failed';
+
+export interface WebhookDelivery {
+  id: string;
+  webhookId: string;
+  event: WebhookEvent;
+  url: string;
+  payload: unknown; // The JSON body that was posted
+  status: WebhookDeliveryStatus;
+  attempts: number;
+  statusCode?: number; // HTTP status of the last failed attempt
+  error?: string;
+  createdAt: string;
+  updatedAt: string;
+  deliveredAt?: string;
+}
+
+export interface WebhookDeliveriesResponse {
+  deliveries: WebhookDelivery[];
+  totalCount: number;
+}
+
+// Feed types
+export interface FeedOptions {
+  summaries?: boolean; // Include stored summaries as item content
+  excludeWatched?: boolean;
+  limit?: number;
 }
 
 // RSS Entry types